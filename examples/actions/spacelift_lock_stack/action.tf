@@ -0,0 +1,28 @@
+resource "terraform_data" "maintenance" {
+  input = var.maintenance_window
+
+  lifecycle {
+    action_trigger {
+      events  = [before_update]
+      actions = [action.spacelift_lock_stack.k8s_core]
+    }
+
+    action_trigger {
+      events  = [after_update]
+      actions = [action.spacelift_unlock_stack.k8s_core]
+    }
+  }
+}
+
+action "spacelift_lock_stack" "k8s_core" {
+  config {
+    stack_id = "k8s-core"
+    note     = "Cluster maintenance in progress"
+  }
+}
+
+action "spacelift_unlock_stack" "k8s_core" {
+  config {
+    stack_id = "k8s-core"
+  }
+}
@@ -0,0 +1,6 @@
+action "spacelift_publish_module_version" "k8s_module" {
+  config {
+    module_id      = spacelift_module.k8s-module.id
+    version_number = "1.2.0"
+  }
+}
@@ -0,0 +1,16 @@
+resource "terraform_data" "worker_image" {
+  input = var.worker_image
+
+  lifecycle {
+    action_trigger {
+      events  = [after_update]
+      actions = [action.spacelift_recycle_worker_pool.private]
+    }
+  }
+}
+
+action "spacelift_recycle_worker_pool" "private" {
+  config {
+    worker_pool_id = spacelift_worker_pool.private.id
+  }
+}
@@ -0,0 +1,8 @@
+action "spacelift_run_task" "unlock_state" {
+  config {
+    stack_id = "k8s-core"
+    command  = "terraform force-unlock -force ${var.lock_id}"
+
+    wait {}
+  }
+}
@@ -0,0 +1,21 @@
+resource "terraform_data" "release" {
+  input = var.release
+
+  lifecycle {
+    action_trigger {
+      events  = [after_update]
+      actions = [action.spacelift_trigger_run.deploy]
+    }
+  }
+}
+
+action "spacelift_trigger_run" "deploy" {
+  config {
+    stack_id = "k8s-core"
+
+    wait {
+      continue_on_state = ["finished", "unconfirmed"]
+      timeout           = "45m"
+    }
+  }
+}
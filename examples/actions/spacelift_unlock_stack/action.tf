@@ -0,0 +1,5 @@
+action "spacelift_unlock_stack" "k8s_core" {
+  config {
+    stack_id = "k8s-core"
+  }
+}
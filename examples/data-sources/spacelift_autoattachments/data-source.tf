@@ -0,0 +1,12 @@
+# What would attach itself to a stack in the "team-platform" space labelled "production"?
+data "spacelift_autoattachments" "production" {
+  space_id = "team-platform"
+  labels   = ["production"]
+}
+
+output "autoattached_policies" {
+  value = [
+    for attachment in data.spacelift_autoattachments.production.autoattachments :
+    attachment.name if attachment.type == "POLICY"
+  ]
+}
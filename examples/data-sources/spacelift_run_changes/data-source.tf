@@ -0,0 +1,19 @@
+resource "spacelift_run" "preview" {
+  stack_id = "k8s_core"
+  proposed = true
+
+  wait {
+    continue_on_state = ["finished"]
+  }
+}
+
+data "spacelift_run_changes" "preview" {
+  stack_id = spacelift_run.preview.stack_id
+  run_id   = spacelift_run.preview.id
+}
+
+# Only roll out downstream if the plan does not replace anything.
+module "downstream" {
+  source = "./downstream"
+  count  = length(data.spacelift_run_changes.preview.replaced_addresses) == 0 ? 1 : 0
+}
@@ -0,0 +1,18 @@
+resource "spacelift_run" "release" {
+  stack_id = "k8s_core"
+
+  wait {
+    continue_on_state = ["finished", "failed"]
+  }
+}
+
+data "spacelift_run_logs" "release" {
+  stack_id  = spacelift_run.release.stack_id
+  run_id    = spacelift_run.release.id
+  phases    = ["PLANNING", "APPLYING"]
+  max_lines = 200
+}
+
+output "apply_logs" {
+  value = one([for phase in data.spacelift_run_logs.release.logs : phase.content if phase.phase == "APPLYING"])
+}
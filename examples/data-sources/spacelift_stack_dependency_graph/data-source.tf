@@ -0,0 +1,14 @@
+data "spacelift_stack_dependency_graph" "platform" {
+  space_id = "platform-01HXYZEXAMPLESPACE"
+  labels   = ["production"]
+}
+
+# Render the graph for the architecture docs.
+resource "local_file" "platform_graph" {
+  filename = "${path.module}/docs/platform.mmd"
+  content  = data.spacelift_stack_dependency_graph.platform.mermaid
+}
+
+output "platform_rollout_order" {
+  value = data.spacelift_stack_dependency_graph.platform.topological_order
+}
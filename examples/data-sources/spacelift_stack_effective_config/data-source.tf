@@ -0,0 +1,11 @@
+data "spacelift_stack_effective_config" "k8s_core" {
+  stack_id = "k8s_core"
+}
+
+# Where does each environment variable the stack's runs get come from?
+output "environment_variable_sources" {
+  value = {
+    for variable in data.spacelift_stack_effective_config.k8s_core.environment_variable :
+    variable.name => variable.source == "STACK" ? "stack" : variable.context_name
+  }
+}
@@ -0,0 +1,18 @@
+# The latest tracked run on the main branch over the last week.
+data "spacelift_stack_runs" "k8s_core" {
+  stack_id      = "k8s_core"
+  created_after = timeadd(plantimestamp(), "-168h")
+  limit         = 1
+
+  type {
+    any_of = ["TRACKED"]
+  }
+
+  branch {
+    any_of = ["main"]
+  }
+}
+
+output "last_release_finished" {
+  value = one(data.spacelift_stack_runs.k8s_core.runs[*].state) == "FINISHED"
+}
@@ -0,0 +1,7 @@
+data "spacelift_stack_state_versions" "k8s_core" {
+  stack_id = "k8s_core"
+}
+
+output "latest_state_serial" {
+  value = data.spacelift_stack_state_versions.k8s_core.state_versions[0].serial
+}
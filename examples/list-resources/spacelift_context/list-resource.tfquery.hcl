@@ -0,0 +1,7 @@
+list "spacelift_context" "production" {
+  provider = spacelift
+
+  config {
+    space_id = ["production-01HXXXXXXXXXXXXXXXXXXXXXXX"]
+  }
+}
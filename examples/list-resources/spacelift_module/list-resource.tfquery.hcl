@@ -0,0 +1,7 @@
+list "spacelift_module" "all" {
+  provider = spacelift
+
+  config {
+    repository = ["terraform-modules"]
+  }
+}
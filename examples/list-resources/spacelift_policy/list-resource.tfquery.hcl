@@ -0,0 +1,7 @@
+list "spacelift_policy" "plan" {
+  provider = spacelift
+
+  config {
+    type = ["PLAN"]
+  }
+}
@@ -0,0 +1,7 @@
+list "spacelift_space" "top_level" {
+  provider = spacelift
+
+  config {
+    parent_space_id = ["root"]
+  }
+}
@@ -0,0 +1,9 @@
+list "spacelift_stack" "ui_managed" {
+  provider         = spacelift
+  include_resource = true
+
+  config {
+    repository = ["monorepo"]
+    labels     = ["team:platform"]
+  }
+}
@@ -0,0 +1,15 @@
+resource "spacelift_blueprint_stack" "payments_prod" {
+  blueprint_id = spacelift_blueprint.service.id
+
+  inputs = {
+    environment = "prod"
+    replicas    = "3"
+  }
+
+  secret_inputs = {
+    api_token = var.payments_api_token
+  }
+
+  # Fail the plan rather than replace the stack if the inputs ever change.
+  on_inputs_change = "FAIL"
+}
@@ -0,0 +1,6 @@
+import {
+  to = spacelift_context.prod-k8s-ie
+  identity = {
+    context_id = "prod-k8s-ie"
+  }
+}
@@ -1 +1,4 @@
 terraform import spacelift_context.prod-k8s-ie $CONTEXT_ID
+
+# Alternatively, by the path of its space and its name, as long as no other context in the space has that name
+terraform import spacelift_context.prod-k8s-ie 'space:root/platform/prod//name:prod-k8s-ie'
@@ -0,0 +1,7 @@
+import {
+  to = spacelift_context_attachment.test_stack
+  identity = {
+    context_id = "prod-k8s-ie"
+    stack_id   = "k8s-core"
+  }
+}
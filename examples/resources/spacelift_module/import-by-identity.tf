@@ -0,0 +1,6 @@
+import {
+  to = spacelift_module.k8s-module
+  identity = {
+    module_id = "k8s-module"
+  }
+}
@@ -0,0 +1,6 @@
+import {
+  to = spacelift_policy.no-weekend-deploys
+  identity = {
+    policy_id = "no-weekend-deploys"
+  }
+}
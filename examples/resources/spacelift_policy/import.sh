@@ -1 +1,4 @@
 terraform import spacelift_policy.no-weekend-deploys $POLICY_ID
+
+# Alternatively, by the path of its space and its name, as long as no other policy in the space has that name
+terraform import spacelift_policy.no-weekend-deploys 'space:root/platform/prod//name:No weekend deploys'
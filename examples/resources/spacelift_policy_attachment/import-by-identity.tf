@@ -0,0 +1,7 @@
+import {
+  to = spacelift_policy_attachment.no-weekend-deploys
+  identity = {
+    policy_id = "no-weekend-deploys"
+    stack_id  = "k8s-core"
+  }
+}
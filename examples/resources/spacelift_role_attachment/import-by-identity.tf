@@ -0,0 +1,7 @@
+import {
+  to = spacelift_role_attachment.stack_attachment
+  identity = {
+    binding_id = "01F8Z5K4Y3D1G2H3J4K5L6M7N8"
+    stack_id   = "k8s-core"
+  }
+}
@@ -11,3 +11,51 @@ resource "spacelift_run" "this" {
     branch = spacelift_stack.this.branch
   }
 }
+
+# Confirm the run without a human, as long as it only adds or updates a
+# handful of DNS records.
+resource "spacelift_run" "dns" {
+  stack_id = spacelift_stack.this.id
+
+  auto_confirm {
+    max_changes            = 10
+    allowed_resource_types = ["aws_route53_record"]
+  }
+
+  wait {
+    continue_on_state = ["finished"]
+  }
+}
+
+# Plan and apply a fix to a single module, recreating one of its instances.
+resource "spacelift_run" "hotfix" {
+  stack_id = spacelift_stack.this.id
+  targets  = ["module.network"]
+  replace  = ["module.network.aws_instance.nat[0]"]
+}
+
+# Replan a run waiting for confirmation, limited to a single resource.
+resource "spacelift_run" "replan" {
+  stack_id      = spacelift_stack.this.id
+  replan_run_id = "01HXYZEXAMPLERUNID"
+  targets       = ["aws_s3_bucket.logs"]
+}
+
+# Apply what a proposed run previewed, as long as the branch has not moved on.
+resource "spacelift_run" "preview" {
+  stack_id = spacelift_stack.this.id
+  proposed = true
+
+  wait {
+    continue_on_state = ["finished"]
+  }
+}
+
+resource "spacelift_run" "promoted" {
+  stack_id       = spacelift_stack.this.id
+  promote_run_id = spacelift_run.preview.id
+
+  wait {
+    continue_on_state = ["finished"]
+  }
+}
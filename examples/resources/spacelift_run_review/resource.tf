@@ -0,0 +1,16 @@
+resource "spacelift_run" "release" {
+  stack_id = "k8s-core"
+}
+
+# Approve the run once the change ticket has been approved, and wait for it
+# to be applied.
+resource "spacelift_run_review" "release" {
+  stack_id = spacelift_run.release.stack_id
+  run_id   = spacelift_run.release.id
+  decision = "APPROVE"
+  note     = "Change ticket CHG-1234 approved"
+
+  wait {
+    continue_on_state = ["finished"]
+  }
+}
@@ -0,0 +1,6 @@
+import {
+  to = spacelift_space.development
+  identity = {
+    space_id = "development-01HXXXXXXXXXXXXXXXXXXXXXXX"
+  }
+}
@@ -0,0 +1,6 @@
+import {
+  to = spacelift_stack.k8s_core
+  identity = {
+    stack_id = "k8s-core"
+  }
+}
@@ -1 +1,4 @@
 terraform import spacelift_stack.k8s_core $STACK_ID
+
+# Alternatively, by the path of its space and its name, as long as no other stack in the space has that name
+terraform import spacelift_stack.k8s_core 'space:root/platform/prod//name:k8s-core'
@@ -0,0 +1 @@
+terraform import spacelift_stack_dependencies.example stack-id
@@ -0,0 +1,42 @@
+resource "spacelift_stack" "infra" {
+  branch     = "master"
+  name       = "Infrastructure stack"
+  repository = "core-infra"
+}
+
+resource "spacelift_stack" "network" {
+  branch     = "master"
+  name       = "Network stack"
+  repository = "network"
+}
+
+resource "spacelift_stack" "app" {
+  branch     = "master"
+  name       = "Application stack"
+  repository = "app"
+}
+
+# The application stack depends on these two stacks only. Dependencies added
+# in the UI are removed on the next apply.
+resource "spacelift_stack_dependencies" "app" {
+  stack_id = spacelift_stack.app.id
+
+  depends_on_stack_ids = [
+    spacelift_stack.infra.id,
+    spacelift_stack.network.id,
+  ]
+
+  references = [
+    {
+      depends_on_stack_id = spacelift_stack.infra.id
+      output_name         = "DB_CONNECTION_STRING"
+      input_name          = "APP_DB_URL"
+    },
+    {
+      depends_on_stack_id = spacelift_stack.network.id
+      output_name         = "vpc_id"
+      input_name          = "TF_VAR_vpc_id"
+      trigger_always      = true
+    },
+  ]
+}
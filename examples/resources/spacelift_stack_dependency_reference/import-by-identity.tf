@@ -0,0 +1,8 @@
+import {
+  to = spacelift_stack_dependency_reference.example
+  identity = {
+    stack_id            = "k8s-apps"
+    depends_on_stack_id = "k8s-core"
+    input_name          = "TF_VAR_cluster_endpoint"
+  }
+}
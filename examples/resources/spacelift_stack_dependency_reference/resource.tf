@@ -19,4 +19,12 @@ resource "spacelift_stack_dependency_reference" "test" {
   stack_dependency_id = spacelift_stack_dependency.test.id
   output_name         = "DB_CONNECTION_STRING"
   input_name          = "APP_DB_URL"
-}
\ No newline at end of file
+}
+
+# Fail the plan unless the infrastructure stack already has the output.
+resource "spacelift_stack_dependency_reference" "vpc" {
+  stack_dependency_id = spacelift_stack_dependency.test.id
+  output_name         = "vpc_id"
+  input_name          = "TF_VAR_vpc_id"
+  on_missing_output   = "FAIL"
+}
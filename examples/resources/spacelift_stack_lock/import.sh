@@ -0,0 +1 @@
+terraform import spacelift_stack_lock.maintenance $STACK_ID
@@ -0,0 +1,6 @@
+resource "spacelift_stack_lock" "maintenance" {
+  count = var.maintenance_window ? 1 : 0
+
+  stack_id = spacelift_stack.k8s_core.id
+  note     = "Cluster upgrade in progress"
+}
@@ -0,0 +1,36 @@
+resource "spacelift_stack_set" "services" {
+  max_concurrency = 5
+
+  defaults {
+    branch            = "main"
+    repository        = "services"
+    space_id          = "root"
+    terraform_version = "1.5.7"
+    labels            = ["team:platform"]
+  }
+
+  member {
+    key          = "billing"
+    name         = "billing"
+    project_root = "services/billing"
+  }
+
+  member {
+    key          = "search"
+    name         = "search"
+    project_root = "services/search"
+    autodeploy   = true
+  }
+
+  # Nested blocks override the defaults as a whole.
+  member {
+    key          = "legacy"
+    name         = "legacy"
+    project_root = "services/legacy"
+    branch       = "release"
+
+    terragrunt {
+      use_run_all = true
+    }
+  }
+}
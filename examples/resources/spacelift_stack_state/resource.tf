@@ -0,0 +1,15 @@
+# Push a state file, e.g. one produced by migrating resources out of another stack.
+resource "spacelift_stack_state" "migrated" {
+  stack_id   = spacelift_stack.k8s_core.id
+  state_file = "${path.module}/migrated.tfstate"
+}
+
+# Roll back to the state version before the latest one.
+data "spacelift_stack_state_versions" "k8s_core" {
+  stack_id = spacelift_stack.k8s_core.id
+}
+
+resource "spacelift_stack_state" "rollback" {
+  stack_id         = spacelift_stack.k8s_core.id
+  state_version_id = data.spacelift_stack_state_versions.k8s_core.state_versions[1].id
+}
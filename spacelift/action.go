@@ -0,0 +1,167 @@
+package spacelift
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	actionschema "github.com/hashicorp/terraform-plugin-framework/action/schema"
+	fwdiag "github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs"
+)
+
+// defaultActionWaitTimeout matches the create timeout of the resources the
+// actions replace, e.g. spacelift_run and spacelift_task.
+const defaultActionWaitTimeout = 30 * time.Minute
+
+// actionClient carries the API client of an action. Actions have no state, so
+// the client is all they share.
+type actionClient struct {
+	client *internal.Client
+}
+
+func (a *actionClient) Configure(_ context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	// ProviderData is nil during schema-validation walks.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*internal.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"unexpected provider data",
+			fmt.Sprintf("expected *internal.Client, got %T", req.ProviderData),
+		)
+		return
+	}
+
+	a.client = client
+}
+
+// actionWaitModel mirrors the wait block of spacelift_run and spacelift_task.
+// Actions have no timeouts block, so the timeout lives here.
+type actionWaitModel struct {
+	Disabled          types.Bool   `tfsdk:"disabled"`
+	ContinueOnState   []string     `tfsdk:"continue_on_state"`
+	ContinueOnTimeout types.Bool   `tfsdk:"continue_on_timeout"`
+	Timeout           types.String `tfsdk:"timeout"`
+}
+
+func actionWaitBlock(subject string) actionschema.SingleNestedBlock {
+	return actionschema.SingleNestedBlock{
+		Description: fmt.Sprintf("Wait for the %s to finish", subject),
+		Attributes: map[string]actionschema.Attribute{
+			"disabled": actionschema.BoolAttribute{
+				Description: fmt.Sprintf("Whether waiting for the %s is disabled or not. Default: `false`", subject),
+				Optional:    true,
+			},
+			"continue_on_state": actionschema.SetAttribute{
+				Description: "Continue on the specified states of a finished run. If not specified, the default is `[ 'finished' ]`. You can use following states: `applying`, `canceled`, `confirmed`, `destroying`, `discarded`, `failed`, `finished`, `initializing`, `pending_review`, `performing`, `planning`, `preparing_apply`, `preparing_replan`, `preparing`, `queued`, `ready`, `replan_requested`, `skipped`, `stopped`, `unconfirmed`.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"continue_on_timeout": actionschema.BoolAttribute{
+				Description: fmt.Sprintf("Continue if the %s timed out, i.e. did not reach any defined end state in time. Default: `false`", subject),
+				Optional:    true,
+			},
+			"timeout": actionschema.StringAttribute{
+				Description: "How long to wait, as a Go duration string, e.g. `45m`. Default: `30m`",
+				Optional:    true,
+				Validators:  []validator.String{durationValidator{}},
+			},
+		},
+	}
+}
+
+// durationValidator checks that a string is a positive Go duration, so that a
+// bad wait timeout fails the plan rather than the action.
+type durationValidator struct{}
+
+func (durationValidator) Description(context.Context) string {
+	return "value must be a positive Go duration string, e.g. `45m`"
+}
+
+func (v durationValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (durationValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := parseWaitTimeout(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "invalid wait timeout", err.Error())
+	}
+}
+
+// parseWaitTimeout parses the timeout of a wait block.
+func parseWaitTimeout(raw string) (time.Duration, error) {
+	timeout, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, err
+	}
+
+	if timeout <= 0 {
+		return 0, fmt.Errorf("timeout %q must be positive", raw)
+	}
+
+	return timeout, nil
+}
+
+// waitConfiguration turns the wait block into the configuration shared with
+// the resources. Like on the resources, the action only waits when the block
+// is present and not disabled; nil is returned otherwise.
+func (m *actionWaitModel) waitConfiguration() (*structs.WaitConfiguration, time.Duration, fwdiag.Diagnostics) {
+	var diags fwdiag.Diagnostics
+
+	if m == nil || m.Disabled.ValueBool() {
+		return nil, 0, diags
+	}
+
+	timeout := defaultActionWaitTimeout
+	if raw := m.Timeout.ValueString(); raw != "" {
+		parsed, err := parseWaitTimeout(raw)
+		if err != nil {
+			diags.AddError("invalid wait timeout", err.Error())
+			return nil, 0, diags
+		}
+		timeout = parsed
+	}
+
+	wait := structs.NewWaitConfigurationFromValues(
+		false,
+		m.ContinueOnState,
+		m.ContinueOnTimeout.ValueBool(),
+	)
+
+	return wait, timeout, diags
+}
+
+// waitForRun waits for the run according to the wait block, reporting progress
+// to Terraform before and after.
+func waitForRun(ctx context.Context, client *internal.Client, wait *actionWaitModel, stackID, runID string, resp *action.InvokeResponse) {
+	cfg, timeout, diags := wait.waitConfiguration()
+	resp.Diagnostics.Append(diags...)
+	if cfg == nil || resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf("waiting for run %s on stack %s", runID, stackID),
+	})
+
+	resp.Diagnostics.Append(fromSDKDiagnostics(cfg.Wait(ctx, client, stackID, runID, timeout))...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf("run %s on stack %s is done", runID, stackID),
+	})
+}
@@ -0,0 +1,58 @@
+package spacelift
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	actionschema "github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ action.ActionWithConfigure = (*lockStackAction)(nil)
+
+// NewLockStackAction returns the spacelift_lock_stack action.
+func NewLockStackAction() action.Action { return &lockStackAction{} }
+
+type lockStackAction struct {
+	actionClient
+}
+
+type lockStackModel struct {
+	StackID types.String `tfsdk:"stack_id"`
+	Note    types.String `tfsdk:"note"`
+}
+
+func (a *lockStackAction) Metadata(_ context.Context, _ action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = "spacelift_lock_stack"
+}
+
+func (a *lockStackAction) Schema(_ context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = actionschema.Schema{
+		Description: "" +
+			"`spacelift_lock_stack` locks a stack for exclusive use by the API user, " +
+			"e.g. for the duration of maintenance. Pair it with `spacelift_unlock_stack`.",
+
+		Attributes: map[string]actionschema.Attribute{
+			"stack_id": actionschema.StringAttribute{
+				Description: "ID of the stack to lock",
+				Required:    true,
+			},
+			"note": actionschema.StringAttribute{
+				Description: "Note explaining why the stack is locked",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func (a *lockStackAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var config lockStackModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := lockStack(ctx, a.client, config.StackID.ValueString(), config.Note.ValueString()); err != nil {
+		resp.Diagnostics.AddError("could not lock stack", err.Error())
+	}
+}
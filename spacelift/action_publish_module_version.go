@@ -0,0 +1,105 @@
+package spacelift
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	actionschema "github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/shurcooL/graphql"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+)
+
+var _ action.ActionWithConfigure = (*publishModuleVersionAction)(nil)
+
+// NewPublishModuleVersionAction returns the spacelift_publish_module_version
+// action, the stateless counterpart of spacelift_version.
+func NewPublishModuleVersionAction() action.Action { return &publishModuleVersionAction{} }
+
+type publishModuleVersionAction struct {
+	actionClient
+}
+
+type publishModuleVersionModel struct {
+	ModuleID      types.String `tfsdk:"module_id"`
+	CommitSHA     types.String `tfsdk:"commit_sha"`
+	VersionNumber types.String `tfsdk:"version_number"`
+}
+
+func (a *publishModuleVersionAction) Metadata(_ context.Context, _ action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = "spacelift_publish_module_version"
+}
+
+func (a *publishModuleVersionAction) Schema(_ context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = actionschema.Schema{
+		Description: "" +
+			"`spacelift_publish_module_version` publishes a new version of a module " +
+			"and waits for it to be tested. Unlike `spacelift_version` it leaves " +
+			"nothing in the state, so it needs no keepers to publish again.",
+
+		Attributes: map[string]actionschema.Attribute{
+			"module_id": actionschema.StringAttribute{
+				Description: "ID of the module",
+				Required:    true,
+			},
+			"commit_sha": actionschema.StringAttribute{
+				Description: "Commit SHA to publish the version from. Defaults to the head of the module's branch.",
+				Optional:    true,
+			},
+			"version_number": actionschema.StringAttribute{
+				Description: "Semantic version number to publish. Defaults to the number in the module's configuration file.",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func (a *publishModuleVersionAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var config publishModuleVersionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var mutation struct {
+		Version struct {
+			ID string
+		} `graphql:"versionCreate(module: $module, commitSha: $sha, version: $version)"`
+	}
+
+	moduleID := config.ModuleID.ValueString()
+
+	variables := map[string]any{
+		"module":  toID(moduleID),
+		"sha":     (*graphql.String)(nil),
+		"version": (*graphql.String)(nil),
+	}
+
+	if sha := config.CommitSHA.ValueString(); sha != "" {
+		variables["sha"] = toString(sha)
+	}
+
+	if version := config.VersionNumber.ValueString(); version != "" {
+		variables["version"] = toString(version)
+	}
+
+	if err := a.client.Mutate(ctx, "ResourceVersionCreate", &mutation, variables); err != nil {
+		resp.Diagnostics.AddError(
+			"could not publish module version",
+			fmt.Sprintf("could not trigger version for module %s: %v", moduleID, internal.FromSpaceliftError(err)),
+		)
+		return
+	}
+
+	if mutation.Version.ID == "" {
+		return
+	}
+
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf("waiting for version %s of module %s", mutation.Version.ID, moduleID),
+	})
+
+	resp.Diagnostics.Append(fromSDKDiagnostics(waitForVersionCreate(ctx, a.client, mutation.Version.ID, moduleID))...)
+}
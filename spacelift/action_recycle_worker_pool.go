@@ -0,0 +1,66 @@
+package spacelift
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	actionschema "github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/shurcooL/graphql"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+)
+
+var _ action.ActionWithConfigure = (*recycleWorkerPoolAction)(nil)
+
+// NewRecycleWorkerPoolAction returns the spacelift_recycle_worker_pool action,
+// the stateless counterpart of spacelift_worker_pool_recycle.
+func NewRecycleWorkerPoolAction() action.Action { return &recycleWorkerPoolAction{} }
+
+type recycleWorkerPoolAction struct {
+	actionClient
+}
+
+type recycleWorkerPoolModel struct {
+	WorkerPoolID types.String `tfsdk:"worker_pool_id"`
+}
+
+func (a *recycleWorkerPoolAction) Metadata(_ context.Context, _ action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = "spacelift_recycle_worker_pool"
+}
+
+func (a *recycleWorkerPoolAction) Schema(_ context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = actionschema.Schema{
+		Description: "" +
+			"`spacelift_recycle_worker_pool` recycles the workers of a private worker " +
+			"pool. Unlike `spacelift_worker_pool_recycle` it leaves nothing in the " +
+			"state, so it needs no keepers to recycle again.",
+
+		Attributes: map[string]actionschema.Attribute{
+			"worker_pool_id": actionschema.StringAttribute{
+				Description: "ID of the worker pool to recycle",
+				Required:    true,
+			},
+		},
+	}
+}
+
+func (a *recycleWorkerPoolAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var config recycleWorkerPoolModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var mutation struct {
+		WorkerPoolCycle graphql.Boolean `graphql:"workerPoolCycle(id: $workerPoolId)"`
+	}
+
+	variables := map[string]any{
+		"workerPoolId": graphql.ID(config.WorkerPoolID.ValueString()),
+	}
+
+	if err := a.client.Mutate(ctx, "CycleWorkerPool", &mutation, variables); err != nil {
+		resp.Diagnostics.AddError("could not recycle worker pool", internal.FromSpaceliftError(err).Error())
+	}
+}
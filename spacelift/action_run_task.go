@@ -0,0 +1,105 @@
+package spacelift
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	actionschema "github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/shurcooL/graphql"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs"
+)
+
+var _ action.ActionWithConfigure = (*runTaskAction)(nil)
+
+// NewRunTaskAction returns the spacelift_run_task action, the stateless
+// counterpart of spacelift_task.
+func NewRunTaskAction() action.Action { return &runTaskAction{} }
+
+type runTaskAction struct {
+	actionClient
+}
+
+type runTaskModel struct {
+	StackID types.String     `tfsdk:"stack_id"`
+	Command types.String     `tfsdk:"command"`
+	Init    types.Bool       `tfsdk:"init"`
+	Wait    *actionWaitModel `tfsdk:"wait"`
+}
+
+func (a *runTaskAction) Metadata(_ context.Context, _ action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = "spacelift_run_task"
+}
+
+func (a *runTaskAction) Schema(_ context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = actionschema.Schema{
+		Description: "" +
+			"`spacelift_run_task` runs a task on a stack. Unlike `spacelift_task` " +
+			"it leaves nothing in the state, so it needs no keepers to run again.",
+
+		Attributes: map[string]actionschema.Attribute{
+			"stack_id": actionschema.StringAttribute{
+				Description: "ID of the stack for which to run the task",
+				Required:    true,
+			},
+			"command": actionschema.StringAttribute{
+				Description: "Command that will be run.",
+				Required:    true,
+			},
+			"init": actionschema.BoolAttribute{
+				Description: "Whether to initialize the stack or not. Default: `true`",
+				Optional:    true,
+			},
+		},
+		Blocks: map[string]actionschema.Block{
+			"wait": actionWaitBlock("task"),
+		},
+	}
+}
+
+func (a *runTaskAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var config runTaskModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	stackID := config.StackID.ValueString()
+
+	if config.Command.ValueString() == "" {
+		resp.Diagnostics.AddError("invalid task", "command must not be empty")
+		return
+	}
+
+	init := config.Init.IsNull() || config.Init.ValueBool()
+
+	var mutation struct {
+		CreateTask structs.Task `graphql:"taskCreate(stack: $stack, command: $command, skipInitialization: $skipInitialization)"`
+	}
+
+	variables := map[string]any{
+		"stack":              graphql.ID(stackID),
+		"command":            graphql.String(config.Command.ValueString()),
+		"skipInitialization": graphql.Boolean(!init),
+	}
+
+	if err := a.client.Mutate(ctx, "TaskCreate", &mutation, variables); err != nil {
+		resp.Diagnostics.AddError("could not create task", internal.FromSpaceliftError(err).Error())
+		return
+	}
+
+	taskID, ok := mutation.CreateTask.ID.(string)
+	if !ok || taskID == "" {
+		resp.Diagnostics.AddError("could not create task", fmt.Sprintf("no task ID returned for stack %s", stackID))
+		return
+	}
+
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf("started task %s on stack %s", taskID, stackID),
+	})
+
+	waitForRun(ctx, a.client, config.Wait, stackID, taskID, resp)
+}
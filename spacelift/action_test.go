@@ -0,0 +1,35 @@
+package spacelift
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestActionWaitTimeoutValidation(t *testing.T) {
+	for _, tc := range []struct {
+		value   types.String
+		wantErr bool
+	}{
+		{value: types.StringValue("45m")},
+		{value: types.StringValue("1h30m")},
+		{value: types.StringNull()},
+		{value: types.StringUnknown()},
+		{value: types.StringValue("45"), wantErr: true},
+		{value: types.StringValue("soon"), wantErr: true},
+		{value: types.StringValue("-5m"), wantErr: true},
+		{value: types.StringValue("0s"), wantErr: true},
+	} {
+		req := validator.StringRequest{Path: path.Root("wait").AtName("timeout"), ConfigValue: tc.value}
+		var resp validator.StringResponse
+
+		durationValidator{}.ValidateString(context.Background(), req, &resp)
+
+		if got := resp.Diagnostics.HasError(); got != tc.wantErr {
+			t.Errorf("%s: got error %t, want %t: %v", tc.value, got, tc.wantErr, resp.Diagnostics)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package spacelift
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	actionschema "github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/shurcooL/graphql"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs"
+)
+
+var _ action.ActionWithConfigure = (*triggerRunAction)(nil)
+
+// NewTriggerRunAction returns the spacelift_trigger_run action, the stateless
+// counterpart of spacelift_run.
+func NewTriggerRunAction() action.Action { return &triggerRunAction{} }
+
+type triggerRunAction struct {
+	actionClient
+}
+
+type triggerRunModel struct {
+	StackID   types.String     `tfsdk:"stack_id"`
+	CommitSHA types.String     `tfsdk:"commit_sha"`
+	Proposed  types.Bool       `tfsdk:"proposed"`
+	Wait      *actionWaitModel `tfsdk:"wait"`
+}
+
+func (a *triggerRunAction) Metadata(_ context.Context, _ action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = "spacelift_trigger_run"
+}
+
+func (a *triggerRunAction) Schema(_ context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = actionschema.Schema{
+		Description: "" +
+			"`spacelift_trigger_run` triggers a run on a stack. Unlike `spacelift_run` " +
+			"it leaves nothing in the state, so it needs no keepers to run again.",
+
+		Attributes: map[string]actionschema.Attribute{
+			"stack_id": actionschema.StringAttribute{
+				Description: "ID of the stack on which the run is to be triggered.",
+				Required:    true,
+			},
+			"commit_sha": actionschema.StringAttribute{
+				Description: "The commit SHA for which to trigger a run.",
+				Optional:    true,
+			},
+			"proposed": actionschema.BoolAttribute{
+				Description: "Whether the run is a proposed run. Defaults to `false`.",
+				Optional:    true,
+			},
+		},
+		Blocks: map[string]actionschema.Block{
+			"wait": actionWaitBlock("run"),
+		},
+	}
+}
+
+func (a *triggerRunAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var config triggerRunModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var mutation struct {
+		CreateRun structs.Run `graphql:"runTrigger(stack: $stack, commitSha: $sha, runType: $runType)"`
+	}
+
+	stackID := config.StackID.ValueString()
+
+	runType := structs.RunTypeTracked
+	if config.Proposed.ValueBool() {
+		runType = structs.RunTypeProposed
+	}
+
+	variables := map[string]any{
+		"stack":   toID(stackID),
+		"sha":     (*graphql.String)(nil),
+		"runType": runType,
+	}
+
+	if sha := config.CommitSHA.ValueString(); sha != "" {
+		variables["sha"] = graphql.NewString(graphql.String(sha))
+	}
+
+	if err := a.client.Mutate(ctx, "RunTrigger", &mutation, variables); err != nil {
+		resp.Diagnostics.AddError(
+			"could not trigger run",
+			fmt.Sprintf("could not trigger run for stack %s: %v", stackID, internal.FromSpaceliftError(err)),
+		)
+		return
+	}
+
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf("triggered run %s on stack %s", mutation.CreateRun.ID, stackID),
+	})
+
+	waitForRun(ctx, a.client, config.Wait, stackID, mutation.CreateRun.ID, resp)
+}
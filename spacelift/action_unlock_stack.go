@@ -0,0 +1,51 @@
+package spacelift
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	actionschema "github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ action.ActionWithConfigure = (*unlockStackAction)(nil)
+
+// NewUnlockStackAction returns the spacelift_unlock_stack action.
+func NewUnlockStackAction() action.Action { return &unlockStackAction{} }
+
+type unlockStackAction struct {
+	actionClient
+}
+
+type unlockStackModel struct {
+	StackID types.String `tfsdk:"stack_id"`
+}
+
+func (a *unlockStackAction) Metadata(_ context.Context, _ action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = "spacelift_unlock_stack"
+}
+
+func (a *unlockStackAction) Schema(_ context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = actionschema.Schema{
+		Description: "`spacelift_unlock_stack` releases the lock the API user holds on a stack.",
+
+		Attributes: map[string]actionschema.Attribute{
+			"stack_id": actionschema.StringAttribute{
+				Description: "ID of the stack to unlock",
+				Required:    true,
+			},
+		},
+	}
+}
+
+func (a *unlockStackAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var config unlockStackModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := unlockStack(ctx, a.client, config.StackID.ValueString()); err != nil {
+		resp.Diagnostics.AddError("could not unlock stack", err.Error())
+	}
+}
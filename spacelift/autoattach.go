@@ -0,0 +1,365 @@
+package spacelift
+
+import (
+	"cmp"
+	"context"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"weak"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+)
+
+const autoattachLabelPrefix = "autoattach:"
+
+// autoattachment is an entity which attaches itself to a stack through an
+// autoattach label.
+type autoattachment struct {
+	Type    string
+	ID      string
+	Name    string
+	SpaceID string
+	Label   string
+}
+
+func (a autoattachment) flatten() map[string]any {
+	return map[string]any{
+		"type":     a.Type,
+		"id":       a.ID,
+		"name":     a.Name,
+		"space_id": a.SpaceID,
+		"label":    a.Label,
+	}
+}
+
+func flattenAutoattachments(attachments []autoattachment) []any {
+	flat := make([]any, 0, len(attachments))
+	for _, attachment := range attachments {
+		flat = append(flat, attachment.flatten())
+	}
+	return flat
+}
+
+// autoattachLabels returns the labels of a stack, sorted.
+func autoattachLabels(set *schema.Set) []string {
+	labels := make([]string, 0, set.Len())
+	for _, label := range set.List() {
+		labels = append(labels, label.(string))
+	}
+	slices.Sort(labels)
+	return labels
+}
+
+// autoattachmentSchema describes a single autoattachment, shared by the
+// preview data source and the stack.
+func autoattachmentSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"type": {
+				Type:        schema.TypeString,
+				Description: "Type of the attached entity: `CONTEXT`, `POLICY`, `AWS_INTEGRATION` or `AZURE_INTEGRATION`",
+				Computed:    true,
+			},
+			"id": {
+				Type:        schema.TypeString,
+				Description: "ID of the attached entity",
+				Computed:    true,
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Description: "Name of the attached entity",
+				Computed:    true,
+			},
+			"space_id": {
+				Type:        schema.TypeString,
+				Description: "ID of the space the attached entity is in",
+				Computed:    true,
+			},
+			"label": {
+				Type:        schema.TypeString,
+				Description: "The `autoattach:` label of the entity which matched",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// autoattachCandidate is an entity carrying labels, as returned by the API.
+type autoattachCandidate struct {
+	ID     string   `graphql:"id"`
+	Name   string   `graphql:"name"`
+	Space  string   `graphql:"space"`
+	Labels []string `graphql:"labels"`
+}
+
+// autoattachCandidates are the entities which may attach themselves to
+// stacks, along with the space tree deciding which stacks can see them.
+type autoattachCandidates struct {
+	Spaces []struct {
+		ID              string  `graphql:"id"`
+		InheritEntities bool    `graphql:"inheritEntities"`
+		ParentSpace     *string `graphql:"parentSpace"`
+	} `graphql:"spaces()"`
+	Contexts []autoattachCandidate `graphql:"contexts()"`
+	Policies []struct {
+		ID     string   `graphql:"id"`
+		Name   string   `graphql:"name"`
+		Space  string   `graphql:"space"`
+		Labels []string `graphql:"labels"`
+		Type   string   `graphql:"type"`
+	} `graphql:"policies()"`
+	AWSIntegrations []struct {
+		ID                string   `graphql:"id"`
+		Name              string   `graphql:"name"`
+		Space             string   `graphql:"space"`
+		Labels            []string `graphql:"labels"`
+		AutoattachEnabled bool     `graphql:"autoattachEnabled"`
+	} `graphql:"awsIntegrations()"`
+	AzureIntegrations []struct {
+		ID                string   `graphql:"id"`
+		Name              string   `graphql:"name"`
+		Space             string   `graphql:"space"`
+		Labels            []string `graphql:"labels"`
+		AutoattachEnabled bool     `graphql:"autoattachEnabled"`
+	} `graphql:"azureIntegrations()"`
+}
+
+func getAutoattachCandidates(ctx context.Context, client *internal.Client) (*autoattachCandidates, error) {
+	var query autoattachCandidates
+
+	if err := client.Query(ctx, "AutoattachmentsPreview", &query, nil); err != nil {
+		return nil, errors.Wrap(err, "could not query for autoattachable entities")
+	}
+
+	return &query, nil
+}
+
+// autoattachPlans holds the autoattachable entities looked up while planning,
+// so that a plan covering many stacks queries for them only once. Terraform
+// configures a provider instance, and so a client, for every operation, which
+// makes the client a key scoped to a single plan. The key does not keep the
+// client alive, and the entry goes away along with the client.
+var autoattachPlans = struct {
+	sync.Mutex
+	byClient map[weak.Pointer[internal.Client]]*autoattachCandidates
+}{byClient: map[weak.Pointer[internal.Client]]*autoattachCandidates{}}
+
+// plannedAutoattachCandidates returns the autoattachable entities, querying
+// for them the first time they are needed by the plan. Failed queries are not
+// remembered, so that the next stack tries again.
+func plannedAutoattachCandidates(ctx context.Context, client *internal.Client) (*autoattachCandidates, error) {
+	autoattachPlans.Lock()
+	defer autoattachPlans.Unlock()
+
+	key := weak.Make(client)
+	if candidates, ok := autoattachPlans.byClient[key]; ok {
+		return candidates, nil
+	}
+
+	candidates, err := getAutoattachCandidates(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	autoattachPlans.byClient[key] = candidates
+	runtime.AddCleanup(client, forgetAutoattachPlan, key)
+
+	return candidates, nil
+}
+
+func forgetAutoattachPlan(key weak.Pointer[internal.Client]) {
+	autoattachPlans.Lock()
+	defer autoattachPlans.Unlock()
+
+	delete(autoattachPlans.byClient, key)
+}
+
+// previewAutoattachments lists the contexts, policies and cloud integrations
+// which would attach themselves to a stack in the given space with the given
+// labels, sorted by type and ID.
+func previewAutoattachments(ctx context.Context, client *internal.Client, spaceID string, labels []string) ([]autoattachment, error) {
+	candidates, err := getAutoattachCandidates(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return candidates.match(spaceID, labels)
+}
+
+// plannedAutoattachments is previewAutoattachments, sharing a single lookup
+// of the autoattachable entities among all the stacks being planned.
+func plannedAutoattachments(ctx context.Context, client *internal.Client, spaceID string, labels []string) ([]autoattachment, error) {
+	candidates, err := plannedAutoattachCandidates(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return candidates.match(spaceID, labels)
+}
+
+// match returns the candidates which attach themselves to a stack in the
+// given space with the given labels, sorted by type and ID.
+func (c *autoattachCandidates) match(spaceID string, labels []string) ([]autoattachment, error) {
+	parents := make(map[string]*string, len(c.Spaces))
+	inherits := make(map[string]bool, len(c.Spaces))
+	for _, space := range c.Spaces {
+		parents[space.ID] = space.ParentSpace
+		inherits[space.ID] = space.InheritEntities
+	}
+
+	if _, ok := parents[spaceID]; !ok {
+		return nil, errors.Errorf("space %s not found", spaceID)
+	}
+
+	visible := visibleSpaces(spaceID, parents, inherits)
+
+	var attachments []autoattachment
+	add := func(kind string, candidate autoattachCandidate) {
+		if !visible[candidate.Space] {
+			return
+		}
+
+		if label, ok := matchAutoattachLabel(candidate.Labels, labels); ok {
+			attachments = append(attachments, autoattachment{
+				Type:    kind,
+				ID:      candidate.ID,
+				Name:    candidate.Name,
+				SpaceID: candidate.Space,
+				Label:   label,
+			})
+		}
+	}
+
+	for _, candidate := range c.Contexts {
+		add("CONTEXT", candidate)
+	}
+
+	for _, policy := range c.Policies {
+		// Login policies apply to the whole account and never attach to stacks.
+		if policy.Type != "LOGIN" {
+			add("POLICY", autoattachCandidate{policy.ID, policy.Name, policy.Space, policy.Labels})
+		}
+	}
+
+	for _, integration := range c.AWSIntegrations {
+		if integration.AutoattachEnabled {
+			add("AWS_INTEGRATION", autoattachCandidate{integration.ID, integration.Name, integration.Space, integration.Labels})
+		}
+	}
+
+	for _, integration := range c.AzureIntegrations {
+		if integration.AutoattachEnabled {
+			add("AZURE_INTEGRATION", autoattachCandidate{integration.ID, integration.Name, integration.Space, integration.Labels})
+		}
+	}
+
+	slices.SortFunc(attachments, func(a, b autoattachment) int {
+		return cmp.Or(cmp.Compare(a.Type, b.Type), cmp.Compare(a.ID, b.ID))
+	})
+
+	return attachments, nil
+}
+
+// visibleSpaces returns the spaces whose entities a stack in spaceID can use:
+// its own space, and the ancestors it inherits entities from for as long as
+// every space on the way inherits them.
+func visibleSpaces(spaceID string, parents map[string]*string, inherits map[string]bool) map[string]bool {
+	visible := map[string]bool{spaceID: true}
+
+	for current := spaceID; inherits[current]; {
+		parent := parents[current]
+		if parent == nil || visible[*parent] {
+			break
+		}
+
+		current = *parent
+		visible[current] = true
+	}
+
+	return visible
+}
+
+// matchAutoattachLabel returns the first autoattach label of an entity which
+// matches one of the stack labels, the wildcard label matching any stack.
+func matchAutoattachLabel(entityLabels, stackLabels []string) (string, bool) {
+	for _, label := range entityLabels {
+		target, ok := strings.CutPrefix(label, autoattachLabelPrefix)
+		if !ok {
+			continue
+		}
+
+		if target == "*" || slices.Contains(stackLabels, target) {
+			return label, true
+		}
+	}
+
+	return "", false
+}
+
+// customizeStackAutoattachments computes the autoattachments of a stack in
+// the plan whenever its labels or space change, or when none were recorded
+// yet, e.g. because the stack was imported. They are not looked up otherwise,
+// so that plans don't list every autoattachable entity in the account. If they
+// can't be looked up, they are left to be resolved once the stack is applied.
+func customizeStackAutoattachments(ctx context.Context, diff *schema.ResourceDiff, meta any) error {
+	if diff.Id() != "" && !diff.HasChange("labels") && !diff.HasChange("space_id") && hasAutoattachments(diff.GetRawState()) {
+		return nil
+	}
+
+	spaceID, _ := diff.Get("space_id").(string)
+	if !diff.NewValueKnown("labels") || !diff.NewValueKnown("space_id") || spaceID == "" {
+		return diff.SetNewComputed("autoattachments")
+	}
+
+	labels := autoattachLabels(diff.Get("labels").(*schema.Set))
+
+	attachments, err := plannedAutoattachments(ctx, meta.(*internal.Client), spaceID, labels)
+	if err != nil {
+		tflog.Warn(ctx, "could not preview autoattachments, leaving them to be resolved on apply", map[string]any{"error": err.Error()})
+		return diff.SetNewComputed("autoattachments")
+	}
+
+	return diff.SetNew("autoattachments", flattenAutoattachments(attachments))
+}
+
+// hasAutoattachments tells whether the state of a stack records its
+// autoattachments, even if there are none.
+func hasAutoattachments(state cty.Value) bool {
+	return !state.IsNull() && !state.GetAttr("autoattachments").IsNull()
+}
+
+// resolveStackAutoattachments fills in the autoattachments of a stack which
+// could not be computed at plan time, typically because the space of a new
+// stack was left for Spacelift to pick. They are looked up afresh, since the
+// apply may have just created some of the entities.
+func resolveStackAutoattachments(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	if plan := d.GetRawPlan(); plan.IsNull() || plan.GetAttr("autoattachments").IsKnown() {
+		return nil
+	}
+
+	labels := autoattachLabels(d.Get("labels").(*schema.Set))
+
+	attachments, err := previewAutoattachments(ctx, meta.(*internal.Client), d.Get("space_id").(string), labels)
+	if err != nil {
+		// Leave them unset, so that the next plan looks them up again.
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  "could not preview autoattachments",
+			Detail:   err.Error() + "; they will be looked up again on the next plan",
+		}}
+	}
+
+	if err := d.Set("autoattachments", flattenAutoattachments(attachments)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
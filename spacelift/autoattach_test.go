@@ -0,0 +1,200 @@
+package spacelift
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	. "github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/testhelpers"
+)
+
+func TestVisibleSpaces(t *testing.T) {
+	parent := func(id string) *string { return &id }
+
+	parents := map[string]*string{
+		"root":    nil,
+		"team":    parent("root"),
+		"prod":    parent("team"),
+		"sandbox": parent("team"),
+	}
+
+	inherits := map[string]bool{
+		"team": true,
+		"prod": true,
+	}
+
+	for spaceID, want := range map[string][]string{
+		"root":    {"root"},
+		"team":    {"root", "team"},
+		"prod":    {"prod", "root", "team"},
+		"sandbox": {"sandbox"},
+	} {
+		if got := slices.Sorted(maps.Keys(visibleSpaces(spaceID, parents, inherits))); !slices.Equal(got, want) {
+			t.Errorf("visibleSpaces(%s) = %v, want %v", spaceID, got, want)
+		}
+	}
+}
+
+func TestMatchAutoattachLabel(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		entityLabels []string
+		stackLabels  []string
+		want         string
+	}{
+		{
+			name:         "matching label",
+			entityLabels: []string{"team:platform", "autoattach:prod"},
+			stackLabels:  []string{"dev", "prod"},
+			want:         "autoattach:prod",
+		},
+		{
+			name:         "wildcard",
+			entityLabels: []string{"autoattach:*"},
+			want:         "autoattach:*",
+		},
+		{
+			name:         "plain label does not attach",
+			entityLabels: []string{"prod"},
+			stackLabels:  []string{"prod"},
+		},
+		{
+			name:         "no matching stack label",
+			entityLabels: []string{"autoattach:prod"},
+			stackLabels:  []string{"autoattach:prod", "dev"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := matchAutoattachLabel(tc.entityLabels, tc.stackLabels)
+			if got != tc.want || ok != (tc.want != "") {
+				t.Errorf("got (%q, %t), want %q", got, ok, tc.want)
+			}
+		})
+	}
+}
+
+func TestPlannedAutoattachments(t *testing.T) {
+	var fail bool
+	server := NewGraphQLServer(t, map[string]GraphQLHandler{
+		"AutoattachmentsPreview": func(GraphQLRequest) any {
+			if fail {
+				return errors.New("unauthorized")
+			}
+
+			return map[string]any{
+				"spaces":            []any{map[string]any{"id": "prod", "inheritEntities": false, "parentSpace": nil}},
+				"contexts":          []any{map[string]any{"id": "aws", "name": "AWS", "space": "prod", "labels": []string{"autoattach:prod"}}},
+				"policies":          []any{},
+				"awsIntegrations":   []any{},
+				"azureIntegrations": []any{},
+			}
+		},
+	})
+	client := server.Client()
+
+	fail = true
+	if _, err := plannedAutoattachments(context.Background(), client, "prod", []string{"prod"}); err == nil {
+		t.Fatal("expected the failed lookup to be reported")
+	}
+
+	fail = false
+	for _, labels := range [][]string{{"prod"}, {"dev"}} {
+		attachments, err := plannedAutoattachments(context.Background(), client, "prod", labels)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := slices.Contains(labels, "prod"); (len(attachments) == 1) != want {
+			t.Errorf("labels %v: got autoattachments %v", labels, attachments)
+		}
+	}
+
+	if got := len(server.Requests("AutoattachmentsPreview")); got != 2 {
+		t.Errorf("got %d queries, want one failed and one shared by both stacks", got)
+	}
+}
+
+func TestCustomizeStackAutoattachments(t *testing.T) {
+	server := NewGraphQLServer(t, map[string]GraphQLHandler{
+		"AutoattachmentsPreview": func(GraphQLRequest) any {
+			return map[string]any{
+				"spaces":            []any{map[string]any{"id": "prod", "inheritEntities": false, "parentSpace": nil}},
+				"contexts":          []any{map[string]any{"id": "aws", "name": "AWS", "space": "prod", "labels": []string{"autoattach:prod"}}},
+				"policies":          []any{},
+				"awsIntegrations":   []any{},
+				"azureIntegrations": []any{},
+			}
+		},
+	})
+
+	stack := resourceStack()
+	stackType := stack.CoreConfigSchema().ImpliedType()
+
+	rawConfig := objectWith(stackType, map[string]cty.Value{
+		"name":       cty.StringVal("API"),
+		"branch":     cty.StringVal("main"),
+		"repository": cty.StringVal("monorepo"),
+		"space_id":   cty.StringVal("prod"),
+		"labels":     cty.SetVal([]cty.Value{cty.StringVal("prod")}),
+	})
+	config := terraform.NewResourceConfigShimmed(rawConfig, stack.CoreConfigSchema())
+
+	state := func(attributes map[string]string) *terraform.InstanceState {
+		attributes = maps.Clone(attributes)
+		maps.Copy(attributes, map[string]string{
+			"id":           "api",
+			"name":         "API",
+			"branch":       "main",
+			"repository":   "monorepo",
+			"space_id":     "prod",
+			"labels.#":     "1",
+			"labels.0":     "prod",
+			"manage_state": "true",
+		})
+
+		state := &terraform.InstanceState{ID: "api", Attributes: attributes, RawConfig: rawConfig}
+		rawState, err := state.AttrsAsObjectValue(stackType)
+		if err != nil {
+			t.Fatalf("could not build the state: %v", err)
+		}
+		state.RawState = rawState
+
+		return state
+	}
+
+	for name, tc := range map[string]struct {
+		state   *terraform.InstanceState
+		queries int
+	}{
+		"new stack":      {state: &terraform.InstanceState{RawConfig: rawConfig, RawState: cty.NullVal(stackType)}, queries: 1},
+		"imported stack": {state: state(map[string]string{}), queries: 1},
+		"unchanged stack": {
+			state:   state(map[string]string{"autoattachments.#": "0"}),
+			queries: 0,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			before := len(server.Requests("AutoattachmentsPreview"))
+
+			// Every provider instance looks them up once, so use one per case.
+			diff, err := stack.SimpleDiff(context.Background(), tc.state, config, server.Client())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := len(server.Requests("AutoattachmentsPreview")) - before; got != tc.queries {
+				t.Errorf("got %d queries, want %d", got, tc.queries)
+			}
+
+			_, planned := diff.Attributes["autoattachments.0.id"]
+			if planned != (tc.queries > 0) {
+				t.Errorf("autoattachments planned: %t, want %t", planned, tc.queries > 0)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package spacelift
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/validations"
+)
+
+func dataAutoattachments() *schema.Resource {
+	return &schema.Resource{
+		Description: "" +
+			"`spacelift_autoattachments` previews the contexts, policies and cloud " +
+			"integrations which would attach themselves through `autoattach:` labels " +
+			"to a stack with the given labels in the given space. An entity is " +
+			"considered if it is in the stack's space, or in an ancestor space the " +
+			"stack inherits entities from. Cloud integrations are only considered " +
+			"if they have `autoattach_enabled` set.",
+
+		ReadContext: dataAutoattachmentsRead,
+
+		Schema: map[string]*schema.Schema{
+			"space_id": {
+				Type:             schema.TypeString,
+				Description:      "ID (slug) of the space the stack is in",
+				Required:         true,
+				ValidateDiagFunc: validations.DisallowEmptyString,
+			},
+			"labels": {
+				Type:        schema.TypeSet,
+				Description: "Labels of the stack",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+			},
+			"autoattachments": {
+				Type:        schema.TypeList,
+				Description: "Entities which would be attached to the stack, sorted by type and ID",
+				Computed:    true,
+				Elem:        autoattachmentSchema(),
+			},
+		},
+	}
+}
+
+func dataAutoattachmentsRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	spaceID := d.Get("space_id").(string)
+
+	labels := autoattachLabels(d.Get("labels").(*schema.Set))
+
+	attachments, err := previewAutoattachments(ctx, meta.(*internal.Client), spaceID, labels)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", spaceID, strings.Join(labels, ",")))
+
+	d.Set("autoattachments", flattenAutoattachments(attachments))
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package spacelift
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+
+	. "github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/testhelpers"
+)
+
+func TestAutoattachmentsData(t *testing.T) {
+	randomID := acctest.RandStringFromCharSet(5, acctest.CharSetAlphaNum)
+
+	testSteps(t, []resource.TestStep{{
+		Config: fmt.Sprintf(`
+			resource "spacelift_context" "test" {
+				name     = "Autoattach context %s"
+				labels   = ["autoattach:preview-%s"]
+				space_id = "root"
+			}
+
+			resource "spacelift_policy" "test" {
+				name     = "Autoattach policy %s"
+				body     = "package spacelift"
+				type     = "PLAN"
+				labels   = ["autoattach:preview-%s"]
+				space_id = "root"
+			}
+
+			resource "spacelift_stack" "test" {
+				name       = "Autoattach stack %s"
+				branch     = "master"
+				repository = "demo"
+				labels     = ["preview-%s"]
+				space_id   = "root"
+
+				depends_on = [spacelift_context.test, spacelift_policy.test]
+			}
+
+			data "spacelift_autoattachments" "test" {
+				space_id = "root"
+				labels   = ["preview-%s"]
+
+				depends_on = [spacelift_context.test, spacelift_policy.test]
+			}
+		`, randomID, randomID, randomID, randomID, randomID, randomID, randomID),
+		Check: resource.ComposeTestCheckFunc(
+			Resource(
+				"data.spacelift_autoattachments.test",
+				Attribute("id", Equals("root/preview-"+randomID)),
+				Nested("autoattachments", CheckInList(
+					Attribute("type", Equals("CONTEXT")),
+					Attribute("label", Equals("autoattach:preview-"+randomID)),
+				)),
+				Nested("autoattachments", CheckInList(
+					Attribute("type", Equals("POLICY")),
+					Attribute("space_id", Equals("root")),
+				)),
+			),
+			Resource(
+				"spacelift_stack.test",
+				Nested("autoattachments", CheckInList(
+					Attribute("type", Equals("CONTEXT")),
+					Attribute("label", Equals("autoattach:preview-"+randomID)),
+				)),
+			),
+		),
+	}})
+}
@@ -0,0 +1,198 @@
+package spacelift
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/graphql"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/validations"
+)
+
+// runUnplannedStates are the states of a run which has not produced a plan yet.
+var runUnplannedStates = []string{"QUEUED", "READY", "PREPARING", "INITIALIZING", "PLANNING", "PREPARING_REPLAN", "REPLAN_REQUESTED"}
+
+func dataRunChanges() *schema.Resource {
+	return &schema.Resource{
+		Description: "" +
+			"`spacelift_run_changes` returns the resource changes planned by a " +
+			"tracked or proposed run, along with the summary counts, so that " +
+			"downstream configuration can be gated on what a plan would do. The " +
+			"run must have finished planning, e.g. by using a `wait` block on " +
+			"the `spacelift_run` which triggers it.",
+
+		ReadContext: dataRunChangesRead,
+
+		Schema: map[string]*schema.Schema{
+			"stack_id": {
+				Type:             schema.TypeString,
+				Description:      "ID (slug) of the stack the run belongs to",
+				Required:         true,
+				ValidateDiagFunc: validations.DisallowEmptyString,
+			},
+			"run_id": {
+				Type:             schema.TypeString,
+				Description:      "ID of the run",
+				Required:         true,
+				ValidateDiagFunc: validations.DisallowEmptyString,
+			},
+			"changes": {
+				Type:        schema.TypeList,
+				Description: "Planned resource changes, sorted by address",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"address": {
+							Type:        schema.TypeString,
+							Description: "Address of the resource",
+							Computed:    true,
+						},
+						"action": {
+							Type:        schema.TypeString,
+							Description: "Planned action: `create`, `update`, `delete`, `replace`, or the lowercased change type reported by Spacelift for anything else, e.g. `import`",
+							Computed:    true,
+						},
+						"replace": {
+							Type:        schema.TypeBool,
+							Description: "Whether the resource is replaced, i.e. deleted and created again",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"replaced_addresses": {
+				Type:        schema.TypeList,
+				Description: "Addresses of the resources which are replaced, sorted",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"delta_added": {
+				Type:        schema.TypeInt,
+				Description: "Number of resources the run adds",
+				Computed:    true,
+			},
+			"delta_changed": {
+				Type:        schema.TypeInt,
+				Description: "Number of resources the run changes",
+				Computed:    true,
+			},
+			"delta_deleted": {
+				Type:        schema.TypeInt,
+				Description: "Number of resources the run deletes",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataRunChangesRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	stackID := d.Get("stack_id").(string)
+	runID := d.Get("run_id").(string)
+
+	run, err := getRunChanges(ctx, meta.(*internal.Client), stackID, runID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if run == nil {
+		return diag.Errorf("run %s not found on stack %s", runID, stackID)
+	}
+
+	if slices.Contains(runUnplannedStates, run.State) {
+		return diag.Errorf("run %s on stack %s has not finished planning yet (it is %s); wait for it, e.g. with a wait block on the spacelift_run", runID, stackID, strings.ToLower(run.State))
+	}
+
+	changes := make([]any, 0, len(run.Changes))
+	replaced := []string{}
+
+	sorted := slices.Clone(run.Changes)
+	slices.SortStableFunc(sorted, func(a, b structs.RunResourceChange) int { return strings.Compare(a.Address, b.Address) })
+
+	for _, change := range sorted {
+		action, replace := runChangeAction(change.Metadata.Type)
+
+		changes = append(changes, map[string]any{
+			"address": change.Address,
+			"action":  action,
+			"replace": replace,
+		})
+
+		if replace {
+			replaced = append(replaced, change.Address)
+		}
+	}
+
+	var delta structs.RunDelta
+	if run.Delta != nil {
+		delta = *run.Delta
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", stackID, runID))
+
+	if err := d.Set("changes", changes); err != nil {
+		return diag.Errorf("could not set changes: %v", err)
+	}
+
+	d.Set("replaced_addresses", replaced)
+	d.Set("delta_added", delta.AddCount)
+	d.Set("delta_changed", delta.ChangeCount)
+	d.Set("delta_deleted", delta.DeleteCount)
+
+	return nil
+}
+
+// runChanges is the plan of a run, along with the state the run is in.
+type runChanges struct {
+	State   string                      `graphql:"state"`
+	Delta   *structs.RunDelta           `graphql:"delta"`
+	Changes []structs.RunResourceChange `graphql:"changes"`
+}
+
+// getRunChanges returns the changes planned by a run. It returns nil if the
+// run does not exist.
+func getRunChanges(ctx context.Context, client *internal.Client, stackID, runID string) (*runChanges, error) {
+	var query struct {
+		Stack *struct {
+			Run *runChanges `graphql:"run(id: $runId)"`
+		} `graphql:"stack(id: $stackId)"`
+	}
+
+	variables := map[string]any{
+		"stackId": graphql.ID(stackID),
+		"runId":   graphql.ID(runID),
+	}
+
+	if err := client.Query(ctx, "RunChangesRead", &query, variables); err != nil {
+		return nil, errors.Wrapf(err, "could not query for changes of run %s", runID)
+	}
+
+	if query.Stack == nil {
+		return nil, nil
+	}
+
+	return query.Stack.Run, nil
+}
+
+// runChangeAction maps the change types reported by Spacelift onto the
+// actions Terraform uses in its plans.
+func runChangeAction(changeType string) (action string, replace bool) {
+	switch {
+	case changeType == "ADDED":
+		return "create", false
+	case changeType == "CHANGED":
+		return "update", false
+	case changeType == "DELETED":
+		return "delete", false
+	case strings.HasPrefix(changeType, "REPLACE"):
+		return "replace", true
+	default:
+		return strings.ToLower(changeType), false
+	}
+}
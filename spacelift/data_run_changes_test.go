@@ -0,0 +1,147 @@
+package spacelift
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	. "github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/testhelpers"
+)
+
+func TestRunChangesData(t *testing.T) {
+	randomID := acctest.RandStringFromCharSet(5, acctest.CharSetAlphaNum)
+
+	testSteps(t, []resource.TestStep{{
+		Config: fmt.Sprintf(`
+			resource "spacelift_stack" "test" {
+				name       = "Test stack %s"
+				branch     = "master"
+				repository = "demo"
+			}
+
+			resource "spacelift_run" "test" {
+				stack_id = spacelift_stack.test.id
+				proposed = true
+
+				wait {
+					continue_on_state = ["finished"]
+				}
+			}
+
+			data "spacelift_run_changes" "test" {
+				stack_id = spacelift_run.test.stack_id
+				run_id   = spacelift_run.test.id
+			}
+		`, randomID),
+		Check: Resource(
+			"data.spacelift_run_changes.test",
+			Attribute("id", Contains("/")),
+			Attribute("delta_added", IsNotEmpty()),
+			Attribute("delta_deleted", Equals("0")),
+		),
+	}})
+}
+
+// runChange is a change planned by a run, as returned by the API.
+func runChange(address, changeType string) map[string]any {
+	return map[string]any{"address": address, "metadata": map[string]string{"type": changeType}}
+}
+
+func TestRunChangesRead(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		run     map[string]any
+		wantErr string
+		check   func(t *testing.T, d *schema.ResourceData)
+	}{
+		{
+			name: "planned run",
+			run: map[string]any{
+				"state": "UNCONFIRMED",
+				"delta": map[string]int{"addCount": 1, "changeCount": 1, "deleteCount": 2},
+				"changes": []any{
+					runChange("random_pet.name", "REPLACE_DESTROY_BEFORE_CREATE"),
+					runChange("aws_s3_bucket.logs", "DELETED"),
+					runChange("aws_iam_role.ci", "CHANGED"),
+				},
+			},
+			check: func(t *testing.T, d *schema.ResourceData) {
+				if got := d.Get("changes.#").(int); got != 3 {
+					t.Fatalf("got %d changes, want 3", got)
+				}
+
+				if got := d.Get("changes.0.address"); got != "aws_iam_role.ci" {
+					t.Errorf("changes are not sorted, first is %v", got)
+				}
+
+				if got := d.Get("changes.2").(map[string]any); got["action"] != "replace" || got["replace"] != true {
+					t.Errorf("unexpected replacement: %v", got)
+				}
+
+				if got := d.Get("replaced_addresses").([]any); len(got) != 1 || got[0] != "random_pet.name" {
+					t.Errorf("unexpected replaced addresses: %v", got)
+				}
+
+				if got := d.Get("delta_deleted"); got != 2 {
+					t.Errorf("got %v deletions, want 2", got)
+				}
+			},
+		},
+		{
+			name:    "run still planning",
+			run:     map[string]any{"state": "PLANNING", "delta": nil, "changes": []any{}},
+			wantErr: "has not finished planning yet (it is planning)",
+		},
+		{
+			name:    "missing run",
+			wantErr: "run run-id not found on stack stack-id",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := NewGraphQLServer(t, map[string]GraphQLHandler{
+				"RunChangesRead": func(GraphQLRequest) any {
+					return map[string]any{"stack": map[string]any{"run": tc.run}}
+				},
+			})
+
+			d := schema.TestResourceDataRaw(t, dataRunChanges().Schema, map[string]any{
+				"stack_id": "stack-id",
+				"run_id":   "run-id",
+			})
+
+			diags := dataRunChangesRead(context.Background(), d, server.Client())
+
+			if tc.wantErr != "" {
+				if !diags.HasError() || !strings.Contains(diags[0].Summary, tc.wantErr) {
+					t.Fatalf("expected error %q, got %v", tc.wantErr, diags)
+				}
+				return
+			}
+
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+
+			tc.check(t, d)
+		})
+	}
+}
+
+func TestRunChangeAction(t *testing.T) {
+	for changeType, want := range map[string]string{
+		"ADDED":                         "create",
+		"CHANGED":                       "update",
+		"DELETED":                       "delete",
+		"REPLACE_CREATE_BEFORE_DESTROY": "replace",
+		"IMPORT":                        "import",
+	} {
+		if action, replace := runChangeAction(changeType); action != want || replace != (want == "replace") {
+			t.Errorf("%s: got %s (replace %t), want %s", changeType, action, replace, want)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package spacelift
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/validations"
+)
+
+func dataRunLogs() *schema.Resource {
+	return &schema.Resource{
+		Description: "" +
+			"`spacelift_run_logs` returns the logs of each phase of a run, such as " +
+			"initializing, planning and applying. The number of lines returned " +
+			"per phase is capped, and at most " + fmt.Sprint(structs.RunLogsMaxPages) + " " +
+			"pages of logs are fetched per phase; logs of runs which are still in " +
+			"progress are returned as far as they go.",
+
+		ReadContext: dataRunLogsRead,
+
+		Schema: map[string]*schema.Schema{
+			"stack_id": {
+				Type:             schema.TypeString,
+				Description:      "ID (slug) of the stack the run belongs to",
+				Required:         true,
+				ValidateDiagFunc: validations.DisallowEmptyString,
+			},
+			"run_id": {
+				Type:             schema.TypeString,
+				Description:      "ID of the run",
+				Required:         true,
+				ValidateDiagFunc: validations.DisallowEmptyString,
+			},
+			"phases": {
+				Type:        schema.TypeList,
+				Description: "Phases to return the logs of, out of `PREPARING`, `INITIALIZING`, `PLANNING`, `APPLYING`, `PERFORMING` and `DESTROYING`. Defaults to every phase the run went through.",
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(structs.RunLogPhases, false),
+				},
+			},
+			"max_lines": {
+				Type:         schema.TypeInt,
+				Description:  "Maximum number of lines returned per phase. Defaults to `1000`.",
+				Optional:     true,
+				Default:      1000,
+				ValidateFunc: validation.IntBetween(1, 10000),
+			},
+			"logs": {
+				Type:        schema.TypeList,
+				Description: "Logs of the phases, in the order the run went through them",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"phase": {
+							Type:        schema.TypeString,
+							Description: "Phase of the run",
+							Computed:    true,
+						},
+						"content": {
+							Type:        schema.TypeString,
+							Description: "Log lines of the phase, separated by newlines",
+							Computed:    true,
+						},
+						"truncated": {
+							Type:        schema.TypeBool,
+							Description: "Whether lines were left out because of `max_lines`",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataRunLogsRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*internal.Client)
+
+	stackID := d.Get("stack_id").(string)
+	runID := d.Get("run_id").(string)
+	maxLines := d.Get("max_lines").(int)
+
+	history, err := structs.GetRunHistory(ctx, client, stackID, runID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if history == nil {
+		return diag.Errorf("run %s not found on stack %s", runID, stackID)
+	}
+
+	phases := structs.LoggedPhases(history)
+	if requested, ok := d.GetOk("phases"); ok {
+		phases = nil
+		for _, phase := range requested.([]any) {
+			phases = append(phases, phase.(string))
+		}
+	}
+
+	var diags diag.Diagnostics
+
+	logs := make([]any, 0, len(phases))
+	for _, phase := range phases {
+		phaseLogs, err := structs.GetRunPhaseLogs(ctx, client, stackID, runID, phase, maxLines, false)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		if phaseLogs.PageLimitReached {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("%s logs of run %s were cut short", strings.ToLower(phase), runID),
+				Detail:   fmt.Sprintf("Only the first %d pages of the logs were fetched, and they held fewer than %d lines.", structs.RunLogsMaxPages, maxLines),
+			})
+		}
+
+		logs = append(logs, map[string]any{
+			"phase":     phaseLogs.Phase,
+			"content":   strings.Join(phaseLogs.Lines, "\n"),
+			"truncated": phaseLogs.Truncated,
+		})
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", stackID, runID))
+
+	if err := d.Set("logs", logs); err != nil {
+		return diag.Errorf("could not set logs: %v", err)
+	}
+
+	return diags
+}
@@ -0,0 +1,181 @@
+package spacelift
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs"
+	. "github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/testhelpers"
+)
+
+func TestRunLogsData(t *testing.T) {
+	randomID := acctest.RandStringFromCharSet(5, acctest.CharSetAlphaNum)
+
+	testSteps(t, []resource.TestStep{{
+		Config: fmt.Sprintf(`
+			resource "spacelift_stack" "test" {
+				name       = "Test stack %s"
+				branch     = "master"
+				repository = "demo"
+			}
+
+			resource "spacelift_run" "test" {
+				stack_id = spacelift_stack.test.id
+				proposed = true
+
+				wait {
+					continue_on_state = ["finished", "failed"]
+				}
+			}
+
+			data "spacelift_run_logs" "test" {
+				stack_id  = spacelift_run.test.stack_id
+				run_id    = spacelift_run.test.id
+				max_lines = 50
+			}
+		`, randomID),
+		Check: Resource(
+			"data.spacelift_run_logs.test",
+			Attribute("logs.#", IsNotEmpty()),
+			Attribute("logs.0.phase", Equals("PREPARING")),
+			Attribute("logs.0.content", IsNotEmpty()),
+		),
+	}})
+}
+
+// fakeRunLogsServer serves the history of a run and the logs of its phases,
+// two lines per page.
+func fakeRunLogsServer(t *testing.T, history []string, logs map[string][]string) *GraphQLServer {
+	return NewGraphQLServer(t, map[string]GraphQLHandler{
+		"RunHistoryRead": func(GraphQLRequest) any {
+			var transitions []map[string]any
+			for i, state := range history {
+				transitions = append(transitions, map[string]any{"state": state, "timestamp": 100 + i})
+			}
+			return map[string]any{"stack": map[string]any{"run": map[string]any{"history": transitions}}}
+		},
+		"RunLogsRead": func(r GraphQLRequest) any {
+			lines := logs[r.Variable("state")]
+
+			offset := 0
+			fmt.Sscanf(r.Variable("token"), "page-%d", &offset)
+			end := min(offset+2, len(lines))
+
+			var messages []map[string]string
+			for _, line := range lines[offset:end] {
+				messages = append(messages, map[string]string{"message": line + "\n"})
+			}
+
+			return map[string]any{"stack": map[string]any{"run": map[string]any{"logs": map[string]any{
+				"hasMore":   end < len(lines),
+				"nextToken": fmt.Sprintf("page-%d", end),
+				"messages":  messages,
+			}}}}
+		},
+	})
+}
+
+func TestGetRunPhaseLogs(t *testing.T) {
+	lines := []string{"one", "two", "three", "four", "five"}
+
+	var endless []string
+	for i := range 2*structs.RunLogsMaxPages + 3 {
+		endless = append(endless, fmt.Sprintf("line %d", i))
+	}
+
+	for _, tc := range []struct {
+		name                 string
+		lines                []string
+		maxLines             int
+		tail                 bool
+		wantLines            []string
+		wantTruncated        bool
+		wantPageLimitReached bool
+		wantPages            int
+	}{
+		{name: "everything", lines: lines, maxLines: 10, wantLines: lines, wantPages: 3},
+		{name: "head", lines: lines, maxLines: 3, wantLines: []string{"one", "two", "three"}, wantTruncated: true, wantPages: 2},
+		{name: "exact head", lines: lines, maxLines: 4, wantLines: []string{"one", "two", "three", "four"}, wantTruncated: true, wantPages: 2},
+		{name: "tail", lines: lines, maxLines: 2, tail: true, wantLines: []string{"four", "five"}, wantTruncated: true, wantPages: 3},
+		{
+			name:                 "tail beyond the page limit",
+			lines:                endless,
+			maxLines:             2,
+			tail:                 true,
+			wantLines:            endless[2*structs.RunLogsMaxPages-2 : 2*structs.RunLogsMaxPages],
+			wantTruncated:        true,
+			wantPageLimitReached: true,
+			wantPages:            structs.RunLogsMaxPages,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := fakeRunLogsServer(t, nil, map[string][]string{"PLANNING": tc.lines})
+
+			logs, err := structs.GetRunPhaseLogs(context.Background(), server.Client(), "stack", "run", "PLANNING", tc.maxLines, tc.tail)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if strings.Join(logs.Lines, ",") != strings.Join(tc.wantLines, ",") {
+				t.Errorf("got lines %v, want %v", logs.Lines, tc.wantLines)
+			}
+
+			if logs.Truncated != tc.wantTruncated {
+				t.Errorf("got truncated %t, want %t", logs.Truncated, tc.wantTruncated)
+			}
+
+			if logs.PageLimitReached != tc.wantPageLimitReached {
+				t.Errorf("got page limit reached %t, want %t", logs.PageLimitReached, tc.wantPageLimitReached)
+			}
+
+			if pages := len(server.Requests("RunLogsRead")); pages != tc.wantPages {
+				t.Errorf("fetched %d pages, want %d", pages, tc.wantPages)
+			}
+		})
+	}
+}
+
+func TestFailedPhaseLogsTail(t *testing.T) {
+	server := fakeRunLogsServer(
+		t,
+		[]string{"QUEUED", "PREPARING", "INITIALIZING", "PLANNING", "FAILED"},
+		map[string][]string{
+			"INITIALIZING": {"Initializing modules..."},
+			"PLANNING":     {"Planning...", "Error: Invalid reference", "  on main.tf line 3"},
+		},
+	)
+
+	logs, err := structs.FailedPhaseLogsTail(context.Background(), server.Client(), "stack", "run", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if logs.Phase != "PLANNING" {
+		t.Errorf("got logs of %s, want PLANNING", logs.Phase)
+	}
+
+	if strings.Join(logs.Lines, "\n") != "Error: Invalid reference\n  on main.tf line 3" {
+		t.Errorf("unexpected tail: %q", logs.Lines)
+	}
+}
+
+func TestLoggedPhases(t *testing.T) {
+	history := []structs.RunStateTransition{
+		{State: "QUEUED"},
+		{State: "PREPARING"},
+		{State: "PLANNING"},
+		{State: "UNCONFIRMED"},
+		{State: "PLANNING"},
+		{State: "APPLYING"},
+		{State: "FINISHED"},
+	}
+
+	if got := strings.Join(structs.LoggedPhases(history), ","); got != "PREPARING,PLANNING,APPLYING" {
+		t.Errorf("got phases %s", got)
+	}
+}
@@ -0,0 +1,297 @@
+package spacelift
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/graphql"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs/search"
+)
+
+// dependencyGraphStack is a stack as needed to build its part of the
+// dependency graph.
+type dependencyGraphStack struct {
+	ID        string   `graphql:"id"`
+	Name      string   `graphql:"name"`
+	Space     string   `graphql:"space"`
+	Labels    []string `graphql:"labels"`
+	DependsOn []struct {
+		ID             string `graphql:"id"`
+		DependsOnStack struct {
+			ID string `graphql:"id"`
+		} `graphql:"dependsOnStack"`
+		References []struct {
+			OutputName    string `graphql:"outputName"`
+			InputName     string `graphql:"inputName"`
+			TriggerAlways bool   `graphql:"triggerAlways"`
+		} `graphql:"references"`
+	} `graphql:"dependsOn"`
+}
+
+func dataStackDependencyGraph() *schema.Resource {
+	return &schema.Resource{
+		Description: "" +
+			"`spacelift_stack_dependency_graph` returns the graph of dependencies " +
+			"between a selection of stacks, as built by `spacelift_stack_dependency` " +
+			"and `spacelift_stack_dependency_reference`. Dependencies on stacks " +
+			"outside the selection are left out.",
+
+		ReadContext: dataStackDependencyGraphRead,
+
+		Schema: map[string]*schema.Schema{
+			"space_id": {
+				Type:        schema.TypeString,
+				Description: "Only include stacks in this space",
+				Optional:    true,
+			},
+			"labels": {
+				Type:        schema.TypeSet,
+				Description: "Only include stacks with all of these labels",
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"nodes": {
+				Type:        schema.TypeList,
+				Description: "Stacks in the graph, sorted by ID",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"stack_id": {
+							Type:        schema.TypeString,
+							Description: "ID (slug) of the stack",
+							Computed:    true,
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Description: "Name of the stack",
+							Computed:    true,
+						},
+						"space_id": {
+							Type:        schema.TypeString,
+							Description: "ID of the space the stack is in",
+							Computed:    true,
+						},
+						"labels": {
+							Type:        schema.TypeSet,
+							Description: "Labels of the stack",
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+			"edges": {
+				Type:        schema.TypeList,
+				Description: "Dependencies between the stacks",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Description: "ID of the stack dependency",
+							Computed:    true,
+						},
+						"stack_id": {
+							Type:        schema.TypeString,
+							Description: "ID of the stack which depends on the other one",
+							Computed:    true,
+						},
+						"depends_on_stack_id": {
+							Type:        schema.TypeString,
+							Description: "ID of the stack depended on",
+							Computed:    true,
+						},
+						"references": {
+							Type:        schema.TypeList,
+							Description: "Outputs of the stack depended on passed to the other stack",
+							Computed:    true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"output_name": {
+										Type:        schema.TypeString,
+										Description: "Name of the output of the stack depended on",
+										Computed:    true,
+									},
+									"input_name": {
+										Type:        schema.TypeString,
+										Description: "Name of the input of the stack which depends on the other one",
+										Computed:    true,
+									},
+									"trigger_always": {
+										Type:        schema.TypeBool,
+										Description: "Whether the stack is triggered whenever the stack depended on finishes, even if the output did not change",
+										Computed:    true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"topological_order": {
+				Type:        schema.TypeList,
+				Description: "IDs of the stacks, each after the stacks it depends on. Stacks which depend on each other in a cycle are listed next to each other.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"strongly_connected_components": {
+				Type:        schema.TypeList,
+				Description: "Groups of stacks which depend on each other in a cycle, each in the same order as `topological_order`. Empty if the dependencies form a directed acyclic graph.",
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeList,
+					Elem: &schema.Schema{Type: schema.TypeString},
+				},
+			},
+			"dot": {
+				Type:        schema.TypeString,
+				Description: "The graph in the Graphviz DOT language, with edges pointing from the stacks depended on to the stacks depending on them",
+				Computed:    true,
+			},
+			"mermaid": {
+				Type:        schema.TypeString,
+				Description: "The graph as a Mermaid flowchart, with edges pointing from the stacks depended on to the stacks depending on them",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataStackDependencyGraphRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	var conditions []search.SearchQueryPredicate
+	if spaceID, ok := d.GetOk("space_id"); ok {
+		conditions = append(conditions, search.SearchQueryPredicate{
+			Field: "space",
+			Constraint: search.SearchQueryFieldConstraint{
+				StringMatches: &[]graphql.String{graphql.String(spaceID.(string))},
+			},
+		})
+	}
+
+	stacks, err := searchDependencyGraphStacks(ctx, meta.(*internal.Client), conditions)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stacks = internal.FilterByRequiredLabels(d, stacks, func(stack dependencyGraphStack) []string { return stack.Labels })
+
+	graph := newStackGraph()
+	for _, stack := range stacks {
+		graph.addStack(stack.ID, stack.Name)
+	}
+
+	for _, stack := range stacks {
+		for _, dependency := range stack.DependsOn {
+			if _, ok := graph.names[dependency.DependsOnStack.ID]; !ok {
+				continue
+			}
+
+			edge := stackGraphEdge{
+				ID:        dependency.ID,
+				StackID:   stack.ID,
+				DependsOn: dependency.DependsOnStack.ID,
+			}
+			for _, reference := range dependency.References {
+				edge.References = append(edge.References, stackGraphReference(reference))
+			}
+
+			graph.addEdge(edge)
+		}
+	}
+
+	nodes := make([]any, 0, len(stacks))
+	byID := map[string]dependencyGraphStack{}
+	for _, stack := range stacks {
+		byID[stack.ID] = stack
+	}
+	for _, stackID := range graph.stackIDs() {
+		stack := byID[stackID]
+		nodes = append(nodes, map[string]any{
+			"stack_id": stack.ID,
+			"name":     stack.Name,
+			"space_id": stack.Space,
+			"labels":   stack.Labels,
+		})
+	}
+
+	edges := make([]any, 0, len(graph.edges))
+	for _, edge := range graph.sortedEdges() {
+		references := make([]any, 0, len(edge.References))
+		for _, reference := range edge.References {
+			references = append(references, map[string]any{
+				"output_name":    reference.OutputName,
+				"input_name":     reference.InputName,
+				"trigger_always": reference.TriggerAlways,
+			})
+		}
+
+		edges = append(edges, map[string]any{
+			"id":                  edge.ID,
+			"stack_id":            edge.StackID,
+			"depends_on_stack_id": edge.DependsOn,
+			"references":          references,
+		})
+	}
+
+	d.SetId(fmt.Sprintf("stack-dependency-graph-%d", time.Now().UnixNano()))
+
+	for key, value := range map[string]any{
+		"nodes":                         nodes,
+		"edges":                         edges,
+		"topological_order":             graph.topologicalOrder(),
+		"strongly_connected_components": graph.cycles(),
+		"dot":                           graph.dot(),
+		"mermaid":                       graph.mermaid(),
+	} {
+		if err := d.Set(key, value); err != nil {
+			return diag.Errorf("could not set %s: %v", key, err)
+		}
+	}
+
+	return nil
+}
+
+func searchDependencyGraphStacks(ctx context.Context, client *internal.Client, conditions []search.SearchQueryPredicate) ([]dependencyGraphStack, error) {
+	var query struct {
+		SearchStacksOutput struct {
+			Edges []struct {
+				Node dependencyGraphStack `graphql:"node"`
+			} `graphql:"edges"`
+			PageInfo search.PageInfo `graphql:"pageInfo"`
+		} `graphql:"searchStacks(input: $input)"`
+	}
+
+	input := search.SearchInput{
+		First:      graphql.NewInt(50),
+		Predicates: &conditions,
+	}
+
+	var stacks []dependencyGraphStack
+
+	for {
+		variables := map[string]any{"input": input}
+
+		if err := client.Query(ctx, "StackDependencyGraphPage", &query, variables); err != nil {
+			return nil, errors.Wrap(err, "could not query for stacks")
+		}
+
+		for _, edge := range query.SearchStacksOutput.Edges {
+			stacks = append(stacks, edge.Node)
+		}
+
+		if !query.SearchStacksOutput.PageInfo.HasNextPage {
+			break
+		}
+
+		after := graphql.String(query.SearchStacksOutput.PageInfo.EndCursor)
+		input.After = &after
+	}
+
+	return stacks, nil
+}
@@ -0,0 +1,120 @@
+package spacelift
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	. "github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/testhelpers"
+)
+
+func TestStackDependencyGraphData(t *testing.T) {
+	randomID := acctest.RandStringFromCharSet(5, acctest.CharSetAlphaNum)
+
+	testSteps(t, []resource.TestStep{{
+		Config: fmt.Sprintf(`
+			resource "spacelift_stack" "upstream" {
+				name       = "Upstream %[1]s"
+				branch     = "master"
+				repository = "demo"
+				labels     = ["graph-%[1]s"]
+			}
+
+			resource "spacelift_stack" "downstream" {
+				name       = "Downstream %[1]s"
+				branch     = "master"
+				repository = "demo"
+				labels     = ["graph-%[1]s"]
+			}
+
+			resource "spacelift_stack_dependency" "test" {
+				stack_id            = spacelift_stack.downstream.id
+				depends_on_stack_id = spacelift_stack.upstream.id
+			}
+
+			resource "spacelift_stack_dependency_reference" "test" {
+				stack_dependency_id = spacelift_stack_dependency.test.id
+				output_name         = "vpc_id"
+				input_name          = "TF_VAR_vpc_id"
+			}
+
+			data "spacelift_stack_dependency_graph" "test" {
+				labels = ["graph-%[1]s"]
+
+				depends_on = [spacelift_stack_dependency_reference.test]
+			}
+		`, randomID),
+		Check: Resource(
+			"data.spacelift_stack_dependency_graph.test",
+			Attribute("nodes.#", Equals("2")),
+			Attribute("edges.#", Equals("1")),
+			Attribute("edges.0.references.0.input_name", Equals("TF_VAR_vpc_id")),
+			Attribute("topological_order.0", StartsWith("upstream-")),
+			Attribute("strongly_connected_components.#", Equals("0")),
+			Attribute("mermaid", Contains("TF_VAR_vpc_id")),
+		),
+	}})
+}
+
+// stackDependency is a dependency on an upstream stack, as returned by the
+// API.
+func stackDependency(id, upstreamID string, references ...map[string]any) map[string]any {
+	return map[string]any{
+		"id":             id,
+		"dependsOnStack": map[string]any{"id": upstreamID},
+		"references":     append([]map[string]any{}, references...),
+	}
+}
+
+func TestStackDependencyGraphRead(t *testing.T) {
+	node := func(id, name string, labels []string, dependsOn ...map[string]any) map[string]any {
+		return map[string]any{"node": map[string]any{"id": id, "name": name, "space": "root", "labels": labels, "dependsOn": dependsOn}}
+	}
+
+	server := NewGraphQLServer(t, map[string]GraphQLHandler{
+		"StackDependencyGraphPage": func(GraphQLRequest) any {
+			return map[string]any{"searchStacks": map[string]any{
+				"pageInfo": map[string]any{"hasNextPage": false},
+				"edges": []any{
+					node("app", "App", []string{"team"},
+						stackDependency("dep-1", "db", map[string]any{"outputName": "url", "inputName": "DB_URL", "triggerAlways": true}),
+						stackDependency("dep-2", "elsewhere"),
+					),
+					node("db", "DB", []string{"team"}, stackDependency("dep-3", "cache")),
+					node("cache", "Cache", []string{"team"}, stackDependency("dep-4", "db")),
+					node("other", "Other", []string{}),
+				},
+			}}
+		},
+	})
+
+	d := schema.TestResourceDataRaw(t, dataStackDependencyGraph().Schema, map[string]any{
+		"labels": []any{"team"},
+	})
+
+	if diags := dataStackDependencyGraphRead(context.Background(), d, server.Client()); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	for key, want := range map[string]any{
+		"nodes.#":                             3,
+		"nodes.0.stack_id":                    "app",
+		"edges.#":                             3,
+		"edges.0.depends_on_stack_id":         "db",
+		"edges.0.references.0.input_name":     "DB_URL",
+		"edges.0.references.0.trigger_always": true,
+		"topological_order.#":                 3,
+		"topological_order.2":                 "app",
+		"strongly_connected_components.#":     1,
+		"strongly_connected_components.0.0":   "cache",
+		"strongly_connected_components.0.1":   "db",
+	} {
+		if got := d.Get(key); got != want {
+			t.Errorf("%s = %v, want %v", key, got, want)
+		}
+	}
+}
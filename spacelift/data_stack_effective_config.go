@@ -0,0 +1,365 @@
+package spacelift
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/graphql"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/validations"
+)
+
+// hookPhases maps the hook attributes of a stack to the matching fields of
+// structs.Hooks.
+var hookPhases = []struct {
+	attribute string
+	after     bool
+	field     func(*structs.Hooks) *[]string
+}{
+	{"after_apply", true, func(h *structs.Hooks) *[]string { return &h.AfterApply }},
+	{"after_destroy", true, func(h *structs.Hooks) *[]string { return &h.AfterDestroy }},
+	{"after_init", true, func(h *structs.Hooks) *[]string { return &h.AfterInit }},
+	{"after_perform", true, func(h *structs.Hooks) *[]string { return &h.AfterPerform }},
+	{"after_plan", true, func(h *structs.Hooks) *[]string { return &h.AfterPlan }},
+	{"after_run", true, func(h *structs.Hooks) *[]string { return &h.AfterRun }},
+	{"before_apply", false, func(h *structs.Hooks) *[]string { return &h.BeforeApply }},
+	{"before_destroy", false, func(h *structs.Hooks) *[]string { return &h.BeforeDestroy }},
+	{"before_init", false, func(h *structs.Hooks) *[]string { return &h.BeforeInit }},
+	{"before_perform", false, func(h *structs.Hooks) *[]string { return &h.BeforePerform }},
+	{"before_plan", false, func(h *structs.Hooks) *[]string { return &h.BeforePlan }},
+}
+
+func dataStackEffectiveConfig() *schema.Resource {
+	configSourceSchema := func() map[string]*schema.Schema {
+		return map[string]*schema.Schema{
+			"checksum": {
+				Type:        schema.TypeString,
+				Description: "SHA-256 checksum of the winning value",
+				Computed:    true,
+			},
+			"write_only": {
+				Type:        schema.TypeBool,
+				Description: "Indicates whether the winning value is a secret which can't be read back outside a run",
+				Computed:    true,
+			},
+			"source": {
+				Type:        schema.TypeString,
+				Description: "Where the winning value comes from, either `STACK` or `CONTEXT`",
+				Computed:    true,
+			},
+			"context_id": {
+				Type:        schema.TypeString,
+				Description: "ID of the context the winning value comes from, empty if it is defined on the stack",
+				Computed:    true,
+			},
+			"context_name": {
+				Type:        schema.TypeString,
+				Description: "Name of the context the winning value comes from, empty if it is defined on the stack",
+				Computed:    true,
+			},
+			"priority": {
+				Type:        schema.TypeInt,
+				Description: "Priority of the attachment of the context the winning value comes from",
+				Computed:    true,
+			},
+			"autoattached": {
+				Type:        schema.TypeBool,
+				Description: "Indicates whether the context the winning value comes from is attached through an `autoattach:` label",
+				Computed:    true,
+			},
+			"overridden_context_ids": {
+				Type:        schema.TypeList,
+				Description: "IDs of the attached contexts which define the same entry but lose to the winning value, in priority order",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		}
+	}
+
+	environmentVariableSchema := map[string]*schema.Schema{
+		"name": {
+			Type:        schema.TypeString,
+			Description: "Name of the environment variable",
+			Computed:    true,
+		},
+		"value": {
+			Type:        schema.TypeString,
+			Description: "Winning value of the environment variable, empty if it is write-only",
+			Sensitive:   true,
+			Computed:    true,
+		},
+	}
+
+	mountedFileSchema := map[string]*schema.Schema{
+		"relative_path": {
+			Type:        schema.TypeString,
+			Description: "Relative path to the mounted file, without the /mnt/workspace/ prefix",
+			Computed:    true,
+		},
+	}
+
+	for name, attribute := range configSourceSchema() {
+		environmentVariableSchema[name] = attribute
+	}
+
+	for name, attribute := range configSourceSchema() {
+		mountedFileSchema[name] = attribute
+	}
+
+	resourceSchema := map[string]*schema.Schema{
+		"stack_id": {
+			Type:             schema.TypeString,
+			Description:      "ID (slug) of the stack",
+			Required:         true,
+			ValidateDiagFunc: validations.DisallowEmptyString,
+		},
+		"contexts": {
+			Type:        schema.TypeList,
+			Description: "Contexts attached to the stack, explicitly or through `autoattach:` labels, in priority order",
+			Computed:    true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"context_id": {
+						Type:        schema.TypeString,
+						Description: "ID of the context",
+						Computed:    true,
+					},
+					"name": {
+						Type:        schema.TypeString,
+						Description: "Name of the context",
+						Computed:    true,
+					},
+					"priority": {
+						Type:        schema.TypeInt,
+						Description: "Priority of the attachment",
+						Computed:    true,
+					},
+					"autoattached": {
+						Type:        schema.TypeBool,
+						Description: "Indicates whether the context is attached through an `autoattach:` label",
+						Computed:    true,
+					},
+				},
+			},
+		},
+		"environment_variable": {
+			Type:        schema.TypeList,
+			Description: "Environment variables a run of the stack gets, sorted by name",
+			Computed:    true,
+			Elem:        &schema.Resource{Schema: environmentVariableSchema},
+		},
+		"mounted_file": {
+			Type:        schema.TypeList,
+			Description: "Files mounted in the workspace of a run of the stack, sorted by path",
+			Computed:    true,
+			Elem:        &schema.Resource{Schema: mountedFileSchema},
+		},
+	}
+
+	for _, phase := range hookPhases {
+		resourceSchema[phase.attribute] = &schema.Schema{
+			Type:        schema.TypeList,
+			Description: "Merged list of commands executed at this stage of a run",
+			Computed:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		}
+	}
+
+	return &schema.Resource{
+		Description: "" +
+			"`spacelift_stack_effective_config` shows the configuration a run of " +
+			"the stack actually gets once the stack's own environment variables, " +
+			"mounted files and hooks are merged with those of every attached " +
+			"context, including the ones attached through `autoattach:` labels. " +
+			"The stack's own config takes precedence over any context, and a " +
+			"context attached with a lower `priority` takes precedence over one " +
+			"attached with a higher `priority`, ties being broken by context name. " +
+			"Before hooks of the contexts run ahead of the stack's own in priority " +
+			"order, while after hooks of the contexts run after the stack's own " +
+			"in reverse priority order.",
+
+		ReadContext: dataStackEffectiveConfigRead,
+
+		Schema: resourceSchema,
+	}
+}
+
+func dataStackEffectiveConfigRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	stackID := d.Get("stack_id").(string)
+
+	var query struct {
+		Stack *struct {
+			structs.Hooks
+			Config           []structs.ConfigElement          `graphql:"config"`
+			AttachedContexts []structs.StackContextAttachment `graphql:"attachedContexts"`
+		} `graphql:"stack(id: $id)"`
+	}
+
+	variables := map[string]any{"id": graphql.ID(stackID)}
+
+	if err := meta.(*internal.Client).Query(ctx, "StackEffectiveConfigRead", &query, variables); err != nil {
+		return diag.FromErr(errors.Wrap(err, "could not query for stack effective config"))
+	}
+
+	if query.Stack == nil {
+		return diag.Errorf("stack not found")
+	}
+
+	d.SetId(stackID)
+
+	attachments := sortContextAttachments(query.Stack.AttachedContexts)
+
+	contexts := make([]any, 0, len(attachments))
+	for _, attachment := range attachments {
+		contexts = append(contexts, map[string]any{
+			"context_id":   attachment.ContextID,
+			"name":         attachment.ContextName,
+			"priority":     attachment.Priority,
+			"autoattached": attachment.IsAutoattached,
+		})
+	}
+	d.Set("contexts", contexts)
+
+	var environmentVariables, mountedFiles []any
+	for _, entry := range mergeStackConfig(query.Stack.Config, attachments) {
+		flat := map[string]any{
+			"checksum":               entry.element.Checksum,
+			"write_only":             entry.element.WriteOnly,
+			"source":                 "STACK",
+			"overridden_context_ids": entry.overrides,
+		}
+
+		if source := entry.source; source != nil {
+			flat["source"] = "CONTEXT"
+			flat["context_id"] = source.ContextID
+			flat["context_name"] = source.ContextName
+			flat["priority"] = source.Priority
+			flat["autoattached"] = source.IsAutoattached
+		}
+
+		switch entry.element.Type {
+		case "ENVIRONMENT_VARIABLE":
+			flat["name"] = entry.element.ID
+			if entry.element.Value != nil {
+				flat["value"] = *entry.element.Value
+			}
+			environmentVariables = append(environmentVariables, flat)
+		case "FILE_MOUNT":
+			flat["relative_path"] = entry.element.ID
+			mountedFiles = append(mountedFiles, flat)
+		}
+	}
+	d.Set("environment_variable", environmentVariables)
+	d.Set("mounted_file", mountedFiles)
+
+	hooks := mergeStackHooks(query.Stack.Hooks, attachments)
+	for _, phase := range hookPhases {
+		d.Set(phase.attribute, *phase.field(&hooks))
+	}
+
+	return nil
+}
+
+// effectiveConfigEntry is the winning value of a config element, along with
+// the context it comes from (nil for the stack itself) and the IDs of the
+// contexts it overrides.
+type effectiveConfigEntry struct {
+	element   structs.ConfigElement
+	source    *structs.StackContextAttachment
+	overrides []string
+}
+
+// sortContextAttachments returns the attachments in priority order, ties
+// broken by context name.
+func sortContextAttachments(attachments []structs.StackContextAttachment) []structs.StackContextAttachment {
+	sorted := slices.Clone(attachments)
+	slices.SortStableFunc(sorted, func(a, b structs.StackContextAttachment) int {
+		return cmp.Or(cmp.Compare(a.Priority, b.Priority), cmp.Compare(a.ContextName, b.ContextName))
+	})
+	return sorted
+}
+
+// mergeStackConfig resolves the winning value of every config element, given
+// the stack's own config and the attachments in priority order. The result is
+// sorted by type and ID.
+func mergeStackConfig(own []structs.ConfigElement, attachments []structs.StackContextAttachment) []effectiveConfigEntry {
+	type key struct {
+		configType structs.ConfigType
+		id         string
+	}
+
+	var entries []*effectiveConfigEntry
+	byKey := make(map[key]*effectiveConfigEntry)
+
+	add := func(element structs.ConfigElement, source *structs.StackContextAttachment) {
+		k := key{element.Type, element.ID}
+
+		if winner, ok := byKey[k]; ok {
+			// Everything added later has a lower precedence, so the only
+			// thing left to do is to record what has been shadowed.
+			if source != nil {
+				winner.overrides = append(winner.overrides, source.ContextID)
+			}
+			return
+		}
+
+		entry := &effectiveConfigEntry{element: element, source: source, overrides: []string{}}
+		entries = append(entries, entry)
+		byKey[k] = entry
+	}
+
+	for _, element := range own {
+		add(element, nil)
+	}
+
+	for i := range attachments {
+		for _, element := range attachments[i].Config {
+			add(element, &attachments[i])
+		}
+	}
+
+	slices.SortFunc(entries, func(a, b *effectiveConfigEntry) int {
+		return cmp.Or(cmp.Compare(a.element.Type, b.element.Type), cmp.Compare(a.element.ID, b.element.ID))
+	})
+
+	merged := make([]effectiveConfigEntry, 0, len(entries))
+	for _, entry := range entries {
+		merged = append(merged, *entry)
+	}
+
+	return merged
+}
+
+// mergeStackHooks merges the stack's own hooks with those of the attachments,
+// which are expected in priority order. Contexts wrap the stack: their before
+// hooks run ahead of the stack's own in priority order, and their after hooks
+// run after the stack's own in reverse priority order.
+func mergeStackHooks(own structs.Hooks, attachments []structs.StackContextAttachment) structs.Hooks {
+	var merged structs.Hooks
+
+	for _, phase := range hookPhases {
+		commands := []string{}
+
+		if phase.after {
+			commands = append(commands, *phase.field(&own)...)
+			for i := len(attachments) - 1; i >= 0; i-- {
+				commands = append(commands, *phase.field(&attachments[i].ContextHooks)...)
+			}
+		} else {
+			for i := range attachments {
+				commands = append(commands, *phase.field(&attachments[i].ContextHooks)...)
+			}
+			commands = append(commands, *phase.field(&own)...)
+		}
+
+		*phase.field(&merged) = commands
+	}
+
+	return merged
+}
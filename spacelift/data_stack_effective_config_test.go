@@ -0,0 +1,219 @@
+package spacelift
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs"
+	. "github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/testhelpers"
+)
+
+func TestStackEffectiveConfigData(t *testing.T) {
+	randomID := acctest.RandStringFromCharSet(5, acctest.CharSetAlphaNum)
+
+	testSteps(t, []resource.TestStep{{
+		Config: fmt.Sprintf(`
+			resource "spacelift_stack" "test" {
+				name        = "Test stack %s"
+				branch      = "master"
+				repository  = "demo"
+				before_init = ["stack-before"]
+				after_apply = ["stack-after"]
+			}
+
+			resource "spacelift_context" "high" {
+				name        = "Effective config high %s"
+				before_init = ["high-before"]
+				after_apply = ["high-after"]
+			}
+
+			resource "spacelift_context" "low" {
+				name        = "Effective config low %s"
+				before_init = ["low-before"]
+				after_apply = ["low-after"]
+			}
+
+			resource "spacelift_context_attachment" "high" {
+				context_id = spacelift_context.high.id
+				stack_id   = spacelift_stack.test.id
+				priority   = 0
+			}
+
+			resource "spacelift_context_attachment" "low" {
+				context_id = spacelift_context.low.id
+				stack_id   = spacelift_stack.test.id
+				priority   = 5
+			}
+
+			resource "spacelift_environment_variable" "stack" {
+				stack_id = spacelift_stack.test.id
+				name     = "SHARED"
+				value    = "stack"
+			}
+
+			resource "spacelift_environment_variable" "high" {
+				context_id = spacelift_context.high.id
+				name       = "SHARED"
+				value      = "high"
+			}
+
+			resource "spacelift_environment_variable" "high_secret" {
+				context_id = spacelift_context.high.id
+				name       = "SECRET"
+				value      = "high"
+				write_only = true
+			}
+
+			resource "spacelift_environment_variable" "low_secret" {
+				context_id = spacelift_context.low.id
+				name       = "SECRET"
+				value      = "low"
+				write_only = true
+			}
+
+			data "spacelift_stack_effective_config" "test" {
+				stack_id = spacelift_stack.test.id
+
+				depends_on = [
+					spacelift_context_attachment.high,
+					spacelift_context_attachment.low,
+					spacelift_environment_variable.stack,
+					spacelift_environment_variable.high,
+					spacelift_environment_variable.high_secret,
+					spacelift_environment_variable.low_secret,
+				]
+			}
+		`, randomID, randomID, randomID),
+		Check: Resource(
+			"data.spacelift_stack_effective_config.test",
+			Attribute("contexts.#", Equals("2")),
+			Attribute("contexts.0.priority", Equals("0")),
+			Attribute("contexts.1.priority", Equals("5")),
+			Attribute("environment_variable.0.name", Equals("SECRET")),
+			Attribute("environment_variable.0.source", Equals("CONTEXT")),
+			Attribute("environment_variable.0.priority", Equals("0")),
+			Attribute("environment_variable.0.write_only", Equals("true")),
+			Attribute("environment_variable.0.checksum", IsNotEmpty()),
+			Attribute("environment_variable.0.overridden_context_ids.#", Equals("1")),
+			Attribute("environment_variable.1.name", Equals("SHARED")),
+			Attribute("environment_variable.1.source", Equals("STACK")),
+			Attribute("environment_variable.1.value", Equals("stack")),
+			Attribute("environment_variable.1.overridden_context_ids.#", Equals("1")),
+			Attribute("before_init.#", Equals("3")),
+			Attribute("before_init.0", Equals("high-before")),
+			Attribute("before_init.1", Equals("low-before")),
+			Attribute("before_init.2", Equals("stack-before")),
+			Attribute("after_apply.#", Equals("3")),
+			Attribute("after_apply.0", Equals("stack-after")),
+			Attribute("after_apply.1", Equals("low-after")),
+			Attribute("after_apply.2", Equals("high-after")),
+		),
+	}})
+}
+
+func TestMergeStackConfig(t *testing.T) {
+	value := func(v string) *string { return &v }
+
+	attachments := sortContextAttachments([]structs.StackContextAttachment{
+		{
+			ContextID:      "autoattached",
+			ContextName:    "b",
+			Priority:       1,
+			IsAutoattached: true,
+			Config: []structs.ConfigElement{
+				{ID: "REGION", Type: "ENVIRONMENT_VARIABLE", Value: value("eu-west-1")},
+				{ID: "kubeconfig", Type: "FILE_MOUNT", Checksum: "auto", WriteOnly: true},
+			},
+		},
+		{
+			ContextID:   "explicit",
+			ContextName: "a",
+			Priority:    1,
+			Config: []structs.ConfigElement{
+				{ID: "REGION", Type: "ENVIRONMENT_VARIABLE", Value: value("us-east-1")},
+				{ID: "TOKEN", Type: "ENVIRONMENT_VARIABLE", Checksum: "explicit", WriteOnly: true},
+			},
+		},
+		{
+			ContextID:   "urgent",
+			ContextName: "z",
+			Priority:    0,
+			Config: []structs.ConfigElement{
+				{ID: "TOKEN", Type: "ENVIRONMENT_VARIABLE", Checksum: "urgent", WriteOnly: true},
+			},
+		},
+	})
+
+	if got := []string{attachments[0].ContextID, attachments[1].ContextID, attachments[2].ContextID}; !slices.Equal(got, []string{"urgent", "explicit", "autoattached"}) {
+		t.Fatalf("attachments sorted as %v", got)
+	}
+
+	own := []structs.ConfigElement{
+		{ID: "REGION", Type: "ENVIRONMENT_VARIABLE", Value: value("ap-south-1")},
+	}
+
+	merged := mergeStackConfig(own, attachments)
+
+	type result struct {
+		id        string
+		sourceID  string
+		checksum  string
+		overrides []string
+	}
+
+	var got []result
+	for _, entry := range merged {
+		r := result{id: entry.element.ID, checksum: entry.element.Checksum, overrides: entry.overrides}
+		if entry.source != nil {
+			r.sourceID = entry.source.ContextID
+		}
+		got = append(got, r)
+	}
+
+	want := []result{
+		{id: "REGION", overrides: []string{"explicit", "autoattached"}},
+		{id: "TOKEN", sourceID: "urgent", checksum: "urgent", overrides: []string{"explicit"}},
+		{id: "kubeconfig", sourceID: "autoattached", checksum: "auto", overrides: []string{}},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(got), len(want))
+	}
+
+	for i := range want {
+		if got[i].id != want[i].id || got[i].sourceID != want[i].sourceID || got[i].checksum != want[i].checksum || !slices.Equal(got[i].overrides, want[i].overrides) {
+			t.Errorf("entry %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if *merged[0].element.Value != "ap-south-1" {
+		t.Errorf("REGION = %s, want the stack's own value", *merged[0].element.Value)
+	}
+}
+
+func TestMergeStackHooks(t *testing.T) {
+	attachments := []structs.StackContextAttachment{
+		{ContextID: "first", ContextHooks: structs.Hooks{BeforePlan: []string{"first-before"}, AfterPlan: []string{"first-after"}}},
+		{ContextID: "second", ContextHooks: structs.Hooks{BeforePlan: []string{"second-before"}, AfterPlan: []string{"second-after"}}},
+	}
+
+	own := structs.Hooks{BeforePlan: []string{"own-before"}, AfterPlan: []string{"own-after"}}
+
+	merged := mergeStackHooks(own, attachments)
+
+	if want := []string{"first-before", "second-before", "own-before"}; !slices.Equal(merged.BeforePlan, want) {
+		t.Errorf("before_plan = %v, want %v", merged.BeforePlan, want)
+	}
+
+	if want := []string{"own-after", "second-after", "first-after"}; !slices.Equal(merged.AfterPlan, want) {
+		t.Errorf("after_plan = %v, want %v", merged.AfterPlan, want)
+	}
+
+	if merged.BeforeInit == nil || len(merged.BeforeInit) != 0 {
+		t.Errorf("before_init = %#v, want an empty list", merged.BeforeInit)
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
 
 	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
 	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs"
@@ -56,27 +57,21 @@ func dataStackOutputs() *schema.Resource {
 }
 
 func dataStackOutputsRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	var query struct {
-		Stack *struct {
-			Outputs []structs.StackOutput `graphql:"outputs"`
-		} `graphql:"stack(id: $id)"`
-	}
+	stackID := d.Get("stack_id").(string)
 
-	stackID := d.Get("stack_id")
-	variables := map[string]any{"id": toID(stackID)}
-	if err := meta.(*internal.Client).Query(ctx, "StackOutputsRead", &query, variables); err != nil {
-		return diag.Errorf("could not query for stack outputs: %v", err)
+	stackOutputs, found, err := getStackOutputs(ctx, meta.(*internal.Client), stackID)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
-	stack := query.Stack
-	if stack == nil {
+	if !found {
 		return diag.Errorf("stack not found")
 	}
 
-	d.SetId(stackID.(string))
+	d.SetId(stackID)
 
-	outputs := make([]any, 0, len(stack.Outputs))
-	for _, output := range stack.Outputs {
+	outputs := make([]any, 0, len(stackOutputs))
+	for _, output := range stackOutputs {
 		outputs = append(outputs, map[string]any{
 			"id":          output.ID,
 			"description": output.Description,
@@ -87,3 +82,24 @@ func dataStackOutputsRead(ctx context.Context, d *schema.ResourceData, meta any)
 
 	return nil
 }
+
+// getStackOutputs returns the metadata of the outputs of a stack, and whether
+// the stack exists at all.
+func getStackOutputs(ctx context.Context, client *internal.Client, stackID string) ([]structs.StackOutput, bool, error) {
+	var query struct {
+		Stack *struct {
+			Outputs []structs.StackOutput `graphql:"outputs"`
+		} `graphql:"stack(id: $id)"`
+	}
+
+	variables := map[string]any{"id": toID(stackID)}
+	if err := client.Query(ctx, "StackOutputsRead", &query, variables); err != nil {
+		return nil, false, errors.Errorf("could not query for stack outputs: %v", err)
+	}
+
+	if query.Stack == nil {
+		return nil, false, nil
+	}
+
+	return query.Stack.Outputs, true, nil
+}
@@ -0,0 +1,323 @@
+package spacelift
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/graphql"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs/search"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs/search/predicates"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/validations"
+)
+
+// stackRunsPageSize is the number of runs requested per search page.
+const stackRunsPageSize = 50
+
+func dataStackRuns() *schema.Resource {
+	return &schema.Resource{
+		Description: "" +
+			"`spacelift_stack_runs` lists the runs of a stack matching predicates, " +
+			"newest first. It is meant for release dashboards and gates, e.g. " +
+			"checking that the latest tracked run on a branch has finished.",
+
+		ReadContext: dataStackRunsRead,
+
+		Schema: map[string]*schema.Schema{
+			"stack_id": {
+				Type:             schema.TypeString,
+				Description:      "ID (slug) of the stack",
+				Required:         true,
+				ValidateDiagFunc: validations.DisallowEmptyString,
+			},
+
+			// Search predicates.
+			"branch":       predicates.StringField("Require runs to be on one of the branches", 1),
+			"commit":       predicates.StringField("Require runs to be for one of the commit SHAs", 1),
+			"state":        predicates.StringField("Require runs to be in one of the states, e.g. `FINISHED` or `FAILED`", 1),
+			"triggered_by": predicates.StringField("Require runs to be triggered by one of the users", 1),
+			"type":         predicates.StringField("Require runs to be of one of the types: `PROPOSED`, `TRACKED`, `TASK`, `TESTING` or `DESTROY`", 1),
+			"created_after": {
+				Type:         schema.TypeString,
+				Description:  "Require runs to be created at or after this time, in RFC3339 format",
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"created_before": {
+				Type:         schema.TypeString,
+				Description:  "Require runs to be created at or before this time, in RFC3339 format",
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"limit": {
+				Type:         schema.TypeInt,
+				Description:  "Maximum number of runs to return. If not set, all matching runs are returned.",
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+
+			// Results.
+			"runs": {
+				Type:        schema.TypeList,
+				Description: "Runs matching the predicates, newest first",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Description: "ID of the run",
+							Computed:    true,
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Description: "Type of the run",
+							Computed:    true,
+						},
+						"state": {
+							Type:        schema.TypeString,
+							Description: "Current state of the run",
+							Computed:    true,
+						},
+						"finished": {
+							Type:        schema.TypeBool,
+							Description: "Whether the run has reached a terminal state",
+							Computed:    true,
+						},
+						"branch": {
+							Type:        schema.TypeString,
+							Description: "Branch the run was triggered on",
+							Computed:    true,
+						},
+						"commit_sha": {
+							Type:        schema.TypeString,
+							Description: "SHA of the commit the run was triggered for",
+							Computed:    true,
+						},
+						"commit_message": {
+							Type:        schema.TypeString,
+							Description: "Message of the commit the run was triggered for",
+							Computed:    true,
+						},
+						"commit_author": {
+							Type:        schema.TypeString,
+							Description: "Login of the author of the commit if known, their name otherwise",
+							Computed:    true,
+						},
+						"triggered_by": {
+							Type:        schema.TypeString,
+							Description: "User who triggered the run, empty if it was triggered by Spacelift itself, e.g. by a push",
+							Computed:    true,
+						},
+						"created_at": {
+							Type:        schema.TypeInt,
+							Description: "Unix timestamp when the run was created",
+							Computed:    true,
+						},
+						"updated_at": {
+							Type:        schema.TypeInt,
+							Description: "Unix timestamp when the run was last updated",
+							Computed:    true,
+						},
+						"delta_added": {
+							Type:        schema.TypeInt,
+							Description: "Number of resources the run adds",
+							Computed:    true,
+						},
+						"delta_changed": {
+							Type:        schema.TypeInt,
+							Description: "Number of resources the run changes",
+							Computed:    true,
+						},
+						"delta_deleted": {
+							Type:        schema.TypeInt,
+							Description: "Number of resources the run deletes",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataStackRunsRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	stackID := d.Get("stack_id").(string)
+
+	conditions, err := stackRunsConditions(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	runs, err := searchStackRuns(ctx, meta.(*internal.Client), stackID, conditions, d.Get("limit").(int))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if runs == nil {
+		return diag.Errorf("stack not found")
+	}
+
+	d.SetId(fmt.Sprintf("stack-runs-%s-%d", stackID, time.Now().UnixNano()))
+
+	flattened := make([]any, 0, len(runs))
+	for _, run := range runs {
+		flattened = append(flattened, flattenStackRun(run))
+	}
+
+	if err := d.Set("runs", flattened); err != nil {
+		return diag.Errorf("could not set runs: %v", err)
+	}
+
+	return nil
+}
+
+func stackRunsConditions(d *schema.ResourceData) ([]search.SearchQueryPredicate, error) {
+	var conditions []search.SearchQueryPredicate
+
+	conditions = append(conditions, predicates.BuildStringOrEnum(d, false, "branch")...)
+	conditions = append(conditions, predicates.BuildStringOrEnum(d, false, "commit")...)
+	conditions = append(conditions, predicates.BuildStringOrEnum(d, true, "state")...)
+	conditions = append(conditions, predicates.BuildStringOrEnum(d, false, "triggered_by", "triggeredBy")...)
+	conditions = append(conditions, predicates.BuildStringOrEnum(d, true, "type")...)
+
+	start, err := stackRunsTimeBound(d, "created_after")
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := stackRunsTimeBound(d, "created_before")
+	if err != nil {
+		return nil, err
+	}
+
+	if start != nil && end != nil && *start > *end {
+		return nil, errors.New("created_after must not be later than created_before")
+	}
+
+	if start != nil || end != nil {
+		conditions = append(conditions, predicates.TimeInRange("createdAt", start, end))
+	}
+
+	return conditions, nil
+}
+
+func stackRunsTimeBound(d *schema.ResourceData, key string) (*int64, error) {
+	raw, ok := d.GetOk(key)
+	if !ok {
+		return nil, nil
+	}
+
+	timestamp, err := time.Parse(time.RFC3339, raw.(string))
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %s", key)
+	}
+
+	return new(timestamp.Unix()), nil
+}
+
+// searchStackRuns pages through the runs of a stack matching the conditions,
+// newest first, stopping after limit runs unless limit is 0. It returns nil if
+// the stack does not exist.
+func searchStackRuns(ctx context.Context, client *internal.Client, stackID string, conditions []search.SearchQueryPredicate, limit int) ([]structs.StackRun, error) {
+	var query struct {
+		Stack *struct {
+			SearchRuns struct {
+				Edges []struct {
+					Node structs.StackRun `graphql:"node"`
+				} `graphql:"edges"`
+				PageInfo search.PageInfo `graphql:"pageInfo"`
+			} `graphql:"searchRuns(input: $input)"`
+		} `graphql:"stack(id: $id)"`
+	}
+
+	input := search.SearchInput{
+		Predicates: &conditions,
+		OrderBy: &search.QueryOrder{
+			Field:     "createdAt",
+			Direction: "DESC",
+		},
+	}
+
+	runs := []structs.StackRun{}
+
+	for {
+		pageSize := stackRunsPageSize
+		if limit > 0 {
+			pageSize = min(pageSize, limit-len(runs))
+		}
+		input.First = graphql.NewInt(graphql.Int(pageSize))
+
+		variables := map[string]any{
+			"id":    graphql.ID(stackID),
+			"input": input,
+		}
+
+		if err := client.Query(ctx, "StackRunsPage", &query, variables); err != nil {
+			return nil, errors.Wrap(err, "could not query for stack runs")
+		}
+
+		if query.Stack == nil {
+			return nil, nil
+		}
+
+		for _, edge := range query.Stack.SearchRuns.Edges {
+			runs = append(runs, edge.Node)
+		}
+
+		pageInfo := query.Stack.SearchRuns.PageInfo
+		if !pageInfo.HasNextPage || (limit > 0 && len(runs) >= limit) {
+			break
+		}
+
+		after := graphql.String(pageInfo.EndCursor)
+		input.After = &after
+	}
+
+	if limit > 0 && len(runs) > limit {
+		runs = runs[:limit]
+	}
+
+	return runs, nil
+}
+
+func flattenStackRun(run structs.StackRun) map[string]any {
+	author := run.Commit.AuthorName
+	if login := run.Commit.AuthorLogin; login != nil && *login != "" {
+		author = *login
+	}
+
+	var delta structs.RunDelta
+	if run.Delta != nil {
+		delta = *run.Delta
+	}
+
+	triggeredBy := ""
+	if run.TriggeredBy != nil {
+		triggeredBy = *run.TriggeredBy
+	}
+
+	return map[string]any{
+		"id":             run.ID,
+		"type":           run.Type,
+		"state":          run.State,
+		"finished":       run.Finished,
+		"branch":         run.Branch,
+		"commit_sha":     run.Commit.Hash,
+		"commit_message": strings.TrimSpace(run.Commit.Message),
+		"commit_author":  author,
+		"triggered_by":   triggeredBy,
+		"created_at":     run.CreatedAt,
+		"updated_at":     run.UpdatedAt,
+		"delta_added":    delta.AddCount,
+		"delta_changed":  delta.ChangeCount,
+		"delta_deleted":  delta.DeleteCount,
+	}
+}
@@ -0,0 +1,200 @@
+package spacelift
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	. "github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/testhelpers"
+)
+
+func TestStackRunsData(t *testing.T) {
+	randomID := acctest.RandStringFromCharSet(5, acctest.CharSetAlphaNum)
+
+	testSteps(t, []resource.TestStep{{
+		Config: fmt.Sprintf(`
+			resource "spacelift_stack" "test" {
+				name       = "Test stack %s"
+				branch     = "master"
+				repository = "demo"
+			}
+
+			resource "spacelift_run" "test" {
+				stack_id = spacelift_stack.test.id
+				proposed = true
+			}
+
+			data "spacelift_stack_runs" "test" {
+				stack_id      = spacelift_run.test.stack_id
+				created_after = "2020-01-01T00:00:00Z"
+				limit         = 5
+
+				type {
+					any_of = ["PROPOSED"]
+				}
+			}
+		`, randomID),
+		Check: Resource(
+			"data.spacelift_stack_runs.test",
+			Attribute("runs.#", Equals("1")),
+			Attribute("runs.0.type", Equals("PROPOSED")),
+			Attribute("runs.0.branch", Equals("master")),
+			Attribute("runs.0.commit_sha", IsNotEmpty()),
+			Attribute("runs.0.created_at", IsNotEmpty()),
+		),
+	}})
+}
+
+func TestSearchStackRuns(t *testing.T) {
+	type variables struct {
+		Input struct {
+			First   int    `json:"first"`
+			After   string `json:"after"`
+			OrderBy struct {
+				Field     string `json:"field"`
+				Direction string `json:"direction"`
+			} `json:"orderBy"`
+		} `json:"input"`
+	}
+
+	// The server pretends the stack has 120 runs, named after their position.
+	const total = 120
+
+	newServer := func(t *testing.T) *GraphQLServer {
+		return NewGraphQLServer(t, map[string]GraphQLHandler{
+			"StackRunsPage": func(r GraphQLRequest) any {
+				var v variables
+				if err := r.DecodeVariables(&v); err != nil {
+					return err
+				}
+
+				offset := 0
+				if after := v.Input.After; after != "" {
+					fmt.Sscanf(after, "cursor-%d", &offset)
+				}
+				end := min(offset+v.Input.First, total)
+
+				var edges []any
+				for i := offset; i < end; i++ {
+					edges = append(edges, map[string]any{"node": map[string]any{
+						"id":        fmt.Sprintf("run-%03d", i),
+						"type":      "TRACKED",
+						"state":     "FINISHED",
+						"finished":  true,
+						"commit":    map[string]any{"hash": "abc", "authorName": "Jane", "message": "Fix\n"},
+						"delta":     map[string]any{"addCount": 1, "changeCount": 2, "deleteCount": 3},
+						"createdAt": 1000 - i,
+					}})
+				}
+
+				return map[string]any{"stack": map[string]any{"searchRuns": map[string]any{
+					"edges":    edges,
+					"pageInfo": map[string]any{"endCursor": fmt.Sprintf("cursor-%d", end), "hasNextPage": end < total},
+				}}}
+			},
+		})
+	}
+
+	pages := func(t *testing.T, server *GraphQLServer) []variables {
+		var decoded []variables
+		for _, r := range server.Requests("StackRunsPage") {
+			var v variables
+			if err := r.DecodeVariables(&v); err != nil {
+				t.Fatalf("could not decode variables: %v", err)
+			}
+			decoded = append(decoded, v)
+		}
+		return decoded
+	}
+
+	t.Run("all runs", func(t *testing.T) {
+		server := newServer(t)
+
+		runs, err := searchStackRuns(context.Background(), server.Client(), "my-stack", nil, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(runs) != total || runs[0].ID != "run-000" || runs[total-1].ID != "run-119" {
+			t.Errorf("got %d runs, want %d in order", len(runs), total)
+		}
+
+		requests := pages(t, server)
+		if len(requests) != 3 {
+			t.Errorf("got %d requests, want 3", len(requests))
+		}
+
+		if order := requests[0].Input.OrderBy; order.Field != "createdAt" || order.Direction != "DESC" {
+			t.Errorf("runs are ordered by %+v, want newest first", order)
+		}
+
+		flattened := flattenStackRun(runs[0])
+		if flattened["commit_author"] != "Jane" || flattened["commit_message"] != "Fix" || flattened["delta_deleted"] != 3 {
+			t.Errorf("unexpected flattened run: %v", flattened)
+		}
+	})
+
+	t.Run("limited", func(t *testing.T) {
+		server := newServer(t)
+
+		runs, err := searchStackRuns(context.Background(), server.Client(), "my-stack", nil, 60)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(runs) != 60 {
+			t.Errorf("got %d runs, want 60", len(runs))
+		}
+
+		if requests := pages(t, server); len(requests) != 2 || requests[1].Input.First != 10 || requests[1].Input.After != "cursor-50" {
+			t.Errorf("unexpected requests: %+v", requests)
+		}
+	})
+}
+
+func TestStackRunsConditions(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, dataStackRuns().Schema, map[string]any{
+		"stack_id":       "my-stack",
+		"created_after":  "2024-01-01T00:00:00Z",
+		"created_before": "2024-01-02T00:00:00Z",
+		"type":           []any{map[string]any{"any_of": []any{"TRACKED"}}},
+		"triggered_by":   []any{map[string]any{"any_of": []any{"jane"}}},
+	})
+
+	conditions, err := stackRunsConditions(d)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fields := make(map[string]bool)
+	for _, condition := range conditions {
+		fields[string(condition.Field)] = true
+
+		if condition.Field == "createdAt" {
+			timeRange := condition.Constraint.TimeInRange
+			if timeRange == nil || *timeRange.Start != 1704067200 || *timeRange.End != 1704153600 {
+				t.Errorf("unexpected time range: %+v", timeRange)
+			}
+		}
+
+		if condition.Field == "type" && condition.Constraint.EnumEquals == nil {
+			t.Errorf("type is not matched as an enum")
+		}
+	}
+
+	for _, field := range []string{"createdAt", "triggeredBy", "type"} {
+		if !fields[field] {
+			t.Errorf("missing predicate on %s, got %v", field, fields)
+		}
+	}
+
+	d.Set("created_after", "2024-01-03T00:00:00Z")
+	if _, err := stackRunsConditions(d); err == nil || !strings.Contains(err.Error(), "created_after must not be later than created_before") {
+		t.Errorf("expected an inverted range to be refused, got %v", err)
+	}
+}
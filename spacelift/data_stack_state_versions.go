@@ -0,0 +1,126 @@
+package spacelift
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/graphql"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/validations"
+)
+
+func dataStackStateVersions() *schema.Resource {
+	return &schema.Resource{
+		Description: "" +
+			"`spacelift_stack_state_versions` lists the versions of the Terraform " +
+			"state Spacelift keeps for a stack with `manage_state` enabled, newest first.",
+
+		ReadContext: dataStackStateVersionsRead,
+
+		Schema: map[string]*schema.Schema{
+			"stack_id": {
+				Type:             schema.TypeString,
+				Description:      "ID (slug) of the stack",
+				Required:         true,
+				ValidateDiagFunc: validations.DisallowEmptyString,
+			},
+			"state_versions": {
+				Type:        schema.TypeList,
+				Description: "Versions of the state, newest first",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Description: "ID of the state version",
+							Computed:    true,
+						},
+						"serial": {
+							Type:        schema.TypeInt,
+							Description: "Serial of the state",
+							Computed:    true,
+						},
+						"lineage": {
+							Type:        schema.TypeString,
+							Description: "Lineage of the state",
+							Computed:    true,
+						},
+						"run_id": {
+							Type:        schema.TypeString,
+							Description: "ID of the run which produced the state version, empty if it was uploaded",
+							Computed:    true,
+						},
+						"created_at": {
+							Type:        schema.TypeInt,
+							Description: "Unix timestamp when the state version was created",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataStackStateVersionsRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	stackID := d.Get("stack_id").(string)
+
+	versions, err := getStackStateVersions(ctx, meta.(*internal.Client), stackID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if versions == nil {
+		return diag.Errorf("stack not found")
+	}
+
+	d.SetId(stackID)
+
+	stateVersions := make([]any, 0, len(versions))
+	for _, version := range versions {
+		stateVersions = append(stateVersions, map[string]any{
+			"id":         version.ID,
+			"serial":     version.Serial,
+			"lineage":    version.Lineage,
+			"run_id":     version.RunID,
+			"created_at": version.CreatedAt,
+		})
+	}
+	d.Set("state_versions", stateVersions)
+
+	return nil
+}
+
+// getStackStateVersions returns the state versions of the stack, newest first.
+// It returns nil if the stack does not exist, and an empty slice if it has no
+// managed state yet.
+func getStackStateVersions(ctx context.Context, client *internal.Client, stackID string) ([]structs.StateVersion, error) {
+	var query struct {
+		Stack *struct {
+			StateVersions []structs.StateVersion `graphql:"stateVersions"`
+		} `graphql:"stack(id: $id)"`
+	}
+
+	variables := map[string]any{"id": graphql.ID(stackID)}
+
+	if err := client.Query(ctx, "StackStateVersionsRead", &query, variables); err != nil {
+		return nil, errors.Wrap(err, "could not query for stack state versions")
+	}
+
+	if query.Stack == nil {
+		return nil, nil
+	}
+
+	versions := append([]structs.StateVersion{}, query.Stack.StateVersions...)
+	slices.SortStableFunc(versions, func(a, b structs.StateVersion) int {
+		return cmp.Or(cmp.Compare(b.CreatedAt, a.CreatedAt), cmp.Compare(b.Serial, a.Serial))
+	})
+
+	return versions, nil
+}
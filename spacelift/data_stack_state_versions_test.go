@@ -0,0 +1,40 @@
+package spacelift
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+
+	. "github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/testhelpers"
+)
+
+func TestStackStateVersionsData(t *testing.T) {
+	randomID := acctest.RandStringFromCharSet(5, acctest.CharSetAlphaNum)
+
+	testSteps(t, []resource.TestStep{{
+		Config: fmt.Sprintf(`
+			resource "spacelift_stack" "test" {
+				name         = "Test stack %s"
+				branch       = "master"
+				repository   = "demo"
+				manage_state = true
+				import_state = jsonencode({ version = 4, serial = 3, lineage = "test-lineage-%s", resources = [], outputs = {} })
+			}
+
+			data "spacelift_stack_state_versions" "test" {
+				stack_id = spacelift_stack.test.id
+			}
+		`, randomID, randomID),
+		Check: Resource(
+			"data.spacelift_stack_state_versions.test",
+			Attribute("id", StartsWith("test-stack-")),
+			Attribute("state_versions.#", Equals("1")),
+			Attribute("state_versions.0.id", IsNotEmpty()),
+			Attribute("state_versions.0.serial", Equals("3")),
+			Attribute("state_versions.0.lineage", Equals("test-lineage-"+randomID)),
+			Attribute("state_versions.0.created_at", IsNotEmpty()),
+		),
+	}})
+}
@@ -0,0 +1,101 @@
+package spacelift
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+// slugIdentity is the identity schema of a resource addressed by a single
+// immutable ID (slug), stored under the given attribute name.
+func slugIdentity(attribute, description string) *schema.ResourceIdentity {
+	return &schema.ResourceIdentity{
+		SchemaFunc: func() map[string]*schema.Schema {
+			return map[string]*schema.Schema{
+				attribute: {
+					Type:              schema.TypeString,
+					Description:       description,
+					RequiredForImport: true,
+				},
+			}
+		},
+	}
+}
+
+// setIdentity records the identity of the resource. Terraform stores it next to
+// the state, which lets `import` blocks and `terraform query` address the
+// resource without knowing how its ID is put together.
+func setIdentity(d *schema.ResourceData, attributes map[string]string) error {
+	identity, err := d.Identity()
+	if err != nil {
+		return err
+	}
+
+	for name, value := range attributes {
+		if err := identity.Set(name, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// attachmentIdentity is the identity schema of a resource attaching the entity
+// stored under the given attribute name to either a stack or a module.
+func attachmentIdentity(attribute, description string) *schema.ResourceIdentity {
+	return &schema.ResourceIdentity{
+		SchemaFunc: func() map[string]*schema.Schema {
+			return map[string]*schema.Schema{
+				attribute: {
+					Type:              schema.TypeString,
+					Description:       description,
+					RequiredForImport: true,
+				},
+				"module_id": {
+					Type:              schema.TypeString,
+					Description:       "ID of the module the entity is attached to",
+					OptionalForImport: true,
+				},
+				"stack_id": {
+					Type:              schema.TypeString,
+					Description:       "ID of the stack the entity is attached to",
+					OptionalForImport: true,
+				},
+			}
+		},
+	}
+}
+
+// attachmentFromIdentity returns the ID of the attached entity and the ID of
+// the stack or module it is attached to, as recorded in an attachmentIdentity.
+func attachmentFromIdentity(d *schema.ResourceData, attribute string) (string, string, error) {
+	identity, err := d.Identity()
+	if err != nil {
+		return "", "", err
+	}
+
+	entityID := identity.Get(attribute).(string)
+	stackID := identity.Get("stack_id").(string)
+	moduleID := identity.Get("module_id").(string)
+
+	switch {
+	case entityID == "":
+		return "", "", errors.Errorf("identity is missing %s", attribute)
+	case (stackID == "") == (moduleID == ""):
+		return "", "", errors.New("identity must contain exactly one of module_id and stack_id")
+	case stackID != "":
+		return entityID, stackID, nil
+	default:
+		return entityID, moduleID, nil
+	}
+}
+
+// setAttachmentIdentity records the identity of a resource attaching the
+// entity stored under the given attribute name to a stack or a module.
+func setAttachmentIdentity(d *schema.ResourceData, attribute, entityID, projectID string, isModule bool) error {
+	target := "stack_id"
+	if isModule {
+		target = "module_id"
+	}
+
+	return setIdentity(d, map[string]string{attribute: entityID, target: projectID})
+}
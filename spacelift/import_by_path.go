@@ -0,0 +1,177 @@
+package spacelift
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/graphql"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs/search"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs/search/predicates"
+)
+
+const (
+	spacePathImportPrefix    = "space:"
+	spacePathImportSeparator = "//name:"
+)
+
+// searchConnection is a page of search results, as returned by the search
+// queries of all searchable entities.
+type searchConnection struct {
+	Edges []struct {
+		Node listedEntity `graphql:"node"`
+	} `graphql:"edges"`
+	PageInfo search.PageInfo `graphql:"pageInfo"`
+}
+
+// spacePathImport describes how to find entities of a single kind by name
+// within a space, which lets them be imported by space path and name.
+type spacePathImport struct {
+	noun   string
+	search func(ctx context.Context, client *internal.Client, variables map[string]any) (*searchConnection, error)
+}
+
+var (
+	contextSpacePathImport = spacePathImport{
+		noun: "context",
+		search: func(ctx context.Context, client *internal.Client, variables map[string]any) (*searchConnection, error) {
+			var query struct {
+				Output searchConnection `graphql:"searchContexts(input: $input)"`
+			}
+			err := client.Query(ctx, "ContextsByName", &query, variables)
+			return &query.Output, err
+		},
+	}
+
+	policySpacePathImport = spacePathImport{
+		noun: "policy",
+		search: func(ctx context.Context, client *internal.Client, variables map[string]any) (*searchConnection, error) {
+			var query struct {
+				Output searchConnection `graphql:"searchPolicies(input: $input)"`
+			}
+			err := client.Query(ctx, "PoliciesByName", &query, variables)
+			return &query.Output, err
+		},
+	}
+
+	stackSpacePathImport = spacePathImport{
+		noun: "stack",
+		search: func(ctx context.Context, client *internal.Client, variables map[string]any) (*searchConnection, error) {
+			var query struct {
+				Output searchConnection `graphql:"searchStacks(input: $input)"`
+			}
+			err := client.Query(ctx, "StacksByName", &query, variables)
+			return &query.Output, err
+		},
+	}
+)
+
+// importBySpacePath wraps an importer so that, on top of whatever the importer
+// accepts, it resolves import IDs like space:root/platform/prod//name:api-gateway
+// into the ID of the single entity with that name in that space.
+func importBySpacePath(kind spacePathImport, importer schema.StateContextFunc) schema.StateContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
+		spacePath, name, ok, err := parseSpacePathImportID(d.Id())
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			id, err := kind.resolve(ctx, meta.(*internal.Client), spacePath, name)
+			if err != nil {
+				return nil, err
+			}
+
+			d.SetId(id)
+		}
+
+		return importer(ctx, d, meta)
+	}
+}
+
+// parseSpacePathImportID splits an import ID of the form
+// space:<space path>//name:<name>. It reports false for any other ID.
+func parseSpacePathImportID(id string) (spacePath, name string, ok bool, err error) {
+	rest, ok := strings.CutPrefix(id, spacePathImportPrefix)
+	if !ok {
+		return "", "", false, nil
+	}
+
+	spacePath, name, ok = strings.Cut(rest, spacePathImportSeparator)
+	if !ok || spacePath == "" || name == "" {
+		return "", "", false, fmt.Errorf("expecting import ID as %s$spacePath%s$name", spacePathImportPrefix, spacePathImportSeparator)
+	}
+
+	// Imports are not run from within a stack, so there is no current space
+	// for a relative path to start from.
+	if spacePath != "root" && !strings.HasPrefix(spacePath, "root/") {
+		return "", "", false, fmt.Errorf("space path %q must be absolute, i.e. start with root", spacePath)
+	}
+
+	return spacePath, name, true, nil
+}
+
+func (kind spacePathImport) resolve(ctx context.Context, client *internal.Client, spacePath, name string) (string, error) {
+	var spaces struct {
+		Spaces []*structs.Space `graphql:"spaces"`
+	}
+
+	if err := client.Query(ctx, "SpaceRead", &spaces, map[string]any{}); err != nil {
+		return "", errors.Wrap(err, "could not query for spaces")
+	}
+
+	space, err := findSpaceByPath(spaces.Spaces, spacePath, "root")
+	if err != nil {
+		return "", errors.Wrapf(err, "could not resolve space path %s", spacePath)
+	}
+
+	conditions := []search.SearchQueryPredicate{
+		predicates.StringOrEnum("space", false, space.ID),
+		predicates.StringOrEnum("name", false, name),
+	}
+
+	input := search.SearchInput{
+		First:      graphql.NewInt(listPageSize),
+		Predicates: &conditions,
+	}
+
+	var matches []string
+
+	for {
+		page, err := kind.search(ctx, client, map[string]any{"input": input})
+		if err != nil {
+			return "", errors.Wrapf(err, "could not search for %s %q", kind.noun, name)
+		}
+
+		// Search matches names loosely, so only exact matches count.
+		for _, edge := range page.Edges {
+			if edge.Node.Name == name {
+				matches = append(matches, edge.Node.ID)
+			}
+		}
+
+		if !page.PageInfo.HasNextPage {
+			break
+		}
+
+		after := graphql.String(page.PageInfo.EndCursor)
+		input.After = &after
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no %s named %q in space %s", kind.noun, name, spacePath)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf(
+			"%s name %q is ambiguous in space %s, matching IDs %s; import by ID instead",
+			kind.noun, name, spacePath, strings.Join(matches, ", "),
+		)
+	}
+}
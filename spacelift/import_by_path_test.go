@@ -0,0 +1,63 @@
+package spacelift
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseSpacePathImportID(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		id            string
+		wantOK        bool
+		wantSpacePath string
+		wantName      string
+		wantErr       string
+	}{
+		{
+			name: "slug",
+			id:   "api-gateway",
+		},
+		{
+			name:          "nested space",
+			id:            "space:root/platform/prod//name:api-gateway",
+			wantOK:        true,
+			wantSpacePath: "root/platform/prod",
+			wantName:      "api-gateway",
+		},
+		{
+			name:          "root space and a name with slashes",
+			id:            "space:root//name:team/api",
+			wantOK:        true,
+			wantSpacePath: "root",
+			wantName:      "team/api",
+		},
+		{
+			name:    "missing name",
+			id:      "space:root/platform",
+			wantErr: "expecting import ID as space:$spacePath//name:$name",
+		},
+		{
+			name:    "relative path",
+			id:      "space:platform/prod//name:api-gateway",
+			wantErr: `space path "platform/prod" must be absolute`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			spacePath, name, ok, err := parseSpacePathImportID(tc.id)
+
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Fatalf("expected no error, got %v", err)
+			case tc.wantErr != "" && err == nil:
+				t.Fatalf("expected an error containing %q, got none", tc.wantErr)
+			case tc.wantErr != "" && !strings.Contains(err.Error(), tc.wantErr):
+				t.Fatalf("expected an error containing %q, got %v", tc.wantErr, err)
+			}
+
+			if ok != tc.wantOK || spacePath != tc.wantSpacePath || name != tc.wantName {
+				t.Fatalf("expected (%q, %q, %t), got (%q, %q, %t)", tc.wantSpacePath, tc.wantName, tc.wantOK, spacePath, name, ok)
+			}
+		})
+	}
+}
@@ -26,3 +26,25 @@ type BlueprintCreateInput struct {
 	Labels      []graphql.String `json:"labels"`
 	Template    *graphql.String  `json:"template"`
 }
+
+// BlueprintInput is an input declared in the template of a blueprint.
+type BlueprintInput struct {
+	ID          string   `graphql:"id"`
+	Name        string   `graphql:"name"`
+	Type        string   `graphql:"type"`
+	Default     *string  `graphql:"default"`
+	Description *string  `graphql:"description"`
+	Options     []string `graphql:"options"`
+}
+
+// BlueprintStackCreateInput represents the input required to create a stack
+// from a blueprint.
+type BlueprintStackCreateInput struct {
+	TemplateInputs []BlueprintStackCreateInputPair `json:"templateInputs"`
+}
+
+// BlueprintStackCreateInputPair is the value of a single blueprint input.
+type BlueprintStackCreateInputPair struct {
+	ID    graphql.String `json:"id"`
+	Value graphql.String `json:"value"`
+}
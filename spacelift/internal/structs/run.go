@@ -15,3 +15,53 @@ type RunDiscardAll struct {
 	DiscardedRuns    []Run    `graphql:"discardedRuns"`
 	FailedDiscarding []string `graphql:"failedDiscarding"`
 }
+
+// RunCommit is the commit a run was triggered for.
+type RunCommit struct {
+	Hash        string  `graphql:"hash"`
+	AuthorLogin *string `graphql:"authorLogin"`
+	AuthorName  string  `graphql:"authorName"`
+	Message     string  `graphql:"message"`
+}
+
+// RunDelta counts the resources a run plans to add, change and delete.
+type RunDelta struct {
+	AddCount    int `graphql:"addCount"`
+	ChangeCount int `graphql:"changeCount"`
+	DeleteCount int `graphql:"deleteCount"`
+}
+
+// StackRun is a run of a stack as listed in its run history.
+type StackRun struct {
+	ID          string    `graphql:"id"`
+	Type        string    `graphql:"type"`
+	State       string    `graphql:"state"`
+	Finished    bool      `graphql:"finished"`
+	Branch      string    `graphql:"branch"`
+	Commit      RunCommit `graphql:"commit"`
+	Delta       *RunDelta `graphql:"delta"`
+	TriggeredBy *string   `graphql:"triggeredBy"`
+	CreatedAt   int       `graphql:"createdAt"`
+	UpdatedAt   int       `graphql:"updatedAt"`
+}
+
+// RunResourceChange is a change to a resource planned by a run.
+type RunResourceChange struct {
+	Address  string `graphql:"address"`
+	Metadata struct {
+		Type string `graphql:"type"`
+	} `graphql:"metadata"`
+}
+
+// RunReviewDecision is the decision of a review of a run.
+type RunReviewDecision string
+
+const (
+	RunReviewDecisionApprove RunReviewDecision = "APPROVE"
+	RunReviewDecisionReject  RunReviewDecision = "REJECT"
+)
+
+// RunReview is a review of a run, which approval policies take into account.
+type RunReview struct {
+	ID string `graphql:"id"`
+}
@@ -0,0 +1,182 @@
+package structs
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/shurcooL/graphql"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+)
+
+// RunState is the state of a run, as used to select the logs of a phase.
+type RunState string
+
+// RunLogPhases lists the states of a run which produce logs, in the order a
+// run goes through them.
+var RunLogPhases = []string{"PREPARING", "INITIALIZING", "PLANNING", "APPLYING", "PERFORMING", "DESTROYING"}
+
+// RunLogsMaxPages caps the number of pages fetched for a single phase, so that
+// a runaway log can't keep the provider busy forever. Logs can only be paged
+// through from the start, so the end of a longer log is never seen.
+const RunLogsMaxPages = 200
+
+// RunStateTransition is an entry of the history of a run.
+type RunStateTransition struct {
+	State     string `graphql:"state"`
+	Timestamp int    `graphql:"timestamp"`
+}
+
+// RunPhaseLogs are the log lines of a single phase of a run.
+type RunPhaseLogs struct {
+	Phase string
+	Lines []string
+
+	// Truncated is set if lines were left out to stay within the limits.
+	Truncated bool
+
+	// PageLimitReached is set if the logs go on beyond RunLogsMaxPages
+	// pages, in which case the last lines are not the end of the log.
+	PageLimitReached bool
+}
+
+// GetRunHistory returns the state transitions of the run, oldest first. It
+// returns nil if the run does not exist.
+func GetRunHistory(ctx context.Context, client *internal.Client, stackID, runID string) ([]RunStateTransition, error) {
+	var query struct {
+		Stack *struct {
+			Run *struct {
+				History []RunStateTransition `graphql:"history"`
+			} `graphql:"run(id: $runId)"`
+		} `graphql:"stack(id: $stackId)"`
+	}
+
+	variables := map[string]any{
+		"stackId": graphql.ID(stackID),
+		"runId":   graphql.ID(runID),
+	}
+
+	if err := client.Query(ctx, "RunHistoryRead", &query, variables); err != nil {
+		return nil, errors.Wrapf(err, "could not query for history of run %s", runID)
+	}
+
+	if query.Stack == nil || query.Stack.Run == nil {
+		return nil, nil
+	}
+
+	history := append([]RunStateTransition{}, query.Stack.Run.History...)
+	slices.SortStableFunc(history, func(a, b RunStateTransition) int { return a.Timestamp - b.Timestamp })
+
+	return history, nil
+}
+
+// LoggedPhases returns the phases a run went through which produce logs, in
+// the order it went through them.
+func LoggedPhases(history []RunStateTransition) []string {
+	var phases []string
+	for _, transition := range history {
+		if slices.Contains(RunLogPhases, transition.State) && !slices.Contains(phases, transition.State) {
+			phases = append(phases, transition.State)
+		}
+	}
+	return phases
+}
+
+// GetRunPhaseLogs pages through the logs of a phase of a run. If tail is
+// false, it stops after the first maxLines lines; otherwise it keeps the last
+// maxLines lines of at most RunLogsMaxPages pages.
+func GetRunPhaseLogs(ctx context.Context, client *internal.Client, stackID, runID, phase string, maxLines int, tail bool) (*RunPhaseLogs, error) {
+	var query struct {
+		Stack *struct {
+			Run *struct {
+				Logs *struct {
+					HasMore   bool    `graphql:"hasMore"`
+					NextToken *string `graphql:"nextToken"`
+					Messages  []struct {
+						Message string `graphql:"message"`
+					} `graphql:"messages"`
+				} `graphql:"logs(state: $state, token: $token)"`
+			} `graphql:"run(id: $runId)"`
+		} `graphql:"stack(id: $stackId)"`
+	}
+
+	logs := &RunPhaseLogs{Phase: phase}
+
+	variables := map[string]any{
+		"stackId": graphql.ID(stackID),
+		"runId":   graphql.ID(runID),
+		"state":   RunState(phase),
+		"token":   (*graphql.String)(nil),
+	}
+
+	for page := 0; ; page++ {
+		if page == RunLogsMaxPages {
+			logs.Truncated = true
+			logs.PageLimitReached = true
+			break
+		}
+
+		if err := client.Query(ctx, "RunLogsRead", &query, variables); err != nil {
+			return nil, errors.Wrapf(err, "could not query for %s logs of run %s", strings.ToLower(phase), runID)
+		}
+
+		if query.Stack == nil || query.Stack.Run == nil {
+			return nil, fmt.Errorf("run %s not found on stack %s", runID, stackID)
+		}
+
+		if query.Stack.Run.Logs == nil {
+			break
+		}
+
+		for _, message := range query.Stack.Run.Logs.Messages {
+			logs.Lines = append(logs.Lines, strings.Split(strings.TrimRight(message.Message, "\n"), "\n")...)
+		}
+
+		if tail && len(logs.Lines) > maxLines {
+			logs.Lines = slices.Clone(logs.Lines[len(logs.Lines)-maxLines:])
+			logs.Truncated = true
+		}
+
+		if !tail && len(logs.Lines) >= maxLines {
+			logs.Truncated = len(logs.Lines) > maxLines || query.Stack.Run.Logs.HasMore
+			logs.Lines = logs.Lines[:maxLines]
+			break
+		}
+
+		if !query.Stack.Run.Logs.HasMore || query.Stack.Run.Logs.NextToken == nil {
+			break
+		}
+
+		variables["token"] = graphql.NewString(graphql.String(*query.Stack.Run.Logs.NextToken))
+	}
+
+	return logs, nil
+}
+
+// FailedPhaseLogsTail returns the last lines of the logs of the last phase the
+// run went through which produces logs, which for a failed run is the phase
+// it failed in. It returns nil if the run has no logs.
+func FailedPhaseLogsTail(ctx context.Context, client *internal.Client, stackID, runID string, maxLines int) (*RunPhaseLogs, error) {
+	history, err := GetRunHistory(ctx, client, stackID, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A phase can be entered more than once, e.g. when a run is replanned, so
+	// the last transition is what counts rather than the first.
+	var lastPhase string
+	for _, transition := range history {
+		if slices.Contains(RunLogPhases, transition.State) {
+			lastPhase = transition.State
+		}
+	}
+
+	if lastPhase == "" {
+		return nil, nil
+	}
+
+	return GetRunPhaseLogs(ctx, client, stackID, runID, lastPhase, maxLines, true)
+}
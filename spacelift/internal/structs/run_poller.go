@@ -0,0 +1,371 @@
+package structs
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/graphql"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+)
+
+const (
+	// runPollerMinInterval is how long a run is left alone after it starts
+	// being watched or changes state before it is looked up again.
+	runPollerMinInterval = 10 * time.Second
+
+	// runPollerMaxInterval caps the backoff between lookups of a run which
+	// sits in the same state for a long time.
+	runPollerMaxInterval = time.Minute
+
+	// runPollerTick is how often the poller checks which runs are due, which
+	// is also the window in which due runs are batched together.
+	runPollerTick = time.Second
+
+	// runPollerBatchSize caps the number of runs looked up by a single query.
+	runPollerBatchSize = 25
+
+	// runPollerQueryTimeout bounds a single lookup, so that a request which
+	// hangs does not hold up every wait.
+	runPollerQueryTimeout = 30 * time.Second
+)
+
+type runKey struct {
+	stackID string
+	runID   string
+}
+
+// runStatus is the state of a run as seen by the poller.
+type runStatus struct {
+	state    string
+	finished bool
+	err      error
+
+	// batchErr is why the run had to be looked up on its own rather than
+	// together with other runs. The poller has no logger of its own, so it is
+	// left for the wait to log.
+	batchErr error
+}
+
+// runWatch is a single wait for a run. Its updates channel only ever holds
+// the latest status, so a slow reader never blocks the poller.
+type runWatch struct {
+	key      runKey
+	updates  chan runStatus
+	interval time.Duration
+	nextPoll time.Time
+	state    string
+}
+
+// runPoller looks up the state of every run being waited for on behalf of a
+// single client. Runs which are due at the same time are looked up with a
+// single query, and runs which sit in the same state are looked up less and
+// less often, so that many concurrent waits don't run into rate limits.
+type runPoller struct {
+	client *internal.Client
+
+	minInterval  time.Duration
+	maxInterval  time.Duration
+	tick         time.Duration
+	batchSize    int
+	queryTimeout time.Duration
+
+	mu      sync.Mutex
+	watches map[*runWatch]struct{}
+	running bool
+}
+
+// runPollers holds the poller of every client, so that all the waits of a
+// provider share one.
+var runPollers = struct {
+	sync.Mutex
+	byClient map[*internal.Client]*runPoller
+}{byClient: map[*internal.Client]*runPoller{}}
+
+func runPollerFor(client *internal.Client) *runPoller {
+	runPollers.Lock()
+	defer runPollers.Unlock()
+
+	poller, ok := runPollers.byClient[client]
+	if !ok {
+		poller = newRunPoller(client)
+		runPollers.byClient[client] = poller
+	}
+
+	return poller
+}
+
+func newRunPoller(client *internal.Client) *runPoller {
+	return &runPoller{
+		client:       client,
+		minInterval:  runPollerMinInterval,
+		maxInterval:  runPollerMaxInterval,
+		tick:         runPollerTick,
+		batchSize:    runPollerBatchSize,
+		queryTimeout: runPollerQueryTimeout,
+		watches:      map[*runWatch]struct{}{},
+	}
+}
+
+// watch starts polling for the state of the run until unwatch is called.
+func (p *runPoller) watch(stackID, runID string) *runWatch {
+	w := &runWatch{
+		key:      runKey{stackID: stackID, runID: runID},
+		updates:  make(chan runStatus, 1),
+		interval: p.minInterval,
+	}
+	w.nextPoll = time.Now().Add(p.jitter(w.interval))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.watches[w] = struct{}{}
+
+	if !p.running {
+		p.running = true
+		go p.loop()
+	}
+
+	return w
+}
+
+func (p *runPoller) unwatch(w *runWatch) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.watches, w)
+}
+
+// loop polls for due runs until there are no runs left to watch.
+func (p *runPoller) loop() {
+	ticker := time.NewTicker(p.tick)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		due, ok := p.due(time.Now())
+		if !ok {
+			return
+		}
+
+		for batch := range slices.Chunk(due, p.batchSize) {
+			p.deliver(batch, p.poll(batch))
+		}
+	}
+}
+
+// poll looks up the states of a batch of runs. If the batch can't be looked
+// up, every run is looked up on its own, so that only the waits for the runs
+// which can't be looked up fail.
+func (p *runPoller) poll(keys []runKey) map[runKey]runStatus {
+	statuses, err := p.getRunStates(keys)
+	if err == nil {
+		return statuses
+	}
+
+	if len(keys) == 1 {
+		return map[runKey]runStatus{keys[0]: {err: err}}
+	}
+
+	statuses = make(map[runKey]runStatus, len(keys))
+	for _, key := range keys {
+		status, keyErr := p.getRunStates([]runKey{key})
+		if keyErr != nil {
+			statuses[key] = runStatus{err: keyErr, batchErr: err}
+			continue
+		}
+
+		result := status[key]
+		result.batchErr = err
+		statuses[key] = result
+	}
+
+	return statuses
+}
+
+func (p *runPoller) getRunStates(keys []runKey) (map[runKey]runStatus, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.queryTimeout)
+	defer cancel()
+
+	return getRunStates(ctx, p.client, keys)
+}
+
+// due returns the runs which need to be looked up now. It returns false and
+// marks the poller as stopped if nothing is being watched anymore.
+func (p *runPoller) due(now time.Time) ([]runKey, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.watches) == 0 {
+		p.running = false
+		return nil, false
+	}
+
+	var keys []runKey
+	seen := map[runKey]bool{}
+
+	for w := range p.watches {
+		if now.Before(w.nextPoll) || seen[w.key] {
+			continue
+		}
+		seen[w.key] = true
+		keys = append(keys, w.key)
+	}
+
+	return keys, true
+}
+
+// deliver hands the results of a lookup to the watches of the runs, and
+// schedules their next lookup.
+func (p *runPoller) deliver(keys []runKey, statuses map[runKey]runStatus) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+
+	for w := range p.watches {
+		if !slices.Contains(keys, w.key) {
+			continue
+		}
+
+		status := statuses[w.key]
+
+		if status.state != w.state {
+			w.interval = p.minInterval
+		} else {
+			w.interval = min(w.interval*3/2, p.maxInterval)
+		}
+		w.state = status.state
+		w.nextPoll = now.Add(p.jitter(w.interval))
+
+		// Only the latest status matters, so replace one the reader hasn't
+		// picked up yet.
+		select {
+		case <-w.updates:
+		default:
+		}
+		w.updates <- status
+	}
+}
+
+// jitter spreads lookups scheduled at the same time by up to a fifth of the
+// interval either way.
+func (p *runPoller) jitter(interval time.Duration) time.Duration {
+	spread := int64(interval) / 5
+	if spread <= 0 {
+		return interval
+	}
+	return interval - time.Duration(spread) + time.Duration(rand.Int64N(2*spread))
+}
+
+// wait blocks until the run has finished or is waiting for confirmation, and
+// returns its final state. Every state the run goes through is logged.
+func (p *runPoller) wait(ctx context.Context, stackID, runID string, timeout time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	w := p.watch(stackID, runID)
+	defer p.unwatch(w)
+
+	var state string
+	for {
+		select {
+		case <-ctx.Done():
+			return state, ctx.Err()
+		case status := <-w.updates:
+			if status.batchErr != nil {
+				tflog.Debug(ctx, "could not look up the run together with others, looked it up on its own", map[string]any{
+					"stackID": stackID,
+					"runID":   runID,
+					"error":   status.batchErr.Error(),
+				})
+			}
+
+			if status.err != nil {
+				return state, status.err
+			}
+
+			if status.state != state {
+				tflog.Info(ctx, "run changed state", map[string]any{
+					"stackID":       stackID,
+					"runID":         runID,
+					"previousState": state,
+					"currentState":  status.state,
+				})
+				state = status.state
+			}
+
+			// Unconfirmed runs are not finished, but they need someone
+			// to confirm them, so there's no point in waiting for them.
+			if status.finished || status.state == "unconfirmed" {
+				return state, nil
+			}
+		}
+	}
+}
+
+type runResourceState struct {
+	ID       graphql.String
+	State    graphql.String
+	Finished graphql.Boolean
+}
+
+// getRunStates looks up the state of all the runs with a single query, giving
+// each run an alias of its own. Runs which can't be found are reported with
+// an empty state.
+func getRunStates(ctx context.Context, client *internal.Client, keys []runKey) (map[runKey]runStatus, error) {
+	fields := make([]reflect.StructField, 0, len(keys))
+	variables := make(map[string]any, 2*len(keys))
+
+	for i, key := range keys {
+		stack := reflect.StructOf([]reflect.StructField{{
+			Name: "RunResourceState",
+			Type: reflect.TypeFor[*runResourceState](),
+			Tag:  reflect.StructTag(fmt.Sprintf(`graphql:"runResourceState(id: $run%d)"`, i)),
+		}})
+
+		fields = append(fields, reflect.StructField{
+			Name: fmt.Sprintf("Run%d", i),
+			Type: reflect.PointerTo(stack),
+			Tag:  reflect.StructTag(fmt.Sprintf(`graphql:"run%d: stack(id: $stack%d)"`, i, i)),
+		})
+
+		variables[fmt.Sprintf("stack%d", i)] = graphql.ID(key.stackID)
+		variables[fmt.Sprintf("run%d", i)] = graphql.ID(key.runID)
+	}
+
+	query := reflect.New(reflect.StructOf(fields))
+
+	if err := client.Query(ctx, "StackRunsStateRead", query.Interface(), variables); err != nil {
+		return nil, errors.Wrapf(err, "could not query for the state of %d runs", len(keys))
+	}
+
+	statuses := make(map[runKey]runStatus, len(keys))
+	for i, key := range keys {
+		stack := query.Elem().Field(i)
+		if stack.IsNil() {
+			statuses[key] = runStatus{}
+			continue
+		}
+
+		rrs, _ := stack.Elem().Field(0).Interface().(*runResourceState)
+		if rrs == nil {
+			statuses[key] = runStatus{}
+			continue
+		}
+
+		statuses[key] = runStatus{
+			state:    strings.ToLower(string(rrs.State)),
+			finished: bool(rrs.Finished),
+		}
+	}
+
+	return statuses, nil
+}
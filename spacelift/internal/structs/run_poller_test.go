@@ -0,0 +1,192 @@
+package structs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+	. "github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/testhelpers"
+)
+
+// fakeRunStatesServer answers aliased run state queries. Every run finishes
+// after it has been looked up finishAfter times. Queries for run "broken"
+// fail, and queries for run "hung" never return.
+func fakeRunStatesServer(t *testing.T, finishAfter int) *GraphQLServer {
+	var mu sync.Mutex
+	lookups := map[string]int{}
+
+	return NewGraphQLServer(t, map[string]GraphQLHandler{
+		"StackRunsStateRead": func(r GraphQLRequest) any {
+			mu.Lock()
+			defer mu.Unlock()
+
+			data := map[string]any{}
+			for i := 0; ; i++ {
+				alias := fmt.Sprintf("run%d", i)
+
+				runID := r.Variable(alias)
+				if runID == "" {
+					break
+				}
+
+				if !strings.Contains(r.Query, fmt.Sprintf("run%d: stack(id: $stack%d)", i, i)) {
+					t.Errorf("query does not alias run %d: %s", i, r.Query)
+				}
+
+				switch runID {
+				case "missing":
+					data[alias] = nil
+					continue
+				case "broken":
+					return errors.New("internal server error")
+				case "hung":
+					mu.Unlock()
+					<-r.Context.Done()
+					mu.Lock()
+					return r.Context.Err()
+				}
+
+				lookups[runID]++
+				state, finished := "PLANNING", false
+				if lookups[runID] >= finishAfter {
+					state, finished = "FINISHED", true
+				}
+
+				data[alias] = map[string]any{"runResourceState": map[string]any{"id": runID, "state": state, "finished": finished}}
+			}
+
+			return data
+		},
+	})
+}
+
+func newTestRunPoller(client *internal.Client) *runPoller {
+	poller := newRunPoller(client)
+	poller.minInterval = 20 * time.Millisecond
+	poller.maxInterval = 50 * time.Millisecond
+	poller.tick = 10 * time.Millisecond
+	poller.batchSize = 10
+	poller.queryTimeout = time.Second
+	return poller
+}
+
+func TestRunPollerBatchesConcurrentWaits(t *testing.T) {
+	server := fakeRunStatesServer(t, 3)
+	poller := newTestRunPoller(server.Client())
+
+	const runs = 30
+
+	var wg sync.WaitGroup
+	states := make([]string, runs)
+	errs := make([]error, runs)
+
+	for i := range runs {
+		wg.Go(func() {
+			states[i], errs[i] = poller.wait(context.Background(), "stack", fmt.Sprintf("run-%d", i), 5*time.Second)
+		})
+	}
+	wg.Wait()
+
+	for i := range runs {
+		if errs[i] != nil || states[i] != "finished" {
+			t.Errorf("run %d: got state %q and error %v", i, states[i], errs[i])
+		}
+	}
+
+	// Each run is looked up three times, so polling them one by one would
+	// take 90 queries.
+	if got := len(server.Requests("")); got >= runs*3/2 {
+		t.Errorf("made %d queries for %d runs", got, runs)
+	}
+}
+
+func TestRunPollerTimesOut(t *testing.T) {
+	server := fakeRunStatesServer(t, 1000)
+	poller := newTestRunPoller(server.Client())
+
+	state, err := poller.wait(context.Background(), "stack", "run", 200*time.Millisecond)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("got error %v, want a timeout", err)
+	}
+
+	if state != "planning" {
+		t.Errorf("got last state %q, want planning", state)
+	}
+
+	if got := len(server.Requests("")); got < 2 || got > 10 {
+		t.Errorf("made %d queries, want the interval to back off", got)
+	}
+
+	// The poller stops once nothing is being watched.
+	time.Sleep(50 * time.Millisecond)
+	poller.mu.Lock()
+	defer poller.mu.Unlock()
+	if poller.running {
+		t.Error("poller is still running with nothing to watch")
+	}
+}
+
+func TestRunPollerIsolatesFailingRuns(t *testing.T) {
+	server := fakeRunStatesServer(t, 2)
+
+	poller := newTestRunPoller(server.Client())
+	poller.queryTimeout = 100 * time.Millisecond
+
+	runIDs := []string{"run-0", "broken", "run-1", "hung", "run-2"}
+
+	var wg sync.WaitGroup
+	states := make([]string, len(runIDs))
+	errs := make([]error, len(runIDs))
+
+	for i, runID := range runIDs {
+		wg.Go(func() {
+			states[i], errs[i] = poller.wait(context.Background(), "stack", runID, 5*time.Second)
+		})
+	}
+	wg.Wait()
+
+	for i, runID := range runIDs {
+		switch runID {
+		case "broken", "hung":
+			if errs[i] == nil {
+				t.Errorf("run %s: expected an error, got state %q", runID, states[i])
+			}
+		default:
+			if errs[i] != nil || states[i] != "finished" {
+				t.Errorf("run %s: got state %q and error %v", runID, states[i], errs[i])
+			}
+		}
+	}
+
+	// Runs looked up on their own carry the error of the batch, for their
+	// waits to log.
+	key := runKey{stackID: "stack", runID: "run-0"}
+	statuses := poller.poll([]runKey{key, {stackID: "stack", runID: "broken"}})
+	if got := statuses[key]; got.err != nil || got.batchErr == nil || got.state != "finished" {
+		t.Errorf("unexpected status of a run looked up on its own: %+v", got)
+	}
+}
+
+func TestGetRunStatesMissingRun(t *testing.T) {
+	server := fakeRunStatesServer(t, 1)
+
+	keys := []runKey{{stackID: "stack", runID: "missing"}, {stackID: "stack", runID: "run"}}
+
+	statuses, err := getRunStates(context.Background(), server.Client(), keys)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := statuses[keys[0]]; got.state != "" || got.finished {
+		t.Errorf("unexpected status of missing run: %+v", got)
+	}
+
+	if got := statuses[keys[1]]; got.state != "finished" || !got.finished {
+		t.Errorf("unexpected status of run: %+v", got)
+	}
+}
@@ -6,6 +6,12 @@ type SearchInput struct {
 	First      *graphql.Int            `json:"first"`
 	After      *graphql.String         `json:"after"`
 	Predicates *[]SearchQueryPredicate `json:"predicates"`
+	OrderBy    *QueryOrder             `json:"orderBy,omitempty"`
+}
+
+type QueryOrder struct {
+	Field     graphql.String `json:"field"`
+	Direction graphql.String `json:"direction"`
 }
 
 type SearchQueryPredicate struct {
@@ -17,4 +23,12 @@ type SearchQueryFieldConstraint struct {
 	BooleanEquals *[]graphql.Boolean `json:"booleanEquals"`
 	EnumEquals    *[]graphql.String  `json:"enumEquals"`
 	StringMatches *[]graphql.String  `json:"stringMatches"`
+	TimeInRange   *SearchTimeRange   `json:"timeInRange,omitempty"`
+}
+
+// SearchTimeRange bounds a timestamp field. Bounds are Unix timestamps, and
+// either can be left open.
+type SearchTimeRange struct {
+	Start *graphql.Int `json:"start"`
+	End   *graphql.Int `json:"end"`
 }
@@ -19,12 +19,7 @@ func BuildBoolean(d *schema.ResourceData, schemaName string, optionalPredicateNa
 			continue
 		}
 
-		out = append(out, search.SearchQueryPredicate{
-			Field: getPredicateName(schemaName, optionalPredicateName),
-			Constraint: search.SearchQueryFieldConstraint{
-				BooleanEquals: &[]graphql.Boolean{graphql.Boolean(predicate["equals"].(bool))},
-			},
-		})
+		out = append(out, Boolean(getPredicateName(schemaName, optionalPredicateName), predicate["equals"].(bool)))
 	}
 
 	return
@@ -42,27 +37,64 @@ func BuildStringOrEnum(d *schema.ResourceData, isEnum bool, schemaName string, o
 			continue
 		}
 
-		var matches []graphql.String
+		var matches []string
 		for _, element := range predicate["any_of"].([]any) {
-			matches = append(matches, graphql.String(element.(string)))
+			matches = append(matches, element.(string))
 		}
 
-		var constraint search.SearchQueryFieldConstraint
-		if isEnum {
-			constraint.EnumEquals = &matches
-		} else {
-			constraint.StringMatches = &matches
-		}
-
-		out = append(out, search.SearchQueryPredicate{
-			Field:      getPredicateName(schemaName, optionalPredicateName),
-			Constraint: constraint,
-		})
+		out = append(out, StringOrEnum(getPredicateName(schemaName, optionalPredicateName), isEnum, matches...))
 	}
 
 	return
 }
 
+// Boolean requires the field to be equal to the value.
+func Boolean(field graphql.String, value bool) search.SearchQueryPredicate {
+	return search.SearchQueryPredicate{
+		Field: field,
+		Constraint: search.SearchQueryFieldConstraint{
+			BooleanEquals: &[]graphql.Boolean{graphql.Boolean(value)},
+		},
+	}
+}
+
+// StringOrEnum requires the field to match any of the values.
+func StringOrEnum(field graphql.String, isEnum bool, values ...string) search.SearchQueryPredicate {
+	matches := make([]graphql.String, 0, len(values))
+	for _, value := range values {
+		matches = append(matches, graphql.String(value))
+	}
+
+	var constraint search.SearchQueryFieldConstraint
+	if isEnum {
+		constraint.EnumEquals = &matches
+	} else {
+		constraint.StringMatches = &matches
+	}
+
+	return search.SearchQueryPredicate{
+		Field:      field,
+		Constraint: constraint,
+	}
+}
+
+// TimeInRange requires the timestamp field to be within the range. Either
+// bound may be nil to leave that end of the range open.
+func TimeInRange(field graphql.String, start, end *int64) search.SearchQueryPredicate {
+	timeRange := &search.SearchTimeRange{}
+	if start != nil {
+		timeRange.Start = graphql.NewInt(graphql.Int(*start))
+	}
+	if end != nil {
+		timeRange.End = graphql.NewInt(graphql.Int(*end))
+	}
+
+	return search.SearchQueryPredicate{
+		Field:      field,
+		Constraint: search.SearchQueryFieldConstraint{TimeInRange: timeRange},
+	}
+}
+
 func getPredicateName(schemaName string, optionalPredicateName []string) graphql.String {
 	if len(optionalPredicateName) == 0 {
 		return graphql.String(schemaName)
@@ -0,0 +1,28 @@
+package structs
+
+// Hooks represents the commands to run around each phase of a run.
+type Hooks struct {
+	AfterApply    []string `graphql:"afterApply"`
+	AfterDestroy  []string `graphql:"afterDestroy"`
+	AfterInit     []string `graphql:"afterInit"`
+	AfterPerform  []string `graphql:"afterPerform"`
+	AfterPlan     []string `graphql:"afterPlan"`
+	AfterRun      []string `graphql:"afterRun"`
+	BeforeApply   []string `graphql:"beforeApply"`
+	BeforeDestroy []string `graphql:"beforeDestroy"`
+	BeforeInit    []string `graphql:"beforeInit"`
+	BeforePerform []string `graphql:"beforePerform"`
+	BeforePlan    []string `graphql:"beforePlan"`
+}
+
+// StackContextAttachment is a context attached to a stack, either explicitly
+// or through an autoattach label, along with the configuration it brings.
+type StackContextAttachment struct {
+	ID             string          `graphql:"id"`
+	ContextID      string          `graphql:"contextId"`
+	ContextName    string          `graphql:"contextName"`
+	IsAutoattached bool            `graphql:"isAutoattached"`
+	Priority       int             `graphql:"priority"`
+	Config         []ConfigElement `graphql:"config"`
+	ContextHooks   Hooks           `graphql:"contextHooks"`
+}
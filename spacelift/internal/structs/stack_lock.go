@@ -0,0 +1,9 @@
+package structs
+
+// StackLock is the lock held on a stack, if any.
+type StackLock struct {
+	ID       string  `graphql:"id"`
+	LockedBy *string `graphql:"lockedBy"`
+	LockedAt *int    `graphql:"lockedAt"`
+	LockNote *string `graphql:"lockNote"`
+}
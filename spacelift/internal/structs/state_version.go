@@ -0,0 +1,11 @@
+package structs
+
+// StateVersion is a single version of the Terraform state managed by
+// Spacelift for a stack.
+type StateVersion struct {
+	ID        string  `graphql:"id"`
+	Serial    int     `graphql:"serial"`
+	Lineage   string  `graphql:"lineage"`
+	RunID     *string `graphql:"runId"`
+	CreatedAt int     `graphql:"createdAt"`
+}
@@ -9,10 +9,8 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/pkg/errors"
-	"github.com/shurcooL/graphql"
 
 	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
 )
@@ -28,70 +26,55 @@ func NewWaitConfiguration(input []any) *WaitConfiguration {
 		return nil
 	}
 	v := input[0].(map[string]any)
-	cfg := &WaitConfiguration{
-		disabled:          v["disabled"].(bool),
-		continueOnState:   []string{},
-		continueOnTimeout: v["continue_on_timeout"].(bool),
-	}
 
+	var continueOnState []string
 	if v, ok := v["continue_on_state"]; ok {
 		for _, item := range v.(*schema.Set).List() {
 			str, ok := item.(string)
 			if !ok {
 				panic(fmt.Sprintf("continue_on_state contains a non-string element %+v", str))
 			}
-			cfg.continueOnState = append(cfg.continueOnState, str)
+			continueOnState = append(continueOnState, str)
 		}
 	}
+
+	return NewWaitConfigurationFromValues(v["disabled"].(bool), continueOnState, v["continue_on_timeout"].(bool))
+}
+
+// NewWaitConfigurationFromValues builds the wait configuration from values that
+// have already been read from the config, e.g. by the Plugin Framework.
+func NewWaitConfigurationFromValues(disabled bool, continueOnState []string, continueOnTimeout bool) *WaitConfiguration {
+	cfg := &WaitConfiguration{
+		disabled:          disabled,
+		continueOnState:   append([]string{}, continueOnState...),
+		continueOnTimeout: continueOnTimeout,
+	}
+
 	if len(cfg.continueOnState) == 0 {
 		cfg.continueOnState = append(cfg.continueOnState, "finished")
 	}
 	return cfg
 }
 
-func (wait *WaitConfiguration) Wait(ctx context.Context, d *schema.ResourceData, client *internal.Client, stackID, mutationID string) diag.Diagnostics {
+func (wait *WaitConfiguration) Wait(ctx context.Context, client *internal.Client, stackID, mutationID string, timeout time.Duration) diag.Diagnostics {
 	if wait.disabled {
 		return nil
 	}
 
-	stateConf := &retry.StateChangeConf{
-		ContinuousTargetOccurence: 1,
-		Delay:                     10 * time.Second,
-		MinTimeout:                10 * time.Second,
-		Pending: []string{
-			"running",
-		},
-		Target: []string{
-			"finished",
-			"unconfirmed", // Let's treat unconfirmed as the target state.
-			// It's not finished, but we don't want to wait for it because it requires confirmation from someone.
-		},
-		Refresh: checkStackStatusFunc(ctx, client, stackID, mutationID),
-		Timeout: d.Timeout(schema.TimeoutCreate),
-	}
-
-	finalState, err := stateConf.WaitForStateContext(ctx)
+	finalState, err := runPollerFor(client).wait(ctx, stackID, mutationID, timeout)
 	if err != nil {
-		if timeoutErr, ok := internal.AsError[*retry.TimeoutError](err); ok {
-			tflog.Debug(ctx, "received retry.TimeoutError from WaitForStateContext", map[string]any{
-				"stackID":       stackID,
-				"runID":         mutationID,
-				"lastState":     timeoutErr.LastState,
-				"expectedState": timeoutErr.ExpectedState,
-			})
-			finalState = "__timeout__"
-		} else if err == context.DeadlineExceeded {
-			tflog.Debug(ctx, "received context.DeadlineExceeded from WaitForStateContext", map[string]any{
-				"stackID": stackID,
-				"runID":   mutationID,
-			})
-			finalState = "__timeout__"
-		} else {
+		if !errors.Is(err, context.DeadlineExceeded) {
 			return diag.Errorf("failed waiting for run %s on stack %s to finish. error(%T): %+v ", mutationID, stackID, err, err)
 		}
+		tflog.Debug(ctx, "timed out waiting for run", map[string]any{
+			"stackID":   stackID,
+			"runID":     mutationID,
+			"lastState": finalState,
+		})
+		finalState = "__timeout__"
 	}
 
-	switch finalState.(string) {
+	switch finalState {
 	case "__timeout__":
 		if !wait.continueOnTimeout {
 			return diag.Errorf("run %s on stack %s has timed out", mutationID, stackID)
@@ -102,8 +85,12 @@ func (wait *WaitConfiguration) Wait(ctx context.Context, d *schema.ResourceData,
 				"runID":   mutationID,
 			})
 	default:
-		if !slices.Contains[[]string](wait.continueOnState, finalState.(string)) {
-			return diag.Errorf("run %s on stack %s has ended with status %s. expected %v", mutationID, stackID, finalState, wait.continueOnState)
+		if !slices.Contains(wait.continueOnState, finalState) {
+			return diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("run %s on stack %s has ended with status %s. expected %v", mutationID, stackID, finalState, wait.continueOnState),
+				Detail:   runLogsTailDetail(ctx, client, stackID, mutationID),
+			}}
 		}
 		tflog.Debug(ctx, "run finished", map[string]any{
 			"stackID":    stackID,
@@ -115,56 +102,40 @@ func (wait *WaitConfiguration) Wait(ctx context.Context, d *schema.ResourceData,
 	return nil
 }
 
-func checkStackStatusFunc(ctx context.Context, client *internal.Client, stackID string, runID string) retry.StateRefreshFunc {
-	return func() (result any, state string, err error) {
-		// instead of a resource handle we return the current state as result
-		// Makes it easier to detect which end state has been reached.
-		// Otherwise we would need another GraphQL query
-		result, finished, err := getStackRunStateByID(ctx, client, stackID, runID)
-		if err != nil {
-			return
-		}
-		state = "running"
-		if finished {
-			state = "finished"
-		}
-		// Let's treat unconfirmed as the target state.
-		// It's not finished, but we don't want to wait for it because it requires confirmation from someone.
-		if result == "unconfirmed" {
-			state = "unconfirmed"
-		}
-		return
-	}
-}
+// waitFailureLogLines is the number of log lines included in the diagnostic
+// of a run which did not end in an expected state.
+const waitFailureLogLines = 30
 
-func getStackRunStateByID(ctx context.Context, client *internal.Client, stackID string, runID string) (string, bool, error) {
-	var query struct {
-		Stack struct {
-			RunResourceState struct {
-				ID       graphql.String
-				State    graphql.String
-				Finished graphql.Boolean
-			} `graphql:"runResourceState(id: $runId)"`
-		} `graphql:"stack(id: $stackId)"`
+// runLogsTailDetail describes the end of the logs of the phase the run ended
+// in, so that failures can be diagnosed without opening Spacelift. Logs are
+// a nice to have, so failing to fetch them is only logged.
+func runLogsTailDetail(ctx context.Context, client *internal.Client, stackID, runID string) string {
+	logs, err := FailedPhaseLogsTail(ctx, client, stackID, runID, waitFailureLogLines)
+	if err != nil {
+		tflog.Warn(ctx, "could not fetch logs of run", map[string]any{
+			"stackID": stackID,
+			"runID":   runID,
+			"error":   err.Error(),
+		})
+		return ""
 	}
 
-	variables := map[string]any{
-		"stackId": graphql.ID(stackID),
-		"runId":   graphql.ID(runID),
+	if logs == nil || len(logs.Lines) == 0 {
+		return ""
 	}
 
-	if err := client.Query(ctx, "StackRunRead", &query, variables); err != nil {
-		return "", false, errors.Wrap(err, fmt.Sprintf("could not query for run %s of stack %s", runID, stackID))
+	header := fmt.Sprintf("Logs of the %s phase:", strings.ToLower(logs.Phase))
+	switch {
+	case logs.PageLimitReached:
+		tflog.Warn(ctx, "logs of run are too long to fetch their end", map[string]any{
+			"stackID":  stackID,
+			"runID":    runID,
+			"maxPages": RunLogsMaxPages,
+		})
+		header = fmt.Sprintf("Last %d lines of the first %d pages of the logs of the %s phase, which go on beyond them:", len(logs.Lines), RunLogsMaxPages, strings.ToLower(logs.Phase))
+	case logs.Truncated:
+		header = fmt.Sprintf("Last %d lines of the logs of the %s phase:", len(logs.Lines), strings.ToLower(logs.Phase))
 	}
 
-	rrs := query.Stack.RunResourceState
-
-	currentState := strings.ToLower(string(rrs.State))
-	tflog.Debug(ctx, "current state of run", map[string]any{
-		"stackID":      stackID,
-		"runID":        runID,
-		"currentState": currentState,
-		"finished":     rrs.Finished,
-	})
-	return currentState, bool(rrs.Finished), nil
+	return header + "\n\n" + strings.Join(logs.Lines, "\n")
 }
@@ -0,0 +1,145 @@
+package testhelpers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+)
+
+// GraphQLRequest is a request made to a fake GraphQL API.
+type GraphQLRequest struct {
+	// Context is done once the client gives up on the request.
+	Context context.Context
+
+	// Operation is the name the client gave the query or mutation.
+	Operation string
+
+	Query     string
+	Variables json.RawMessage
+}
+
+// Variable returns the value of a variable formatted as a string, or an empty
+// string if it is not set.
+func (r GraphQLRequest) Variable(name string) string {
+	var variables map[string]any
+	if err := json.Unmarshal(r.Variables, &variables); err != nil {
+		return ""
+	}
+
+	value, ok := variables[name]
+	if !ok || value == nil {
+		return ""
+	}
+
+	return fmt.Sprint(value)
+}
+
+// DecodeVariables decodes the variables of the request into v.
+func (r GraphQLRequest) DecodeVariables(v any) error {
+	return json.Unmarshal(r.Variables, v)
+}
+
+// GraphQLHandler answers a request to a fake GraphQL API. An error is sent
+// as a GraphQL error, a string or json.RawMessage is sent as the JSON data
+// as is, and anything else is marshalled into the data.
+type GraphQLHandler func(GraphQLRequest) any
+
+// GraphQLServer is a fake GraphQL API, answering each query and mutation
+// with the handler registered under its name.
+type GraphQLServer struct {
+	t        testing.TB
+	server   *httptest.Server
+	handlers map[string]GraphQLHandler
+
+	mu       sync.Mutex
+	requests []GraphQLRequest
+}
+
+// NewGraphQLServer starts a fake GraphQL API, which is closed when the test
+// ends. Requests for operations without a handler fail the test.
+func NewGraphQLServer(t testing.TB, handlers map[string]GraphQLHandler) *GraphQLServer {
+	t.Helper()
+
+	s := &GraphQLServer{t: t, handlers: handlers}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.server.Close)
+
+	return s
+}
+
+// URL returns the URL of the fake API.
+func (s *GraphQLServer) URL() string {
+	return s.server.URL
+}
+
+// Client returns a client talking to the fake API.
+func (s *GraphQLServer) Client() *internal.Client {
+	return internal.NewClient(s.server.URL, "token", nil, nil)
+}
+
+// Requests returns the requests made for the operation so far, or all of
+// them if the operation is empty.
+func (s *GraphQLServer) Requests(operation string) []GraphQLRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var requests []GraphQLRequest
+	for _, request := range s.requests {
+		if operation == "" || request.Operation == operation {
+			requests = append(requests, request)
+		}
+	}
+
+	return requests
+}
+
+func (s *GraphQLServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Query     string          `json:"query"`
+		Variables json.RawMessage `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		s.t.Errorf("could not decode GraphQL request: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	request := GraphQLRequest{
+		Context:   r.Context(),
+		Operation: r.Header.Get("Spacelift-GraphQL-Query") + r.Header.Get("Spacelift-GraphQL-Mutation"),
+		Query:     body.Query,
+		Variables: body.Variables,
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, request)
+	s.mu.Unlock()
+
+	handler, ok := s.handlers[request.Operation]
+	if !ok {
+		s.t.Errorf("unexpected GraphQL operation %q", request.Operation)
+		http.Error(w, "unexpected operation", http.StatusBadRequest)
+		return
+	}
+
+	var response any
+	switch data := handler(request).(type) {
+	case error:
+		response = map[string]any{"errors": []map[string]string{{"message": data.Error()}}}
+	case string:
+		response = map[string]any{"data": json.RawMessage(data)}
+	default:
+		response = map[string]any{"data": data}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		s.t.Errorf("could not encode GraphQL response: %v", err)
+	}
+}
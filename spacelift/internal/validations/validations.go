@@ -1,6 +1,9 @@
 package validations
 
 import (
+	"regexp"
+	"strings"
+
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 )
@@ -13,3 +16,79 @@ func DisallowEmptyString(in any, path cty.Path) diag.Diagnostics {
 
 	return nil
 }
+
+const (
+	addressName   = `[A-Za-z_][A-Za-z0-9_-]*`
+	addressIndex  = `(?:\[(?:[0-9]+|"(?:[^"\\]|\\.)*")\])?`
+	addressModule = `module\.` + addressName + addressIndex
+)
+
+var (
+	addressModulePath = regexp.MustCompile(`^(?:` + addressModule + `\.)*`)
+	addressResource   = regexp.MustCompile(`^(?:data\.)?(` + addressName + `)\.` + addressName + addressIndex + `$`)
+	addressModuleOnly = regexp.MustCompile(`^` + addressModule + `$`)
+)
+
+// TerraformTargetAddress ensures that the given value is an address Terraform
+// accepts as a target, i.e. that of a module, a resource or a resource
+// instance, such as module.network["eu"].aws_subnet.private[0].
+func TerraformTargetAddress(in any, path cty.Path) diag.Diagnostics {
+	address, _ := in.(string)
+
+	if _, resource := splitAddress(address); addressModuleOnly.MatchString(resource) || isResourceAddress(resource) {
+		return nil
+	}
+
+	return diag.Errorf("%q is not a valid module or resource address, e.g. module.network or aws_subnet.private[0]", address)
+}
+
+// TerraformResourceAddress ensures that the given value is the address of a
+// managed resource or resource instance, as Terraform accepts for replacement.
+func TerraformResourceAddress(in any, path cty.Path) diag.Diagnostics {
+	address, _ := in.(string)
+
+	if _, resource := splitAddress(address); isResourceAddress(resource) && !strings.HasPrefix(resource, "data.") {
+		return nil
+	}
+
+	return diag.Errorf("%q is not a valid managed resource address, e.g. aws_subnet.private[0]", address)
+}
+
+// splitAddress splits an address into the module path and what is left of it.
+func splitAddress(address string) (string, string) {
+	modulePath := addressModulePath.FindString(address)
+	return modulePath, address[len(modulePath):]
+}
+
+func isResourceAddress(address string) bool {
+	match := addressResource.FindStringSubmatch(address)
+
+	// Without a resource type, module.name and data.name are not resources.
+	return match != nil && match[1] != "module" && match[1] != "data"
+}
+
+var (
+	environmentVariableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	terraformVariableName   = regexp.MustCompile(`^` + addressName + `$`)
+)
+
+// EnvironmentVariableName ensures that the given value is a valid environment
+// variable name. The name of a TF_VAR_ variable has to be followed by a valid
+// Terraform variable name, which may contain dashes too.
+func EnvironmentVariableName(in any, path cty.Path) diag.Diagnostics {
+	name, _ := in.(string)
+
+	if variable, ok := strings.CutPrefix(name, "TF_VAR_"); ok {
+		if terraformVariableName.MatchString(variable) {
+			return nil
+		}
+
+		return diag.Errorf("%q does not name a valid Terraform variable after TF_VAR_, it has to start with a letter or an underscore and contain only letters, digits, underscores and dashes", name)
+	}
+
+	if environmentVariableName.MatchString(name) {
+		return nil
+	}
+
+	return diag.Errorf("%q is not a valid environment variable name, it has to start with a letter or an underscore and contain only letters, digits and underscores", name)
+}
@@ -0,0 +1,121 @@
+package spacelift
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	listschema "github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/shurcooL/graphql"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs/search"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs/search/predicates"
+)
+
+var (
+	_ list.ListResourceWithConfigure    = (*contextListResource)(nil)
+	_ list.ListResourceWithRawV6Schemas = (*contextListResource)(nil)
+)
+
+// NewContextListResource returns the list resource for spacelift_context, used
+// by `list` blocks and `terraform query`.
+func NewContextListResource() list.ListResource {
+	return &contextListResource{sdkListResource{
+		typeName:          "spacelift_context",
+		resource:          resourceContext,
+		identityAttribute: "context_id",
+	}}
+}
+
+type contextListResource struct {
+	sdkListResource
+}
+
+type contextListModel struct {
+	Labels  []string `tfsdk:"labels"`
+	Name    []string `tfsdk:"name"`
+	SpaceID []string `tfsdk:"space_id"`
+}
+
+func (r *contextListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Diagnostics.Append(r.schemaDiagnostics()...)
+
+	resp.Schema = listschema.Schema{
+		Description: "Lists contexts in the Spacelift account visible to the API user, matching predicates.",
+		Attributes: map[string]listschema.Attribute{
+			"labels": listschema.ListAttribute{
+				Description: "Require contexts to have all of the labels",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"name": listschema.ListAttribute{
+				Description: "Require contexts to have one of the names",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"space_id": listschema.ListAttribute{
+				Description: "Require contexts to be in one of the spaces",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func (r *contextListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config contextListModel
+	if diags := req.Config.Get(ctx, &config); diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	var conditions []search.SearchQueryPredicate
+
+	for _, label := range config.Labels {
+		conditions = append(conditions, predicates.StringOrEnum("label", false, label))
+	}
+	if len(config.Name) > 0 {
+		conditions = append(conditions, predicates.StringOrEnum("name", false, config.Name...))
+	}
+	if len(config.SpaceID) > 0 {
+		conditions = append(conditions, predicates.StringOrEnum("space", false, config.SpaceID...))
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		var query struct {
+			SearchContextsOutput struct {
+				Edges []struct {
+					Node listedEntity `graphql:"node"`
+				} `graphql:"edges"`
+				PageInfo search.PageInfo `graphql:"pageInfo"`
+			} `graphql:"searchContexts(input: $input)"`
+		}
+
+		input := search.SearchInput{
+			First:      graphql.NewInt(listPageSize),
+			Predicates: &conditions,
+		}
+
+		for {
+			variables := map[string]any{"input": input}
+
+			if err := r.client.Query(ctx, "ContextsPage", &query, variables); err != nil {
+				push(listResultError("could not query for contexts", err))
+				return
+			}
+
+			for _, edge := range query.SearchContextsOutput.Edges {
+				if !push(r.result(ctx, req, edge.Node.ID, edge.Node.Name)) {
+					return
+				}
+			}
+
+			if !query.SearchContextsOutput.PageInfo.HasNextPage {
+				return
+			}
+
+			after := graphql.String(query.SearchContextsOutput.PageInfo.EndCursor)
+			input.After = &after
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package spacelift
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	listschema "github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/shurcooL/graphql"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs/search"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs/search/predicates"
+)
+
+var (
+	_ list.ListResourceWithConfigure    = (*moduleListResource)(nil)
+	_ list.ListResourceWithRawV6Schemas = (*moduleListResource)(nil)
+)
+
+// NewModuleListResource returns the list resource for spacelift_module, used by
+// `list` blocks and `terraform query`.
+func NewModuleListResource() list.ListResource {
+	return &moduleListResource{sdkListResource{
+		typeName:          "spacelift_module",
+		resource:          resourceModule,
+		identityAttribute: "module_id",
+	}}
+}
+
+type moduleListResource struct {
+	sdkListResource
+}
+
+type moduleListModel struct {
+	Administrative types.Bool `tfsdk:"administrative"`
+	Branch         []string   `tfsdk:"branch"`
+	Labels         []string   `tfsdk:"labels"`
+	Name           []string   `tfsdk:"name"`
+	ProjectRoot    []string   `tfsdk:"project_root"`
+	Repository     []string   `tfsdk:"repository"`
+	WorkerPool     []string   `tfsdk:"worker_pool"`
+}
+
+func (r *moduleListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Diagnostics.Append(r.schemaDiagnostics()...)
+
+	resp.Schema = listschema.Schema{
+		Description: "Lists modules in the Spacelift account visible to the API user, matching predicates.",
+		Attributes: map[string]listschema.Attribute{
+			"administrative": listschema.BoolAttribute{
+				Description: "Require modules to be administrative or not",
+				Optional:    true,
+			},
+			"branch": listschema.ListAttribute{
+				Description: "Require modules to be on one of the branches",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"labels": listschema.ListAttribute{
+				Description: "Require modules to have all of the labels",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"name": listschema.ListAttribute{
+				Description: "Require modules to have one of the names",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"project_root": listschema.ListAttribute{
+				Description: "Require modules to be in one of the project roots",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"repository": listschema.ListAttribute{
+				Description: "Require modules to be in one of the repositories",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"worker_pool": listschema.ListAttribute{
+				Description: "Require modules to use one of the worker pools",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func (r *moduleListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config moduleListModel
+	if diags := req.Config.Get(ctx, &config); diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	var conditions []search.SearchQueryPredicate
+
+	if !config.Administrative.IsNull() {
+		conditions = append(conditions, predicates.Boolean("administrative", config.Administrative.ValueBool()))
+	}
+	if len(config.Branch) > 0 {
+		conditions = append(conditions, predicates.StringOrEnum("branch", false, config.Branch...))
+	}
+	for _, label := range config.Labels {
+		conditions = append(conditions, predicates.StringOrEnum("label", false, label))
+	}
+	if len(config.Name) > 0 {
+		conditions = append(conditions, predicates.StringOrEnum("name", false, config.Name...))
+	}
+	if len(config.ProjectRoot) > 0 {
+		conditions = append(conditions, predicates.StringOrEnum("projectRoot", false, config.ProjectRoot...))
+	}
+	if len(config.Repository) > 0 {
+		conditions = append(conditions, predicates.StringOrEnum("repository", false, config.Repository...))
+	}
+	if len(config.WorkerPool) > 0 {
+		conditions = append(conditions, predicates.StringOrEnum("workerPool", false, config.WorkerPool...))
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		var query struct {
+			SearchModulesOutput struct {
+				Edges []struct {
+					Node listedEntity `graphql:"node"`
+				} `graphql:"edges"`
+				PageInfo search.PageInfo `graphql:"pageInfo"`
+			} `graphql:"searchModules(input: $input)"`
+		}
+
+		input := search.SearchInput{
+			First:      graphql.NewInt(listPageSize),
+			Predicates: &conditions,
+		}
+
+		for {
+			variables := map[string]any{"input": input}
+
+			if err := r.client.Query(ctx, "ModulesPage", &query, variables); err != nil {
+				push(listResultError("could not query for modules", err))
+				return
+			}
+
+			for _, edge := range query.SearchModulesOutput.Edges {
+				if !push(r.result(ctx, req, edge.Node.ID, edge.Node.Name)) {
+					return
+				}
+			}
+
+			if !query.SearchModulesOutput.PageInfo.HasNextPage {
+				return
+			}
+
+			after := graphql.String(query.SearchModulesOutput.PageInfo.EndCursor)
+			input.After = &after
+		}
+	}
+}
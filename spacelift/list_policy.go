@@ -0,0 +1,130 @@
+package spacelift
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	listschema "github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/shurcooL/graphql"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs/search"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs/search/predicates"
+)
+
+var (
+	_ list.ListResourceWithConfigure    = (*policyListResource)(nil)
+	_ list.ListResourceWithRawV6Schemas = (*policyListResource)(nil)
+)
+
+// NewPolicyListResource returns the list resource for spacelift_policy, used
+// by `list` blocks and `terraform query`.
+func NewPolicyListResource() list.ListResource {
+	return &policyListResource{sdkListResource{
+		typeName:          "spacelift_policy",
+		resource:          resourcePolicy,
+		identityAttribute: "policy_id",
+	}}
+}
+
+type policyListResource struct {
+	sdkListResource
+}
+
+type policyListModel struct {
+	Labels  []string `tfsdk:"labels"`
+	Name    []string `tfsdk:"name"`
+	SpaceID []string `tfsdk:"space_id"`
+	Type    []string `tfsdk:"type"`
+}
+
+func (r *policyListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Diagnostics.Append(r.schemaDiagnostics()...)
+
+	resp.Schema = listschema.Schema{
+		Description: "Lists policies in the Spacelift account visible to the API user, matching predicates.",
+		Attributes: map[string]listschema.Attribute{
+			"labels": listschema.ListAttribute{
+				Description: "Require policies to have all of the labels",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"name": listschema.ListAttribute{
+				Description: "Require policies to have one of the names",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"space_id": listschema.ListAttribute{
+				Description: "Require policies to be in one of the spaces",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"type": listschema.ListAttribute{
+				Description: "Require policies to be of one of the types",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func (r *policyListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config policyListModel
+	if diags := req.Config.Get(ctx, &config); diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	var conditions []search.SearchQueryPredicate
+
+	for _, label := range config.Labels {
+		conditions = append(conditions, predicates.StringOrEnum("label", false, label))
+	}
+	if len(config.Name) > 0 {
+		conditions = append(conditions, predicates.StringOrEnum("name", false, config.Name...))
+	}
+	if len(config.SpaceID) > 0 {
+		conditions = append(conditions, predicates.StringOrEnum("space", false, config.SpaceID...))
+	}
+	if len(config.Type) > 0 {
+		conditions = append(conditions, predicates.StringOrEnum("type", true, config.Type...))
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		var query struct {
+			SearchPoliciesOutput struct {
+				Edges []struct {
+					Node listedEntity `graphql:"node"`
+				} `graphql:"edges"`
+				PageInfo search.PageInfo `graphql:"pageInfo"`
+			} `graphql:"searchPolicies(input: $input)"`
+		}
+
+		input := search.SearchInput{
+			First:      graphql.NewInt(listPageSize),
+			Predicates: &conditions,
+		}
+
+		for {
+			variables := map[string]any{"input": input}
+
+			if err := r.client.Query(ctx, "PoliciesPage", &query, variables); err != nil {
+				push(listResultError("could not query for policies", err))
+				return
+			}
+
+			for _, edge := range query.SearchPoliciesOutput.Edges {
+				if !push(r.result(ctx, req, edge.Node.ID, edge.Node.Name)) {
+					return
+				}
+			}
+
+			if !query.SearchPoliciesOutput.PageInfo.HasNextPage {
+				return
+			}
+
+			after := graphql.String(query.SearchPoliciesOutput.PageInfo.EndCursor)
+			input.After = &after
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package spacelift
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	fwdiag "github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-mux/tf5to6server"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+)
+
+// listPageSize is the number of entities requested per search page while
+// listing resources for `terraform query`.
+const listPageSize = 50
+
+// listedEntity is all a list resource needs from a search result: the
+// resource itself is read separately, and only when Terraform asks for it.
+type listedEntity struct {
+	ID   string `graphql:"id"`
+	Name string `graphql:"name"`
+}
+
+// sdkListResource holds what every list resource needs when the managed
+// resource it lists is still implemented with SDKv2: the client, the managed
+// resource itself and the name of the identity attribute carrying its ID.
+type sdkListResource struct {
+	client *internal.Client
+
+	typeName          string
+	resource          func() *schema.Resource
+	identityAttribute string
+}
+
+func (r *sdkListResource) Metadata(_ context.Context, _ resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = r.typeName
+}
+
+func (r *sdkListResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// ProviderData is nil during schema-validation walks.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*internal.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"unexpected provider data",
+			fmt.Sprintf("expected *internal.Client, got %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+// RawV6Schemas hands the Framework the schemas of the SDKv2 resource, which it
+// has no other way to learn about. Taking them from the upgraded SDKv2 server
+// guarantees they are exactly the ones the mux serves for the resource type.
+func (r *sdkListResource) RawV6Schemas(_ context.Context, _ list.RawV6SchemaRequest, resp *list.RawV6SchemaResponse) {
+	schemas, err := sdkResourceSchemas()
+	if err != nil {
+		return
+	}
+
+	resp.ProtoV6Schema = schemas.resources.ResourceSchemas[r.typeName]
+	resp.ProtoV6IdentitySchema = schemas.identities.IdentitySchemas[r.typeName]
+}
+
+// schemaDiagnostics reports why the schemas of the SDKv2 resource could not be
+// resolved. RawV6Schemas has no diagnostics of its own, so the list resources
+// report it with their config schema, which Terraform asks for on startup.
+func (r *sdkListResource) schemaDiagnostics() fwdiag.Diagnostics {
+	var diags fwdiag.Diagnostics
+
+	if _, err := sdkResourceSchemas(); err != nil {
+		diags.AddError(fmt.Sprintf("could not resolve the schemas of %s", r.typeName), err.Error())
+	}
+
+	return diags
+}
+
+// result builds the list result for a single resource instance. The full
+// resource is only read when Terraform asks for it, which costs one query per
+// result on top of the search.
+func (r *sdkListResource) result(ctx context.Context, req list.ListRequest, id, displayName string) list.ListResult {
+	result := req.NewListResult(ctx)
+	result.DisplayName = displayName
+
+	result.Diagnostics.Append(result.Identity.SetAttribute(ctx, path.Root(r.identityAttribute), id)...)
+	if result.Diagnostics.HasError() || !req.IncludeResource {
+		return result
+	}
+
+	value, diags := r.read(ctx, req, id)
+	result.Diagnostics.Append(diags...)
+	if !result.Diagnostics.HasError() {
+		result.Resource.Raw = value
+	}
+
+	return result
+}
+
+// read runs the SDKv2 read of a resource instance and converts the resulting
+// state into the value the Framework expects.
+func (r *sdkListResource) read(ctx context.Context, req list.ListRequest, id string) (tftypes.Value, fwdiag.Diagnostics) {
+	var diags fwdiag.Diagnostics
+
+	res := r.resource()
+
+	d := res.Data(nil)
+	d.SetId(id)
+
+	diags.Append(fromSDKDiagnostics(res.ReadContext(ctx, d, r.client))...)
+	if diags.HasError() {
+		return tftypes.Value{}, diags
+	}
+
+	state := d.State()
+	if state == nil {
+		diags.AddError("could not read resource", fmt.Sprintf("%s %q no longer exists", r.typeName, id))
+		return tftypes.Value{}, diags
+	}
+
+	value, err := schema.StateValueFromInstanceState(state, res.CoreConfigSchema().ImpliedType())
+	if err != nil {
+		diags.AddError("could not convert resource state", err.Error())
+		return tftypes.Value{}, diags
+	}
+
+	raw, err := ctyjson.Marshal(value, value.Type())
+	if err != nil {
+		diags.AddError("could not convert resource state", err.Error())
+		return tftypes.Value{}, diags
+	}
+
+	converted, err := tftypes.ValueFromJSONWithOpts(
+		raw,
+		req.ResourceSchema.Type().TerraformType(ctx),
+		tftypes.ValueFromJSONOpts{IgnoreUndefinedAttributes: true},
+	)
+	if err != nil {
+		diags.AddError("could not convert resource state", err.Error())
+		return tftypes.Value{}, diags
+	}
+
+	return converted, diags
+}
+
+// sdkSchemas are the protocol 6 resource and identity schemas of all SDKv2
+// resources.
+type sdkSchemas struct {
+	resources  *tfprotov6.GetProviderSchemaResponse
+	identities *tfprotov6.GetResourceIdentitySchemasResponse
+}
+
+// sdkResourceSchemas resolves the schemas of all SDKv2 resources once per
+// process.
+var sdkResourceSchemas = sync.OnceValues(func() (*sdkSchemas, error) {
+	ctx := context.Background()
+
+	server, err := tf5to6server.UpgradeServer(ctx, Provider("", "")().GRPCProvider)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not upgrade the SDKv2 provider to protocol 6")
+	}
+
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get the SDKv2 resource schemas")
+	}
+	if err := protoDiagnosticsError(schemas.Diagnostics); err != nil {
+		return nil, errors.Wrap(err, "could not get the SDKv2 resource schemas")
+	}
+
+	identities, err := server.GetResourceIdentitySchemas(ctx, &tfprotov6.GetResourceIdentitySchemasRequest{})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get the SDKv2 resource identity schemas")
+	}
+	if err := protoDiagnosticsError(identities.Diagnostics); err != nil {
+		return nil, errors.Wrap(err, "could not get the SDKv2 resource identity schemas")
+	}
+
+	return &sdkSchemas{resources: schemas, identities: identities}, nil
+})
+
+// protoDiagnosticsError returns the first error among protocol diagnostics.
+func protoDiagnosticsError(diagnostics []*tfprotov6.Diagnostic) error {
+	for _, d := range diagnostics {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			return errors.Errorf("%s: %s", d.Summary, d.Detail)
+		}
+	}
+
+	return nil
+}
+
+// fromSDKDiagnostics converts SDKv2 diagnostics into Framework ones.
+func fromSDKDiagnostics(in diag.Diagnostics) fwdiag.Diagnostics {
+	var out fwdiag.Diagnostics
+
+	for _, d := range in {
+		if d.Severity == diag.Error {
+			out.AddError(d.Summary, d.Detail)
+		} else {
+			out.AddWarning(d.Summary, d.Detail)
+		}
+	}
+
+	return out
+}
+
+// listResultError is a result carrying nothing but an error, which ends the
+// listing when pushed to the stream.
+func listResultError(summary string, err error) list.ListResult {
+	var diags fwdiag.Diagnostics
+	diags.AddError(summary, err.Error())
+
+	return list.ListResult{Diagnostics: diags}
+}
@@ -0,0 +1,96 @@
+package spacelift
+
+import (
+	"context"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	listschema "github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs"
+)
+
+var (
+	_ list.ListResourceWithConfigure    = (*spaceListResource)(nil)
+	_ list.ListResourceWithRawV6Schemas = (*spaceListResource)(nil)
+)
+
+// NewSpaceListResource returns the list resource for spacelift_space, used by
+// `list` blocks and `terraform query`.
+func NewSpaceListResource() list.ListResource {
+	return &spaceListResource{sdkListResource{
+		typeName:          "spacelift_space",
+		resource:          resourceSpace,
+		identityAttribute: "space_id",
+	}}
+}
+
+type spaceListResource struct {
+	sdkListResource
+}
+
+type spaceListModel struct {
+	Labels        []string `tfsdk:"labels"`
+	ParentSpaceID []string `tfsdk:"parent_space_id"`
+}
+
+func (r *spaceListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Diagnostics.Append(r.schemaDiagnostics()...)
+
+	resp.Schema = listschema.Schema{
+		Description: "Lists spaces in the Spacelift account visible to the API user, matching predicates.",
+		Attributes: map[string]listschema.Attribute{
+			"labels": listschema.ListAttribute{
+				Description: "Require spaces to have all of the labels",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"parent_space_id": listschema.ListAttribute{
+				Description: "Require spaces to be direct children of one of the spaces",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func (r *spaceListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config spaceListModel
+	if diags := req.Config.Get(ctx, &config); diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	// Spaces have no search endpoint, so the filtering happens here, the same
+	// way spacelift_spaces does it.
+	var labelFilters [][]string
+	for _, label := range config.Labels {
+		labelFilters = append(labelFilters, []string{label})
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		var query struct {
+			Spaces []structs.Space `graphql:"spaces()"`
+		}
+
+		if err := r.client.Query(ctx, "SpacesRead", &query, nil); err != nil {
+			push(listResultError("could not query for spaces", err))
+			return
+		}
+
+		for _, space := range query.Spaces {
+			if !matchesLabels(space.Labels, labelFilters) {
+				continue
+			}
+
+			if len(config.ParentSpaceID) > 0 && (space.ParentSpace == nil || !slices.Contains(config.ParentSpaceID, *space.ParentSpace)) {
+				continue
+			}
+
+			if !push(r.result(ctx, req, space.ID, space.Name)) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package spacelift
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	listschema "github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/shurcooL/graphql"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs/search"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs/search/predicates"
+)
+
+var (
+	_ list.ListResourceWithConfigure    = (*stackListResource)(nil)
+	_ list.ListResourceWithRawV6Schemas = (*stackListResource)(nil)
+)
+
+// NewStackListResource returns the list resource for spacelift_stack, used by
+// `list` blocks and `terraform query`.
+func NewStackListResource() list.ListResource {
+	return &stackListResource{sdkListResource{
+		typeName:          "spacelift_stack",
+		resource:          resourceStack,
+		identityAttribute: "stack_id",
+	}}
+}
+
+type stackListResource struct {
+	sdkListResource
+}
+
+type stackListModel struct {
+	Administrative types.Bool `tfsdk:"administrative"`
+	Branch         []string   `tfsdk:"branch"`
+	Labels         []string   `tfsdk:"labels"`
+	Locked         types.Bool `tfsdk:"locked"`
+	Name           []string   `tfsdk:"name"`
+	ProjectRoot    []string   `tfsdk:"project_root"`
+	Repository     []string   `tfsdk:"repository"`
+	State          []string   `tfsdk:"state"`
+	Vendor         []string   `tfsdk:"vendor"`
+	WorkerPool     []string   `tfsdk:"worker_pool"`
+}
+
+func (r *stackListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Diagnostics.Append(r.schemaDiagnostics()...)
+
+	resp.Schema = listschema.Schema{
+		Description: "Lists stacks in the Spacelift account visible to the API user, matching predicates.",
+		Attributes: map[string]listschema.Attribute{
+			"administrative": listschema.BoolAttribute{
+				Description: "Require stacks to be administrative or not",
+				Optional:    true,
+			},
+			"branch": listschema.ListAttribute{
+				Description: "Require stacks to be on one of the branches",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"labels": listschema.ListAttribute{
+				Description: "Require stacks to have all of the labels",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"locked": listschema.BoolAttribute{
+				Description: "Require stacks to be locked or not",
+				Optional:    true,
+			},
+			"name": listschema.ListAttribute{
+				Description: "Require stacks to have one of the names",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"project_root": listschema.ListAttribute{
+				Description: "Require stacks to be in one of the project roots",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"repository": listschema.ListAttribute{
+				Description: "Require stacks to be in one of the repositories",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"state": listschema.ListAttribute{
+				Description: "Require stacks to have one of the states",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"vendor": listschema.ListAttribute{
+				Description: "Require stacks to use one of the IaC vendors",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"worker_pool": listschema.ListAttribute{
+				Description: "Require stacks to use one of the worker pools",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func (r *stackListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config stackListModel
+	if diags := req.Config.Get(ctx, &config); diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	var conditions []search.SearchQueryPredicate
+
+	if !config.Administrative.IsNull() {
+		conditions = append(conditions, predicates.Boolean("administrative", config.Administrative.ValueBool()))
+	}
+	if len(config.Branch) > 0 {
+		conditions = append(conditions, predicates.StringOrEnum("branch", false, config.Branch...))
+	}
+	for _, label := range config.Labels {
+		conditions = append(conditions, predicates.StringOrEnum("label", false, label))
+	}
+	if !config.Locked.IsNull() {
+		conditions = append(conditions, predicates.Boolean("locked", config.Locked.ValueBool()))
+	}
+	if len(config.Name) > 0 {
+		conditions = append(conditions, predicates.StringOrEnum("name", false, config.Name...))
+	}
+	if len(config.ProjectRoot) > 0 {
+		conditions = append(conditions, predicates.StringOrEnum("projectRoot", false, config.ProjectRoot...))
+	}
+	if len(config.Repository) > 0 {
+		conditions = append(conditions, predicates.StringOrEnum("repository", false, config.Repository...))
+	}
+	if len(config.State) > 0 {
+		conditions = append(conditions, predicates.StringOrEnum("state", true, config.State...))
+	}
+	if len(config.Vendor) > 0 {
+		conditions = append(conditions, predicates.StringOrEnum("vendor", true, config.Vendor...))
+	}
+	if len(config.WorkerPool) > 0 {
+		conditions = append(conditions, predicates.StringOrEnum("workerPool", false, config.WorkerPool...))
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		var query struct {
+			SearchStacksOutput struct {
+				Edges []struct {
+					Node listedEntity `graphql:"node"`
+				} `graphql:"edges"`
+				PageInfo search.PageInfo `graphql:"pageInfo"`
+			} `graphql:"searchStacks(input: $input)"`
+		}
+
+		input := search.SearchInput{
+			First:      graphql.NewInt(listPageSize),
+			Predicates: &conditions,
+		}
+
+		for {
+			variables := map[string]any{"input": input}
+
+			if err := r.client.Query(ctx, "StacksPage", &query, variables); err != nil {
+				push(listResultError("could not query for stacks", err))
+				return
+			}
+
+			for _, edge := range query.SearchStacksOutput.Edges {
+				if !push(r.result(ctx, req, edge.Node.ID, edge.Node.Name)) {
+					return
+				}
+			}
+
+			if !query.SearchStacksOutput.PageInfo.HasNextPage {
+				return
+			}
+
+			after := graphql.String(query.SearchStacksOutput.PageInfo.EndCursor)
+			input.After = &after
+		}
+	}
+}
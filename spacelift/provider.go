@@ -59,6 +59,7 @@ func Provider(commit, version string) plugin.ProviderFunc {
 				"spacelift_aws_integrations":                       dataAWSIntegrations(),
 				"spacelift_aws_integration_attachment":             dataAWSIntegrationAttachment(),
 				"spacelift_aws_integration_attachment_external_id": dataAWSIntegrationAttachmentExternalID(),
+				"spacelift_autoattachments":                        dataAutoattachments(),
 				"spacelift_azure_devops_integration":               dataAzureDevopsIntegration(),
 				"spacelift_azure_integration":                      dataAzureIntegration(),
 				"spacelift_azure_integrations":                     dataAzureIntegrations(),
@@ -91,6 +92,8 @@ func Provider(commit, version string) plugin.ProviderFunc {
 				"spacelift_repos":                                  dataRepos(),
 				"spacelift_role":                                   dataRole(),
 				"spacelift_role_actions":                           dataRoleActions(),
+				"spacelift_run_changes":                            dataRunChanges(),
+				"spacelift_run_logs":                               dataRunLogs(),
 				"spacelift_space":                                  dataSpace(),
 				"spacelift_spaces":                                 dataSpaces(),
 				"spacelift_space_by_path":                          dataSpaceByPath(),
@@ -98,7 +101,11 @@ func Provider(commit, version string) plugin.ProviderFunc {
 				"spacelift_scheduled_run":                          dataScheduledRun(),
 				"spacelift_scheduled_delete_stack":                 dataScheduledDeleteStack(),
 				"spacelift_stack":                                  dataStack(),
+				"spacelift_stack_dependency_graph":                 dataStackDependencyGraph(),
+				"spacelift_stack_effective_config":                 dataStackEffectiveConfig(),
 				"spacelift_stack_outputs":                          dataStackOutputs(),
+				"spacelift_stack_runs":                             dataStackRuns(),
+				"spacelift_stack_state_versions":                   dataStackStateVersions(),
 				"spacelift_stacks":                                 dataStacks(),
 				"spacelift_template":                               dataTemplate(),
 				"spacelift_template_deployment":                    dataTemplateDeployment(),
@@ -125,6 +132,7 @@ func Provider(commit, version string) plugin.ProviderFunc {
 				"spacelift_azure_integration":                resourceAzureIntegration(),
 				"spacelift_bitbucket_datacenter_integration": resourceBitbucketDatacenterIntegration(),
 				"spacelift_blueprint":                        resourceBlueprint(),
+				"spacelift_blueprint_stack":                  resourceBlueprintStack(),
 				"spacelift_template_deployment":              resourceTemplateDeployment(),
 				"spacelift_context_attachment":               resourceContextAttachment(),
 				"spacelift_context":                          resourceContext(),
@@ -147,6 +155,7 @@ func Provider(commit, version string) plugin.ProviderFunc {
 				"spacelift_role_attachment":                  resourceRoleAttachment(),
 				"spacelift_role":                             resourceRole(),
 				"spacelift_run":                              resourceRun(),
+				"spacelift_run_review":                       resourceRunReview(),
 				"spacelift_saved_filter":                     resourceSavedFilter(),
 				"spacelift_scheduled_delete_stack":           resourceScheduledDeleteStack(),
 				"spacelift_scheduled_run":                    resourceScheduledRun(),
@@ -158,6 +167,9 @@ func Provider(commit, version string) plugin.ProviderFunc {
 				"spacelift_stack_dependency_reference":       resourceStackDependencyReference(),
 				"spacelift_stack_destructor":                 resourceStackDestructor(),
 				"spacelift_stack_gcp_service_account":        resourceStackGCPServiceAccount(), // deprecated
+				"spacelift_stack_lock":                       resourceStackLock(),
+				"spacelift_stack_set":                        resourceStackSet(),
+				"spacelift_stack_state":                      resourceStackState(),
 				"spacelift_stack":                            resourceStack(),
 				"spacelift_task":                             resourceTask(),
 				"spacelift_template":                         resourceTemplate(),
@@ -6,7 +6,9 @@ import (
 	"os"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/action"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/list"
 	fwprovider "github.com/hashicorp/terraform-plugin-framework/provider"
 	fwschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -15,6 +17,11 @@ import (
 	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
 )
 
+var (
+	_ fwprovider.ProviderWithActions       = (*frameworkProvider)(nil)
+	_ fwprovider.ProviderWithListResources = (*frameworkProvider)(nil)
+)
+
 type frameworkProvider struct {
 	commit  string
 	version string
@@ -121,11 +128,39 @@ func (p *frameworkProvider) Configure(ctx context.Context, req fwprovider.Config
 
 	resp.DataSourceData = client
 	resp.ResourceData = client
+	resp.ListResourceData = client
+	resp.ActionData = client
 }
 
 func (p *frameworkProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewStackDependencyResource,
+		NewStackDependenciesResource,
+	}
+}
+
+// ListResources serves `list` blocks and `terraform query`. The managed resources
+// behind them may still be SDKv2 ones; see sdkListResource.
+func (p *frameworkProvider) ListResources(_ context.Context) []func() list.ListResource {
+	return []func() list.ListResource{
+		NewContextListResource,
+		NewModuleListResource,
+		NewPolicyListResource,
+		NewSpaceListResource,
+		NewStackListResource,
+	}
+}
+
+// Actions are the stateless counterparts of resources which only exist to
+// trigger something, like spacelift_run or spacelift_worker_pool_recycle.
+func (p *frameworkProvider) Actions(_ context.Context) []func() action.Action {
+	return []func() action.Action{
+		NewLockStackAction,
+		NewPublishModuleVersionAction,
+		NewRecycleWorkerPoolAction,
+		NewRunTaskAction,
+		NewTriggerRunAction,
+		NewUnlockStackAction,
 	}
 }
 
@@ -2,6 +2,7 @@ package spacelift
 
 import (
 	"context"
+	"slices"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
@@ -43,3 +44,136 @@ func TestMuxedProviderSchema(t *testing.T) {
 		}
 	}
 }
+
+// TestMuxedProviderListResources checks that every list resource is served next to the
+// identity schema of the managed resource it lists. The managed resources are SDKv2 ones,
+// so the Framework only learns their schemas through RawV6Schemas, and a list resource
+// without them is dropped with an error diagnostic at startup.
+func TestMuxedProviderListResources(t *testing.T) {
+	t.Parallel()
+
+	server, err := testAccProtoV6MuxProviderFactories()["spacelift"]()
+	if err != nil {
+		t.Fatalf("could not build the muxed provider: %v", err)
+	}
+
+	ctx := context.Background()
+
+	schemaResp, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("could not resolve the muxed provider schema: %v", err)
+	}
+
+	for _, diagnostic := range schemaResp.Diagnostics {
+		if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Errorf("schema error: %s: %s", diagnostic.Summary, diagnostic.Detail)
+		}
+	}
+
+	identityResp, err := server.GetResourceIdentitySchemas(ctx, &tfprotov6.GetResourceIdentitySchemasRequest{})
+	if err != nil {
+		t.Fatalf("could not resolve the identity schemas: %v", err)
+	}
+
+	for name, identityAttribute := range map[string]string{
+		"spacelift_context": "context_id",
+		"spacelift_module":  "module_id",
+		"spacelift_policy":  "policy_id",
+		"spacelift_space":   "space_id",
+		"spacelift_stack":   "stack_id",
+	} {
+		if _, ok := schemaResp.ListResourceSchemas[name]; !ok {
+			t.Errorf("%s is not served as a list resource", name)
+		}
+
+		identity, ok := identityResp.IdentitySchemas[name]
+		if !ok {
+			t.Errorf("%s has no identity schema", name)
+			continue
+		}
+
+		if len(identity.IdentityAttributes) != 1 || identity.IdentityAttributes[0].Name != identityAttribute {
+			t.Errorf("%s identity should consist of %s only", name, identityAttribute)
+		}
+	}
+}
+
+// TestMuxedProviderActions checks that every action is served by the muxed provider,
+// and that the actions which trigger runs accept the same wait block as the resources.
+func TestMuxedProviderActions(t *testing.T) {
+	t.Parallel()
+
+	server, err := testAccProtoV6MuxProviderFactories()["spacelift"]()
+	if err != nil {
+		t.Fatalf("could not build the muxed provider: %v", err)
+	}
+
+	schemaResp, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("could not resolve the muxed provider schema: %v", err)
+	}
+
+	for name, waits := range map[string]bool{
+		"spacelift_lock_stack":             false,
+		"spacelift_publish_module_version": false,
+		"spacelift_recycle_worker_pool":    false,
+		"spacelift_run_task":               true,
+		"spacelift_trigger_run":            true,
+		"spacelift_unlock_stack":           false,
+	} {
+		action, ok := schemaResp.ActionSchemas[name]
+		if !ok {
+			t.Errorf("%s is not served as an action", name)
+			continue
+		}
+
+		hasWait := false
+		for _, block := range action.Schema.Block.BlockTypes {
+			hasWait = hasWait || block.TypeName == "wait"
+		}
+
+		if hasWait != waits {
+			t.Errorf("%s should have a wait block: %t", name, waits)
+		}
+	}
+}
+
+// TestMuxedProviderAttachmentIdentities checks the identity schemas of the resources
+// whose IDs are put together from the IDs of the entities they attach.
+func TestMuxedProviderAttachmentIdentities(t *testing.T) {
+	t.Parallel()
+
+	server, err := testAccProtoV6MuxProviderFactories()["spacelift"]()
+	if err != nil {
+		t.Fatalf("could not build the muxed provider: %v", err)
+	}
+
+	identityResp, err := server.GetResourceIdentitySchemas(context.Background(), &tfprotov6.GetResourceIdentitySchemasRequest{})
+	if err != nil {
+		t.Fatalf("could not resolve the identity schemas: %v", err)
+	}
+
+	for name, expected := range map[string][]string{
+		"spacelift_aws_integration_attachment": {"integration_id", "module_id", "stack_id"},
+		"spacelift_context_attachment":         {"context_id", "module_id", "stack_id"},
+		"spacelift_policy_attachment":          {"module_id", "policy_id", "stack_id"},
+		"spacelift_role_attachment":            {"api_key_id", "binding_id", "idp_group_mapping_id", "stack_id", "user_id"},
+		"spacelift_stack_dependency_reference": {"depends_on_stack_id", "input_name", "stack_id"},
+	} {
+		identity, ok := identityResp.IdentitySchemas[name]
+		if !ok {
+			t.Errorf("%s has no identity schema", name)
+			continue
+		}
+
+		var actual []string
+		for _, attribute := range identity.IdentityAttributes {
+			actual = append(actual, attribute.Name)
+		}
+		slices.Sort(actual)
+
+		if !slices.Equal(actual, expected) {
+			t.Errorf("%s identity should consist of %v, got %v", name, expected, actual)
+		}
+	}
+}
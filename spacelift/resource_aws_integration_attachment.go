@@ -27,9 +27,11 @@ func resourceAWSIntegrationAttachment() *schema.Resource {
 		DeleteContext: resourceAWSIntegrationAttachmentDelete,
 
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceAWSIntegrationAttachmentImport,
 		},
 
+		Identity: attachmentIdentity("integration_id", "ID of the attached integration"),
+
 		Schema: map[string]*schema.Schema{
 			"integration_id": {
 				Type:             schema.TypeString,
@@ -142,7 +144,13 @@ func resourceAWSIntegrationAttachmentRead(ctx context.Context, d *schema.Resourc
 		return nil
 	}
 
-	query.AWSIntegration.Attachment.PopulateResourceData(d)
+	attachment := query.AWSIntegration.Attachment
+
+	if err := setAttachmentIdentity(d, "integration_id", integrationID, projectID, attachment.IsModule); err != nil {
+		return diag.Errorf("could not set aws integration attachment identity: %v", err)
+	}
+
+	attachment.PopulateResourceData(d)
 
 	// This is to allow importing.
 	d.Set("integration_id", integrationID)
@@ -150,6 +158,19 @@ func resourceAWSIntegrationAttachmentRead(ctx context.Context, d *schema.Resourc
 	return nil
 }
 
+func resourceAWSIntegrationAttachmentImport(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
+	if d.Id() == "" {
+		integrationID, projectID, err := attachmentFromIdentity(d, "integration_id")
+		if err != nil {
+			return nil, err
+		}
+
+		d.SetId(fmt.Sprintf("%s/%s", integrationID, projectID))
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceAWSIntegrationAttachmentUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	var mutation struct {
 		AWSIntegrationAttachmentUpdate structs.AWSIntegrationAttachment `graphql:"awsIntegrationAttachmentUpdate(id: $id, read: $read, write: $write)"`
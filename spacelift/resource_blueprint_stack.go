@@ -0,0 +1,279 @@
+package spacelift
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/graphql"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/validations"
+)
+
+const (
+	blueprintStackOnInputsChangeRecreate = "RECREATE"
+	blueprintStackOnInputsChangeFail     = "FAIL"
+)
+
+func resourceBlueprintStack() *schema.Resource {
+	return &schema.Resource{
+		Description: "" +
+			"`spacelift_blueprint_stack` creates a stack from a published " +
+			"`spacelift_blueprint`. The inputs are checked against the inputs " +
+			"declared by the blueprint at plan time. Blueprints do not keep track " +
+			"of the stacks created from them, so the stack is never updated in " +
+			"place: changing the inputs either recreates the stack or fails the plan.",
+
+		CreateContext: resourceBlueprintStackCreate,
+		ReadContext:   resourceBlueprintStackRead,
+		UpdateContext: resourceBlueprintStackUpdate,
+		DeleteContext: resourceStackDelete,
+
+		CustomizeDiff: resourceBlueprintStackCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"blueprint_id": {
+				Type:             schema.TypeString,
+				Description:      "ID of the published blueprint to create the stack from",
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validations.DisallowEmptyString,
+			},
+			"inputs": {
+				Type:        schema.TypeMap,
+				Description: "Values of the inputs declared by the blueprint, keyed by input ID. Values of `number`, `float` and `boolean` inputs must parse as such, and values of `select` inputs must be one of the declared options. Inputs with a default value may be omitted.",
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"secret_inputs": {
+				Type:        schema.TypeMap,
+				Description: "Values of the `secret` inputs declared by the blueprint, keyed by input ID. Secret inputs can only be set here, so that their values never show up in plans.",
+				Optional:    true,
+				Sensitive:   true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"on_inputs_change": {
+				Type:         schema.TypeString,
+				Description:  "What to do when the inputs change after the stack has been created: `RECREATE` deletes the stack and creates a new one, `FAIL` fails the plan. Defaults to `RECREATE`.",
+				Optional:     true,
+				Default:      blueprintStackOnInputsChangeRecreate,
+				ValidateFunc: validation.StringInSlice([]string{blueprintStackOnInputsChangeRecreate, blueprintStackOnInputsChangeFail}, false),
+			},
+			"stack_id": {
+				Type:        schema.TypeString,
+				Description: "ID (slug) of the stack created from the blueprint",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceBlueprintStackCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, meta any) error {
+	if diff.Id() != "" && (diff.HasChange("inputs") || diff.HasChange("secret_inputs")) {
+		if diff.Get("on_inputs_change").(string) == blueprintStackOnInputsChangeFail {
+			return errors.Errorf("the inputs of the stack %s created from blueprint %s changed; set on_inputs_change to %s to recreate it", diff.Id(), diff.Get("blueprint_id"), blueprintStackOnInputsChangeRecreate)
+		}
+
+		for _, key := range []string{"inputs", "secret_inputs"} {
+			if diff.HasChange(key) {
+				if err := diff.ForceNew(key); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// An existing stack is only checked again if it is about to be recreated,
+	// so that unpublishing the blueprint does not break plans.
+	if diff.Id() != "" && !diff.HasChanges("blueprint_id", "inputs", "secret_inputs") {
+		return nil
+	}
+
+	// Inputs which are only known after apply can't be checked until then.
+	if !diff.NewValueKnown("blueprint_id") ||
+		!diff.GetRawConfig().GetAttr("inputs").IsWhollyKnown() ||
+		!diff.GetRawConfig().GetAttr("secret_inputs").IsWhollyKnown() {
+		return nil
+	}
+
+	blueprintID := diff.Get("blueprint_id").(string)
+
+	blueprint, err := getBlueprintInputs(ctx, meta.(*internal.Client), blueprintID)
+	if err != nil {
+		return err
+	}
+
+	if blueprint.State != "PUBLISHED" {
+		return errors.Errorf("blueprint %s is in %s state, only published blueprints can create stacks", blueprintID, blueprint.State)
+	}
+
+	return validateBlueprintInputs(blueprint.Inputs, toStringMap(diff.Get("inputs")), toStringMap(diff.Get("secret_inputs")))
+}
+
+// blueprintInputs is a blueprint along with the inputs it declares.
+type blueprintInputs struct {
+	State  string                   `graphql:"state"`
+	Inputs []structs.BlueprintInput `graphql:"inputs"`
+}
+
+func getBlueprintInputs(ctx context.Context, client *internal.Client, blueprintID string) (*blueprintInputs, error) {
+	var query struct {
+		Blueprint *blueprintInputs `graphql:"blueprint(id: $id)"`
+	}
+
+	variables := map[string]any{"id": graphql.ID(blueprintID)}
+
+	if err := client.Query(ctx, "BlueprintInputsRead", &query, variables); err != nil {
+		return nil, errors.Wrap(err, "could not query for blueprint inputs")
+	}
+
+	if query.Blueprint == nil {
+		return nil, errors.Errorf("blueprint %s not found", blueprintID)
+	}
+
+	return query.Blueprint, nil
+}
+
+// validateBlueprintInputs checks the values of the inputs against the inputs
+// declared by a blueprint, reporting every mismatch at once.
+func validateBlueprintInputs(declared []structs.BlueprintInput, inputs, secretInputs map[string]string) error {
+	var problems []string
+
+	byID := make(map[string]structs.BlueprintInput, len(declared))
+	for _, input := range declared {
+		byID[input.ID] = input
+
+		value, isPlain := inputs[input.ID]
+		secretValue, isSecret := secretInputs[input.ID]
+
+		switch {
+		case isPlain && isSecret:
+			problems = append(problems, fmt.Sprintf("input %q is set in both inputs and secret_inputs", input.ID))
+			continue
+		case input.Type == "secret" && isPlain:
+			problems = append(problems, fmt.Sprintf("input %q is a secret and must be set in secret_inputs", input.ID))
+			continue
+		case isSecret:
+			value = secretValue
+		case !isPlain:
+			if input.Default == nil {
+				problems = append(problems, fmt.Sprintf("input %q is required", input.ID))
+			}
+			continue
+		}
+
+		if err := validateBlueprintInputValue(input, value); err != nil && isSecret {
+			problems = append(problems, fmt.Sprintf("input %q %s", input.ID, err))
+		} else if err != nil {
+			problems = append(problems, fmt.Sprintf("input %q %s, got %q", input.ID, err, value))
+		}
+	}
+
+	for _, values := range []map[string]string{inputs, secretInputs} {
+		for id := range values {
+			if _, ok := byID[id]; !ok {
+				problems = append(problems, fmt.Sprintf("input %q is not declared by the blueprint", id))
+			}
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	slices.Sort(problems)
+
+	return errors.Errorf("inputs do not match the blueprint: %s", strings.Join(problems, "; "))
+}
+
+func validateBlueprintInputValue(input structs.BlueprintInput, value string) error {
+	switch input.Type {
+	case "number":
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return errors.Errorf("must be a whole number")
+		}
+	case "float":
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return errors.Errorf("must be a number")
+		}
+	case "boolean":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return errors.Errorf("must be true or false")
+		}
+	case "select":
+		if !slices.Contains(input.Options, value) {
+			return errors.Errorf("must be one of %s", strings.Join(input.Options, ", "))
+		}
+	}
+
+	return nil
+}
+
+func resourceBlueprintStackCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	var mutation struct {
+		BlueprintCreateStack struct {
+			StackSlug string `graphql:"stackSlug"`
+		} `graphql:"blueprintCreateStack(id: $id, input: $input)"`
+	}
+
+	input := structs.BlueprintStackCreateInput{TemplateInputs: []structs.BlueprintStackCreateInputPair{}}
+	for _, key := range []string{"inputs", "secret_inputs"} {
+		for id, value := range toStringMap(d.Get(key)) {
+			input.TemplateInputs = append(input.TemplateInputs, structs.BlueprintStackCreateInputPair{
+				ID:    graphql.String(id),
+				Value: graphql.String(value),
+			})
+		}
+	}
+
+	variables := map[string]any{
+		"id":    graphql.ID(d.Get("blueprint_id").(string)),
+		"input": input,
+	}
+
+	if err := meta.(*internal.Client).Mutate(ctx, "BlueprintCreateStack", &mutation, variables); err != nil {
+		return diag.Errorf("could not create stack from blueprint: %v", internal.FromSpaceliftError(err))
+	}
+
+	d.SetId(mutation.BlueprintCreateStack.StackSlug)
+
+	return resourceBlueprintStackRead(ctx, d, meta)
+}
+
+func resourceBlueprintStackRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	stack, err := getStackByID(ctx, meta.(*internal.Client), d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if stack == nil {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("stack_id", stack.ID)
+
+	return nil
+}
+
+func resourceBlueprintStackUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	// Changes to the inputs force a new stack, so the only thing which can
+	// change in place is on_inputs_change, which is not sent anywhere.
+	return resourceBlueprintStackRead(ctx, d, meta)
+}
+
+func toStringMap(raw any) map[string]string {
+	values := make(map[string]string)
+	for key, value := range raw.(map[string]any) {
+		values[key] = value.(string)
+	}
+	return values
+}
@@ -0,0 +1,176 @@
+package spacelift
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs"
+	. "github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/testhelpers"
+)
+
+func TestBlueprintStackResource(t *testing.T) {
+	const resourceName = "spacelift_blueprint_stack.test"
+
+	randomID := acctest.RandStringFromCharSet(5, acctest.CharSetAlphaNum)
+
+	config := func(environment, onInputsChange string) string {
+		return fmt.Sprintf(`
+			resource "spacelift_blueprint" "test" {
+				name     = "test-blueprint-stack-%s"
+				space    = "root"
+				state    = "PUBLISHED"
+				template = <<-EOT
+					inputs:
+					  - id: environment
+					    name: Environment
+					    type: select
+					    options: [dev, prod]
+					  - id: replicas
+					    name: Replicas
+					    type: number
+					    default: "1"
+					stack:
+					  name: blueprint-stack-%s-$${{ inputs.environment }}
+					  space: root
+					  vcs:
+					    branch: master
+					    repository: demo
+					    provider: GITHUB
+					  vendor:
+					    terraform:
+					      manage_state: true
+				EOT
+			}
+
+			resource "spacelift_blueprint_stack" "test" {
+				blueprint_id     = spacelift_blueprint.test.id
+				on_inputs_change = "%s"
+
+				inputs = {
+					environment = "%s"
+				}
+			}
+		`, randomID, strings.ToLower(randomID), onInputsChange, environment)
+	}
+
+	testSteps(t, []resource.TestStep{
+		{
+			Config: config("dev", "FAIL"),
+			Check: Resource(
+				resourceName,
+				Attribute("stack_id", StartsWith("blueprint-stack-")),
+				Attribute("stack_id", Contains("dev")),
+			),
+		},
+		{
+			Config:      config("prod", "FAIL"),
+			ExpectError: regexp.MustCompile(`set on_inputs_change to RECREATE to recreate it`),
+		},
+		{
+			Config:      config("staging", "RECREATE"),
+			ExpectError: regexp.MustCompile(`input "environment" must be one of dev, prod, got "staging"`),
+		},
+		{
+			Config: config("prod", "RECREATE"),
+			Check: Resource(
+				resourceName,
+				Attribute("stack_id", Contains("prod")),
+			),
+		},
+	})
+}
+
+func TestValidateBlueprintInputs(t *testing.T) {
+	defaultReplicas := "1"
+
+	declared := []structs.BlueprintInput{
+		{ID: "environment", Type: "select", Options: []string{"dev", "prod"}},
+		{ID: "replicas", Type: "number", Default: &defaultReplicas},
+		{ID: "ratio", Type: "float", Default: &defaultReplicas},
+		{ID: "public", Type: "boolean", Default: &defaultReplicas},
+		{ID: "token", Type: "secret", Default: &defaultReplicas},
+	}
+
+	for _, tc := range []struct {
+		name         string
+		inputs       map[string]string
+		secretInputs map[string]string
+		wantErr      []string
+	}{
+		{
+			name:   "valid with defaults",
+			inputs: map[string]string{"environment": "dev"},
+		},
+		{
+			name:         "valid with everything set",
+			inputs:       map[string]string{"environment": "prod", "replicas": "3", "ratio": "0.5", "public": "true"},
+			secretInputs: map[string]string{"token": "hunter2"},
+		},
+		{
+			name:    "missing required input",
+			inputs:  map[string]string{"replicas": "3"},
+			wantErr: []string{`input "environment" is required`},
+		},
+		{
+			name:   "values of the wrong type",
+			inputs: map[string]string{"environment": "staging", "replicas": "1.5", "ratio": "half", "public": "maybe"},
+			wantErr: []string{
+				`input "environment" must be one of dev, prod, got "staging"`,
+				`input "public" must be true or false, got "maybe"`,
+				`input "ratio" must be a number, got "half"`,
+				`input "replicas" must be a whole number, got "1.5"`,
+			},
+		},
+		{
+			name:         "secret in plain inputs",
+			inputs:       map[string]string{"environment": "dev", "token": "hunter2"},
+			secretInputs: map[string]string{},
+			wantErr:      []string{`input "token" is a secret and must be set in secret_inputs`},
+		},
+		{
+			name:         "secret values are not echoed",
+			secretInputs: map[string]string{"environment": "staging"},
+			wantErr:      []string{`input "environment" must be one of dev, prod;`},
+		},
+		{
+			name:         "undeclared and duplicated inputs",
+			inputs:       map[string]string{"environment": "dev", "region": "eu"},
+			secretInputs: map[string]string{"environment": "dev"},
+			wantErr: []string{
+				`input "environment" is set in both inputs and secret_inputs`,
+				`input "region" is not declared by the blueprint`,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateBlueprintInputs(declared, tc.inputs, tc.secretInputs)
+
+			if len(tc.wantErr) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("expected an error, got none")
+			}
+
+			message := err.Error() + ";"
+			for _, want := range tc.wantErr {
+				if !strings.Contains(message, want) {
+					t.Errorf("error %q does not contain %q", err, want)
+				}
+			}
+
+			if strings.Contains(message, "staging\"") && len(tc.secretInputs) > 0 {
+				t.Errorf("error %q echoes a secret value", err)
+			}
+		})
+	}
+}
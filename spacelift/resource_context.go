@@ -27,9 +27,11 @@ func resourceContext() *schema.Resource {
 		DeleteContext: resourceContextDelete,
 
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importBySpacePath(contextSpacePathImport, schema.ImportStatePassthroughWithIdentity("context_id")),
 		},
 
+		Identity: slugIdentity("context_id", "immutable ID (slug) of the context"),
+
 		Schema: map[string]*schema.Schema{
 			"after_apply": {
 				Type:        schema.TypeList,
@@ -215,6 +217,10 @@ func resourceContextRead(ctx context.Context, d *schema.ResourceData, meta any)
 		return nil
 	}
 
+	if err := setIdentity(d, map[string]string{"context_id": context.ID}); err != nil {
+		return diag.Errorf("could not set context identity: %v", err)
+	}
+
 	d.Set("name", context.Name)
 
 	if description := context.Description; description != nil {
@@ -30,6 +30,8 @@ func resourceContextAttachment() *schema.Resource {
 			StateContext: resourceContextAttachmentImport,
 		},
 
+		Identity: attachmentIdentity("context_id", "ID of the attached context"),
+
 		Schema: map[string]*schema.Schema{
 			"context_id": {
 				Type:             schema.TypeString,
@@ -94,6 +96,10 @@ func resourceContextAttachmentCreate(ctx context.Context, d *schema.ResourceData
 
 	d.SetId(path.Join(contextID, mutation.AttachContext.ID))
 
+	if err := setAttachmentIdentity(d, "context_id", contextID, mutation.AttachContext.StackID, mutation.AttachContext.IsModule); err != nil {
+		return diag.Errorf("could not set context attachment identity: %v", err)
+	}
+
 	return nil
 }
 
@@ -107,14 +113,20 @@ func resourceContextAttachmentRead(ctx context.Context, d *schema.ResourceData,
 		projectID = d.Get("module_id").(string)
 	}
 
-	if attachment, err := resourceContextAttachmentFetch(ctx, contextID, projectID, meta); err != nil {
+	attachment, err := resourceContextAttachmentFetch(ctx, contextID, projectID, meta)
+	if err != nil {
 		return diag.FromErr(err)
 	} else if attachment == nil {
 		d.SetId("")
-	} else {
-		d.Set("priority", attachment.Priority)
+		return nil
+	}
+
+	if err := setAttachmentIdentity(d, "context_id", contextID, projectID, attachment.IsModule); err != nil {
+		return diag.Errorf("could not set context attachment identity: %v", err)
 	}
 
+	d.Set("priority", attachment.Priority)
+
 	return nil
 }
 
@@ -140,14 +152,21 @@ func resourceContextAttachmentDelete(ctx context.Context, d *schema.ResourceData
 }
 
 func resourceContextAttachmentImport(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
-	input := d.Id()
+	var contextID, projectID string
 
-	parts := strings.Split(input, "/")
-	if len(parts) != 2 {
-		return nil, fmt.Errorf("expecting attachment ID as $contextId/$projectId")
-	}
+	if input := d.Id(); input != "" {
+		parts := strings.Split(input, "/")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("expecting attachment ID as $contextId/$projectId")
+		}
 
-	contextID, projectID := parts[0], parts[1]
+		contextID, projectID = parts[0], parts[1]
+	} else {
+		var err error
+		if contextID, projectID, err = attachmentFromIdentity(d, "context_id"); err != nil {
+			return nil, err
+		}
+	}
 
 	attachment, err := resourceContextAttachmentFetch(ctx, contextID, projectID, meta)
 	if err != nil {
@@ -165,6 +184,10 @@ func resourceContextAttachmentImport(ctx context.Context, d *schema.ResourceData
 	d.SetId(path.Join(contextID, attachment.ID))
 	d.Set("context_id", contextID)
 
+	if err := setAttachmentIdentity(d, "context_id", contextID, projectID, attachment.IsModule); err != nil {
+		return nil, err
+	}
+
 	return []*schema.ResourceData{d}, nil
 }
 
@@ -27,9 +27,11 @@ func resourceModule() *schema.Resource {
 		DeleteContext: resourceModuleDelete,
 
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: schema.ImportStatePassthroughWithIdentity("module_id"),
 		},
 
+		Identity: slugIdentity("module_id", "immutable ID (slug) of the module"),
+
 		CustomizeDiff: func(ctx context.Context, diff *schema.ResourceDiff, meta any) error {
 			return validateSpaceliftRepoVCS(diff)
 		},
@@ -398,6 +400,10 @@ func resourceModuleRead(ctx context.Context, d *schema.ResourceData, meta any) d
 		return nil
 	}
 
+	if err := setIdentity(d, map[string]string{"module_id": module.ID}); err != nil {
+		return diag.Errorf("could not set module identity: %v", err)
+	}
+
 	d.Set("aws_assume_role_policy_statement", module.Integrations.AWS.AssumeRolePolicyStatement)
 	d.Set("administrative", module.Administrative)
 	d.Set("branch", module.Branch)
@@ -54,9 +54,11 @@ func resourcePolicy() *schema.Resource {
 		DeleteContext: resourcePolicyDelete,
 
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importBySpacePath(policySpacePathImport, schema.ImportStatePassthroughWithIdentity("policy_id")),
 		},
 
+		Identity: slugIdentity("policy_id", "immutable ID (slug) of the policy"),
+
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:             schema.TypeString,
@@ -181,6 +183,10 @@ func resourcePolicyRead(ctx context.Context, d *schema.ResourceData, meta any) d
 		return nil
 	}
 
+	if err := setIdentity(d, map[string]string{"policy_id": policy.ID}); err != nil {
+		return diag.Errorf("could not set policy identity: %v", err)
+	}
+
 	d.Set("name", policy.Name)
 	d.Set("body", policy.Body)
 	d.Set("type", policy.Type)
@@ -33,6 +33,8 @@ func resourcePolicyAttachment() *schema.Resource {
 			StateContext: resourcePolicyAttachmentImport,
 		},
 
+		Identity: attachmentIdentity("policy_id", "ID of the attached policy"),
+
 		Schema: map[string]*schema.Schema{
 			"policy_id": {
 				Type:             schema.TypeString,
@@ -88,6 +90,10 @@ func resourcePolicyAttachmentCreate(ctx context.Context, d *schema.ResourceData,
 
 	d.SetId(path.Join(policyID, mutation.AttachPolicy.ID))
 
+	if err := setAttachmentIdentity(d, "policy_id", policyID, mutation.AttachPolicy.StackID, mutation.AttachPolicy.IsModule); err != nil {
+		return diag.Errorf("could not set policy attachment identity: %v", err)
+	}
+
 	return nil
 }
 
@@ -102,10 +108,16 @@ func resourcePolicyAttachmentRead(ctx context.Context, d *schema.ResourceData, m
 		projectID = d.Get("module_id").(string)
 	}
 
-	if attachment, err := resourcePolicyAttachmentFetch(ctx, policyID, projectID, meta); err != nil {
+	attachment, err := resourcePolicyAttachmentFetch(ctx, policyID, projectID, meta)
+	if err != nil {
 		return diag.FromErr(err)
 	} else if attachment == nil {
 		d.SetId("")
+		return nil
+	}
+
+	if err := setAttachmentIdentity(d, "policy_id", policyID, projectID, attachment.IsModule); err != nil {
+		return diag.Errorf("could not set policy attachment identity: %v", err)
 	}
 
 	return nil
@@ -131,14 +143,21 @@ func resourcePolicyAttachmentDelete(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourcePolicyAttachmentImport(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
-	input := d.Id()
+	var policyID, projectID string
 
-	parts := strings.Split(input, "/")
-	if len(parts) != 2 {
-		return nil, fmt.Errorf("expecting attachment ID as $policyId/$projectId")
-	}
+	if input := d.Id(); input != "" {
+		parts := strings.Split(input, "/")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("expecting attachment ID as $policyId/$projectId")
+		}
 
-	policyID, projectID := parts[0], parts[1]
+		policyID, projectID = parts[0], parts[1]
+	} else {
+		var err error
+		if policyID, projectID, err = attachmentFromIdentity(d, "policy_id"); err != nil {
+			return nil, err
+		}
+	}
 
 	attachment, err := resourcePolicyAttachmentFetch(ctx, policyID, projectID, meta)
 	if err != nil {
@@ -156,6 +175,10 @@ func resourcePolicyAttachmentImport(ctx context.Context, d *schema.ResourceData,
 	d.SetId(path.Join(policyID, attachment.ID))
 	d.Set("policy_id", policyID)
 
+	if err := setAttachmentIdentity(d, "policy_id", policyID, projectID, attachment.IsModule); err != nil {
+		return nil, err
+	}
+
 	return []*schema.ResourceData{d}, nil
 }
 
@@ -20,6 +20,19 @@ const (
 	userRoleAttachmentPrefix            = "USER"
 )
 
+// roleAttachmentSubjects are the kinds of entities a role can be attached to,
+// with the prefix telling them apart in the resource ID.
+var roleAttachmentSubjects = []struct {
+	prefix      string
+	attribute   string
+	description string
+}{
+	{apiKeyRoleAttachmentPrefix, "api_key_id", "ID of the API key the role is attached to"},
+	{idpGroupMappingRoleAttachmentPrefix, "idp_group_mapping_id", "ID of the IdP Group Mapping the role is attached to"},
+	{stackRoleAttachmentPrefix, "stack_id", "Slug of the stack the role is attached to"},
+	{userRoleAttachmentPrefix, "user_id", "ID of the user the role is attached to"},
+}
+
 func resourceRoleAttachment() *schema.Resource {
 	return &schema.Resource{
 		Description: "" +
@@ -35,7 +48,29 @@ func resourceRoleAttachment() *schema.Resource {
 		DeleteContext: resourceRoleAttachmentDelete,
 
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceRoleAttachmentImport,
+		},
+
+		Identity: &schema.ResourceIdentity{
+			SchemaFunc: func() map[string]*schema.Schema {
+				identity := map[string]*schema.Schema{
+					"binding_id": {
+						Type:              schema.TypeString,
+						Description:       "ID of the role binding (ULID format) behind the attachment",
+						RequiredForImport: true,
+					},
+				}
+
+				for _, subject := range roleAttachmentSubjects {
+					identity[subject.attribute] = &schema.Schema{
+						Type:              schema.TypeString,
+						Description:       subject.description,
+						OptionalForImport: true,
+					}
+				}
+
+				return identity
+			},
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -204,19 +239,77 @@ func createStackRoleBinding(ctx context.Context, d *schema.ResourceData, meta an
 func resourceRoleAttachmentRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	id := d.Id()
 
-	if strings.HasPrefix(id, apiKeyRoleAttachmentPrefix) {
-		return readAPIKeyRoleBinding(ctx, d, meta)
+	var diags diag.Diagnostics
+
+	switch {
+	case strings.HasPrefix(id, apiKeyRoleAttachmentPrefix):
+		diags = readAPIKeyRoleBinding(ctx, d, meta)
+	case strings.HasPrefix(id, stackRoleAttachmentPrefix):
+		diags = readStackRoleBinding(ctx, d, meta)
+	case strings.HasPrefix(id, userRoleAttachmentPrefix):
+		diags = readUserRoleBinding(ctx, d, meta)
+	default:
+		diags = readIDPGroupMappingRoleBinding(ctx, d, meta)
 	}
 
-	if strings.HasPrefix(id, stackRoleAttachmentPrefix) {
-		return readStackRoleBinding(ctx, d, meta)
+	if diags.HasError() || d.Id() == "" {
+		return diags
 	}
 
-	if strings.HasPrefix(id, userRoleAttachmentPrefix) {
-		return readUserRoleBinding(ctx, d, meta)
+	if err := setRoleAttachmentIdentity(d); err != nil {
+		return diag.Errorf("could not set role attachment identity: %v", err)
+	}
+
+	return diags
+}
+
+func resourceRoleAttachmentImport(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
+	if d.Id() != "" {
+		return []*schema.ResourceData{d}, nil
+	}
+
+	identity, err := d.Identity()
+	if err != nil {
+		return nil, err
 	}
 
-	return readIDPGroupMappingRoleBinding(ctx, d, meta)
+	bindingID := identity.Get("binding_id").(string)
+	if bindingID == "" {
+		return nil, fmt.Errorf("identity is missing binding_id")
+	}
+
+	var prefixes []string
+	for _, subject := range roleAttachmentSubjects {
+		if identity.Get(subject.attribute).(string) != "" {
+			prefixes = append(prefixes, subject.prefix)
+		}
+	}
+
+	if len(prefixes) != 1 {
+		return nil, fmt.Errorf("identity must contain exactly one of api_key_id, idp_group_mapping_id, stack_id and user_id")
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", prefixes[0], bindingID))
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// setRoleAttachmentIdentity records the identity of the attachment, which names
+// the role binding and the entity it binds instead of the prefixed resource ID.
+func setRoleAttachmentIdentity(d *schema.ResourceData) error {
+	// Like readIDPGroupMappingRoleBinding, tolerate IDs without a prefix.
+	bindingID := d.Id()
+	if _, unprefixed, ok := strings.Cut(bindingID, "/"); ok {
+		bindingID = unprefixed
+	}
+
+	for _, subject := range roleAttachmentSubjects {
+		if subjectID := d.Get(subject.attribute).(string); subjectID != "" {
+			return setIdentity(d, map[string]string{"binding_id": bindingID, subject.attribute: subjectID})
+		}
+	}
+
+	return nil
 }
 
 func readAPIKeyRoleBinding(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
 	"github.com/shurcooL/graphql"
 
 	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
@@ -14,16 +15,19 @@ import (
 )
 
 func resourceRun() *schema.Resource {
-	return &schema.Resource{
+	runResource := &schema.Resource{
 		Description: "" +
 			"`spacelift_run` allows programmatically triggering runs in response " +
-			"to arbitrary changes in the keepers section.",
+			"to arbitrary changes in the keepers section. On Terraform 1.14 and later, " +
+			"consider the stateless `spacelift_trigger_run` action instead.",
 
 		CreateContext: resourceRunCreate,
 		ReadContext:   schema.NoopContext,
 		Delete:        schema.RemoveFromState,
 		UpdateContext: schema.NoopContext,
 
+		CustomizeDiff: resourceRunCustomizeDiff,
+
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(30 * time.Minute),
 		},
@@ -37,9 +41,10 @@ func resourceRun() *schema.Resource {
 				ValidateDiagFunc: validations.DisallowEmptyString,
 			},
 			"commit_sha": {
-				Description: "The commit SHA for which to trigger a run.",
+				Description: "The commit SHA for which to trigger a run. If not set, the run is triggered for the head of the stack's branch, and this is the commit that ran.",
 				Type:        schema.TypeString,
 				Optional:    true,
+				Computed:    true,
 				ForceNew:    true,
 			},
 			"keepers": {
@@ -62,36 +67,45 @@ func resourceRun() *schema.Resource {
 				Type:        schema.TypeString,
 				Computed:    true,
 			},
-			"wait": {
+			"wait":         runWaitSchema("Wait for the run to finish"),
+			"auto_confirm": autoConfirmSchema(),
+			"targets": {
 				Type:        schema.TypeList,
+				Description: "Addresses of the modules and resources to limit the run to, like Terraform's `-target` option. When replanning, the addresses to replan with.",
 				Optional:    true,
-				Description: "Wait for the run to finish",
-				MaxItems:    1,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"disabled": {
-							Type:        schema.TypeBool,
-							Description: "Whether waiting for a job is disabled or not. Default: `false`",
-							Optional:    true,
-							Default:     false,
-						},
-						"continue_on_state": {
-							Type: schema.TypeSet,
-							Elem: &schema.Schema{
-								Type: schema.TypeString,
-							},
-							Description: "Continue on the specified states of a finished run. If not specified, the default is `[ 'finished' ]`. You can use following states: `applying`, `canceled`, `confirmed`, `destroying`, `discarded`, `failed`, `finished`, `initializing`, `pending_review`, `performing`, `planning`, `preparing_apply`, `preparing_replan`, `preparing`, `queued`, `ready`, `replan_requested`, `skipped`, `stopped`, `unconfirmed`.",
-							Optional:    true,
-						},
-						"continue_on_timeout": {
-							Type:        schema.TypeBool,
-							Description: "Continue if run timed out, i.e. did not reach any defined end state in time. Default: `false`",
-							Optional:    true,
-							Default:     false,
-						},
-					},
+				ForceNew:    true,
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateDiagFunc: validations.TerraformTargetAddress,
+				},
+			},
+			"replace": {
+				Type:        schema.TypeList,
+				Description: "Addresses of the resources the run should replace, like Terraform's `-replace` option.",
+				Optional:    true,
+				ForceNew:    true,
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateDiagFunc: validations.TerraformResourceAddress,
 				},
 			},
+			"replan_run_id": {
+				Type:             schema.TypeString,
+				Description:      "ID of an unconfirmed run of the stack to replan with `targets` instead of triggering a new run. The resource then tracks the replanned run.",
+				Optional:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validations.DisallowEmptyString,
+				RequiredWith:     []string{"targets"},
+				ConflictsWith:    []string{"commit_sha", "proposed", "replace", "runtime_config"},
+			},
+			"promote_run_id": {
+				Type:             schema.TypeString,
+				Description:      "ID of a finished proposed run of the stack to promote to a tracked run instead of triggering a new run. The stack must allow run promotion, and its branch must still point at the commit of the proposed run. The resource then tracks the promoted run.",
+				Optional:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validations.DisallowEmptyString,
+				ConflictsWith:    []string{"commit_sha", "proposed", "replace", "replan_run_id", "runtime_config", "targets"},
+			},
 			"runtime_config": {
 				Type:        schema.TypeList,
 				Description: "Custom runtime configuration to apply to this run, overriding the stack's defaults.",
@@ -102,15 +116,45 @@ func resourceRun() *schema.Resource {
 			},
 		},
 	}
+
+	for name, attribute := range runOutcomeSchema() {
+		runResource.Schema[name] = attribute
+	}
+
+	return runResource
 }
 
 func resourceRunCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	var mutation struct {
-		CreateRun structs.Run `graphql:"runTrigger(stack: $stack, commitSha: $sha, runType: $runType, runtimeConfig: $runtimeConfig)"`
+	client := meta.(*internal.Client)
+	stackID := d.Get("stack_id").(string)
+
+	var runID string
+	var err error
+
+	if replanRunID, ok := d.GetOk("replan_run_id"); ok {
+		runID, err = replanRun(ctx, client, stackID, replanRunID.(string), runAddresses(d, "targets"))
+	} else if promoteRunID, ok := d.GetOk("promote_run_id"); ok {
+		runID, err = promoteRun(ctx, client, stackID, promoteRunID.(string))
+	} else {
+		runID, err = triggerRun(ctx, d, client, stackID)
 	}
 
-	stackID := d.Get("stack_id").(string)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// The run exists whatever happens next, so keep track of it even if
+	// waiting for it fails.
+	d.SetId(runID)
 
+	diags := waitForTriggeredRun(ctx, d, client, stackID, runID)
+
+	_, commitSHAConfigured := d.GetOk("commit_sha")
+
+	return append(diags, setRunOutcome(ctx, d, client, stackID, runID, !commitSHAConfigured)...)
+}
+
+func triggerRun(ctx context.Context, d *schema.ResourceData, client *internal.Client, stackID string) (string, error) {
 	runType := structs.RunTypeTracked
 	if d.Get("proposed").(bool) {
 		runType = structs.RunTypeProposed
@@ -131,18 +175,104 @@ func resourceRunCreate(ctx context.Context, d *schema.ResourceData, meta any) di
 		variables["runtimeConfig"] = runtimeConfig
 	}
 
-	client := meta.(*internal.Client)
-	if err := client.Mutate(ctx, "RunTrigger", &mutation, variables); err != nil {
-		return diag.Errorf("could not trigger run for stack %s: %v", stackID, internal.FromSpaceliftError(err))
+	targets, replace := runAddresses(d, "targets"), runAddresses(d, "replace")
+
+	var run structs.Run
+	var err error
+
+	if len(targets) == 0 && len(replace) == 0 {
+		var mutation struct {
+			CreateRun structs.Run `graphql:"runTrigger(stack: $stack, commitSha: $sha, runType: $runType, runtimeConfig: $runtimeConfig)"`
+		}
+		err = client.Mutate(ctx, "RunTrigger", &mutation, variables)
+		run = mutation.CreateRun
+	} else {
+		var mutation struct {
+			CreateRun structs.Run `graphql:"runTrigger(stack: $stack, commitSha: $sha, runType: $runType, runtimeConfig: $runtimeConfig, targets: $targets, replace: $replace)"`
+		}
+		variables["targets"] = optionalRunAddresses(targets)
+		variables["replace"] = optionalRunAddresses(replace)
+		err = client.Mutate(ctx, "RunTrigger", &mutation, variables)
+		run = mutation.CreateRun
+	}
+
+	if err != nil {
+		return "", errors.Errorf("could not trigger run for stack %s: %v", stackID, internal.FromSpaceliftError(err))
+	}
+
+	return run.ID, nil
+}
+
+// replanRun replans an unconfirmed run, limited to the given targets. The
+// run keeps its ID.
+func replanRun(ctx context.Context, client *internal.Client, stackID, runID string, targets []graphql.String) (string, error) {
+	var mutation struct {
+		ReplanRun structs.Run `graphql:"runTargetedReplan(stack: $stack, run: $run, targets: $targets)"`
+	}
+
+	variables := map[string]any{
+		"stack":   toID(stackID),
+		"run":     toID(runID),
+		"targets": targets,
+	}
+
+	if err := client.Mutate(ctx, "RunTargetedReplan", &mutation, variables); err != nil {
+		return "", errors.Errorf("could not replan run %s on stack %s: %v", runID, stackID, internal.FromSpaceliftError(err))
+	}
+
+	return mutation.ReplanRun.ID, nil
+}
+
+func runAddresses(d *schema.ResourceData, key string) []graphql.String {
+	var addresses []graphql.String
+	for _, address := range d.Get(key).([]any) {
+		addresses = append(addresses, graphql.String(address.(string)))
+	}
+	return addresses
+}
+
+func optionalRunAddresses(addresses []graphql.String) *[]graphql.String {
+	if len(addresses) == 0 {
+		return nil
+	}
+	return &addresses
+}
+
+func waitForTriggeredRun(ctx context.Context, d *schema.ResourceData, client *internal.Client, stackID, runID string) diag.Diagnostics {
+	deadline := time.Now().Add(d.Timeout(schema.TimeoutCreate))
+
+	if rules := newAutoConfirmRules(d); rules != nil {
+		// Wait for the plan first, which is where a run stops for confirmation.
+		planned := structs.NewWaitConfigurationFromValues(false, []string{"unconfirmed", "finished"}, false)
+		if diags := planned.Wait(ctx, client, stackID, runID, time.Until(deadline)); len(diags) > 0 {
+			return diags
+		}
+
+		if diags := autoConfirmRun(ctx, client, stackID, runID, rules); len(diags) > 0 {
+			return diags
+		}
 	}
 
 	if waitRaw, ok := d.GetOk("wait"); ok {
 		wait := structs.NewWaitConfiguration(waitRaw.([]any))
-		if diag := wait.Wait(ctx, d, client, stackID, mutation.CreateRun.ID); len(diag) > 0 {
-			return diag
-		}
+		return wait.Wait(ctx, client, stackID, runID, time.Until(deadline))
+	}
+
+	return nil
+}
+
+func resourceRunCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, meta any) error {
+	if autoConfirm, ok := diff.GetOk("auto_confirm"); !ok || len(autoConfirm.([]any)) == 0 {
+		return nil
+	}
+
+	if diff.Get("proposed").(bool) {
+		return errors.New("auto_confirm can only be used with tracked runs, proposed runs are never confirmed")
+	}
+
+	if diff.Get("wait.0.disabled").(bool) {
+		return errors.New("auto_confirm needs to wait for the run to plan, so wait can't be disabled")
 	}
 
-	d.SetId(mutation.CreateRun.ID)
 	return nil
 }
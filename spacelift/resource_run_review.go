@@ -0,0 +1,117 @@
+package spacelift
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/shurcooL/graphql"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/validations"
+)
+
+func resourceRunReview() *schema.Resource {
+	runReviewResource := &schema.Resource{
+		Description: "" +
+			"`spacelift_run_review` approves or rejects a run, like a reviewer " +
+			"would in the Spacelift UI. Approval policies take the review into " +
+			"account when deciding whether the run can go ahead. Removing the " +
+			"resource does not withdraw the review.",
+
+		CreateContext: resourceRunReviewCreate,
+		ReadContext:   schema.NoopContext,
+		Delete:        schema.RemoveFromState,
+		UpdateContext: schema.NoopContext,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"stack_id": {
+				Type:             schema.TypeString,
+				Description:      "ID of the stack the run belongs to",
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validations.DisallowEmptyString,
+			},
+			"run_id": {
+				Type:             schema.TypeString,
+				Description:      "ID of the run to review",
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validations.DisallowEmptyString,
+			},
+			"decision": {
+				Type:        schema.TypeString,
+				Description: "Decision of the review, either `APPROVE` or `REJECT`",
+				Required:    true,
+				ForceNew:    true,
+				ValidateFunc: validation.StringInSlice([]string{
+					string(structs.RunReviewDecisionApprove),
+					string(structs.RunReviewDecisionReject),
+				}, false),
+			},
+			"note": {
+				Type:        schema.TypeString,
+				Description: "Note explaining the decision, e.g. a reference to an approved change ticket",
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"keepers": {
+				Description: "" +
+					"Arbitrary map of values that, when changed, will trigger " +
+					"recreation of the resource.",
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+			},
+			"wait": runWaitSchema("Wait for the run to finish once reviewed, which includes approval policies being evaluated"),
+		},
+	}
+
+	for name, attribute := range runOutcomeSchema() {
+		runReviewResource.Schema[name] = attribute
+	}
+
+	return runReviewResource
+}
+
+func resourceRunReviewCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	var mutation struct {
+		RunReview structs.RunReview `graphql:"runReview(stack: $stack, run: $run, decision: $decision, note: $note)"`
+	}
+
+	stackID := d.Get("stack_id").(string)
+	runID := d.Get("run_id").(string)
+
+	variables := map[string]any{
+		"stack":    toID(stackID),
+		"run":      toID(runID),
+		"decision": structs.RunReviewDecision(d.Get("decision").(string)),
+		"note":     (*graphql.String)(nil),
+	}
+
+	if note, ok := d.GetOk("note"); ok {
+		variables["note"] = toOptionalString(note)
+	}
+
+	client := meta.(*internal.Client)
+	if err := client.Mutate(ctx, "RunReview", &mutation, variables); err != nil {
+		return diag.Errorf("could not review run %s on stack %s: %v", runID, stackID, internal.FromSpaceliftError(err))
+	}
+
+	d.SetId(mutation.RunReview.ID)
+
+	var diags diag.Diagnostics
+	if waitRaw, ok := d.GetOk("wait"); ok {
+		wait := structs.NewWaitConfiguration(waitRaw.([]any))
+		diags = wait.Wait(ctx, client, stackID, runID, d.Timeout(schema.TimeoutCreate))
+	}
+
+	return append(diags, setRunOutcome(ctx, d, client, stackID, runID, false)...)
+}
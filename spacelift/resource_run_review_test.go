@@ -0,0 +1,88 @@
+package spacelift
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	. "github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/testhelpers"
+)
+
+func TestRunReviewResource(t *testing.T) {
+	const resourceName = "spacelift_run_review.test"
+
+	randomID := acctest.RandStringFromCharSet(5, acctest.CharSetAlphaNum)
+
+	testSteps(t, []resource.TestStep{{
+		Config: fmt.Sprintf(`
+			resource "spacelift_worker_pool" "test" {
+				name = "Let's create a dummy worker pool to avoid running the job %s"
+			}
+
+			resource "spacelift_stack" "test" {
+				name           = "Test stack %s"
+				branch         = "master"
+				repository     = "demo"
+				worker_pool_id = spacelift_worker_pool.test.id
+			}
+
+			resource "spacelift_run" "test" {
+				stack_id = spacelift_stack.test.id
+			}
+
+			resource "spacelift_run_review" "test" {
+				stack_id = spacelift_run.test.stack_id
+				run_id   = spacelift_run.test.id
+				decision = "APPROVE"
+				note     = "Change ticket CHG-%s approved"
+			}
+		`, randomID, randomID, randomID),
+		Check: Resource(
+			resourceName,
+			Attribute("id", IsNotEmpty()),
+			Attribute("decision", Equals("APPROVE")),
+			Attribute("state", Equals("queued")),
+			Attribute("url", Contains("/run/")),
+		),
+	}})
+}
+
+func TestRunReviewCreate(t *testing.T) {
+	server := NewGraphQLServer(t, map[string]GraphQLHandler{
+		"RunReview": func(r GraphQLRequest) any {
+			for key, want := range map[string]string{"stack": "stack-id", "run": "run-id", "decision": "REJECT", "note": "CHG-1 was withdrawn"} {
+				if got := r.Variable(key); got != want {
+					t.Errorf("variable %s = %v, want %v", key, got, want)
+				}
+			}
+
+			return map[string]any{"runReview": map[string]any{"id": "review-id"}}
+		},
+		"StackRunOutcomeRead": func(GraphQLRequest) any {
+			return map[string]any{"stack": map[string]any{"run": map[string]any{"id": "run-id", "state": "PENDING_REVIEW"}}}
+		},
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceRunReview().Schema, map[string]any{
+		"stack_id": "stack-id",
+		"run_id":   "run-id",
+		"decision": "REJECT",
+		"note":     "CHG-1 was withdrawn",
+	})
+
+	if diags := resourceRunReviewCreate(context.Background(), d, server.Client()); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	if d.Id() != "review-id" {
+		t.Errorf("got ID %q, want review-id", d.Id())
+	}
+
+	if got := d.Get("state"); got != "pending_review" {
+		t.Errorf("got state %v, want pending_review", got)
+	}
+}
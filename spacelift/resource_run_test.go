@@ -1,12 +1,17 @@
 package spacelift
 
 import (
+	"context"
 	"fmt"
+	"maps"
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
 	. "github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/testhelpers"
 )
@@ -91,6 +96,9 @@ func TestRunResourceWait(t *testing.T) {
 					resourceName,
 					Attribute("id", IsNotEmpty()),
 					Attribute("stack_id", Contains(randomID)),
+					Attribute("state", Equals("queued")),
+					Attribute("url", Contains("/run/")),
+					Attribute("created_at", IsNotEmpty()),
 				),
 			},
 		})
@@ -207,3 +215,198 @@ func TestRunResourceWait(t *testing.T) {
 		})
 	})
 }
+
+func TestRunResourceAutoConfirm(t *testing.T) {
+	randomID := acctest.RandStringFromCharSet(5, acctest.CharSetAlphaNum)
+
+	config := func(proposed bool) string {
+		return fmt.Sprintf(`
+			resource "spacelift_stack" "test" {
+				name       = "Test stack %s"
+				repository = "demo"
+				branch     = "master"
+			}
+
+			resource "spacelift_run" "test" {
+				stack_id = spacelift_stack.test.id
+				proposed = %t
+
+				auto_confirm {
+					max_changes = 5
+				}
+
+				wait {
+					continue_on_state = ["finished"]
+				}
+			}
+		`, randomID, proposed)
+	}
+
+	testSteps(t, []resource.TestStep{
+		{
+			Config:      config(true),
+			ExpectError: regexp.MustCompile("auto_confirm can only be used with tracked runs"),
+		},
+		{
+			Config: config(false),
+			Check: Resource(
+				"spacelift_run.test",
+				Attribute("id", IsNotEmpty()),
+			),
+		},
+	})
+}
+
+func TestRunResourceAddressValidation(t *testing.T) {
+	for _, tc := range []struct {
+		config  map[string]any
+		wantErr string
+	}{
+		{config: map[string]any{"targets": []any{"aws_s3_bucket.logs", `module.network["eu"].aws_subnet.private[0]`, "module.network", "data.aws_ami.ubuntu"}}},
+		{config: map[string]any{"replace": []any{"random_pet.name", `module.app.aws_instance.web["a.b"]`}}},
+		{config: map[string]any{"replan_run_id": "run-id", "targets": []any{"aws_s3_bucket.logs"}}},
+		{config: map[string]any{"targets": []any{"aws_s3_bucket"}}, wantErr: `"aws_s3_bucket" is not a valid module or resource address`},
+		{config: map[string]any{"targets": []any{"-target=aws_s3_bucket.logs"}}, wantErr: "is not a valid module or resource address"},
+		{config: map[string]any{"targets": []any{"aws_s3_bucket.logs[0"}}, wantErr: "is not a valid module or resource address"},
+		{config: map[string]any{"replace": []any{"module.network"}}, wantErr: `"module.network" is not a valid managed resource address`},
+		{config: map[string]any{"replace": []any{"data.aws_ami.ubuntu"}}, wantErr: "is not a valid managed resource address"},
+		{config: map[string]any{"replan_run_id": "run-id"}, wantErr: `"replan_run_id": all of`},
+		{config: map[string]any{"replan_run_id": "run-id", "targets": []any{"aws_s3_bucket.logs"}, "replace": []any{"random_pet.name"}}, wantErr: `"replan_run_id": conflicts with replace`},
+		{config: map[string]any{"promote_run_id": "run-id"}},
+		{config: map[string]any{"promote_run_id": "run-id", "targets": []any{"aws_s3_bucket.logs"}}, wantErr: `"promote_run_id": conflicts with targets`},
+	} {
+		config := map[string]any{"stack_id": "stack-id"}
+		maps.Copy(config, tc.config)
+
+		diags := resourceRun().Validate(terraform.NewResourceConfigRaw(config))
+
+		var messages []string
+		for _, d := range diags {
+			messages = append(messages, d.Summary+": "+d.Detail)
+		}
+
+		switch {
+		case tc.wantErr == "" && diags.HasError():
+			t.Errorf("%v: unexpected errors %v", tc.config, messages)
+		case tc.wantErr != "" && !strings.Contains(strings.Join(messages, "\n"), tc.wantErr):
+			t.Errorf("%v: expected error %q, got %v", tc.config, tc.wantErr, messages)
+		}
+	}
+}
+
+func TestRunResourceCreateTargeted(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		config        map[string]any
+		wantMutation  string
+		wantQuery     string
+		wantVariables map[string]any
+	}{
+		{
+			name:          "untargeted run",
+			config:        map[string]any{},
+			wantMutation:  "RunTrigger",
+			wantQuery:     "runTrigger(stack: $stack, commitSha: $sha, runType: $runType, runtimeConfig: $runtimeConfig)",
+			wantVariables: map[string]any{"stack": "stack-id"},
+		},
+		{
+			name:          "targeted run",
+			config:        map[string]any{"targets": []any{"module.network"}, "replace": []any{"random_pet.name"}},
+			wantMutation:  "RunTrigger",
+			wantQuery:     "targets: $targets, replace: $replace",
+			wantVariables: map[string]any{"targets": []any{"module.network"}, "replace": []any{"random_pet.name"}},
+		},
+		{
+			name:          "targets only",
+			config:        map[string]any{"targets": []any{"module.network"}},
+			wantMutation:  "RunTrigger",
+			wantQuery:     "targets: $targets, replace: $replace",
+			wantVariables: map[string]any{"targets": []any{"module.network"}, "replace": nil},
+		},
+		{
+			name:          "replan",
+			config:        map[string]any{"replan_run_id": "old-run", "targets": []any{"aws_s3_bucket.logs"}},
+			wantMutation:  "RunTargetedReplan",
+			wantQuery:     "runTargetedReplan(stack: $stack, run: $run, targets: $targets)",
+			wantVariables: map[string]any{"run": "old-run", "targets": []any{"aws_s3_bucket.logs"}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mutation := func(r GraphQLRequest) any {
+				if !strings.Contains(r.Query, tc.wantQuery) {
+					t.Errorf("mutation %s does not contain %s", r.Query, tc.wantQuery)
+				}
+
+				var variables map[string]any
+				if err := r.DecodeVariables(&variables); err != nil {
+					return err
+				}
+
+				for key, want := range tc.wantVariables {
+					if got := variables[key]; fmt.Sprint(got) != fmt.Sprint(want) {
+						t.Errorf("variable %s = %v, want %v", key, got, want)
+					}
+				}
+
+				field := strings.ToLower(r.Operation[:1]) + r.Operation[1:]
+				return map[string]any{field: map[string]any{"id": "run-id"}}
+			}
+
+			server := NewGraphQLServer(t, map[string]GraphQLHandler{
+				tc.wantMutation: mutation,
+				"StackRunOutcomeRead": func(GraphQLRequest) any {
+					return map[string]any{"stack": map[string]any{"run": map[string]any{"id": "run-id", "state": "UNCONFIRMED", "commit": map[string]any{"hash": "abc123"}}}}
+				},
+			})
+
+			config := map[string]any{"stack_id": "stack-id"}
+			maps.Copy(config, tc.config)
+
+			d := schema.TestResourceDataRaw(t, resourceRun().Schema, config)
+
+			diags := resourceRunCreate(context.Background(), d, server.Client())
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+
+			if d.Id() != "run-id" {
+				t.Errorf("got ID %q, want run-id", d.Id())
+			}
+		})
+	}
+}
+
+func TestRunResourceCreateCommitSHA(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		config map[string]any
+		want   string
+	}{
+		{name: "head of the branch", config: map[string]any{}, want: "abc123def456"},
+		{name: "configured commit", config: map[string]any{"commit_sha": "abc123"}, want: "abc123"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := NewGraphQLServer(t, map[string]GraphQLHandler{
+				"RunTrigger": func(GraphQLRequest) any {
+					return map[string]any{"runTrigger": map[string]any{"id": "run-id"}}
+				},
+				"StackRunOutcomeRead": func(GraphQLRequest) any {
+					return map[string]any{"stack": map[string]any{"run": map[string]any{"id": "run-id", "state": "UNCONFIRMED", "commit": map[string]any{"hash": "abc123def456"}}}}
+				},
+			})
+
+			config := map[string]any{"stack_id": "stack-id"}
+			maps.Copy(config, tc.config)
+
+			d := schema.TestResourceDataRaw(t, resourceRun().Schema, config)
+
+			if diags := resourceRunCreate(context.Background(), d, server.Client()); diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+
+			if got := d.Get("commit_sha"); got != tc.want {
+				t.Errorf("got commit_sha %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -27,9 +27,11 @@ func resourceSpace() *schema.Resource {
 		DeleteContext: resourceSpaceDelete,
 
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: schema.ImportStatePassthroughWithIdentity("space_id"),
 		},
 
+		Identity: slugIdentity("space_id", "immutable ID (slug) of the space"),
+
 		Schema: map[string]*schema.Schema{
 			"parent_space_id": {
 				Type:        schema.TypeString,
@@ -126,6 +128,11 @@ func resourceSpaceRead(ctx context.Context, d *schema.ResourceData, meta any) di
 	}
 
 	d.SetId(space.ID)
+
+	if err := setIdentity(d, map[string]string{"space_id": space.ID}); err != nil {
+		return diag.Errorf("could not set space identity: %v", err)
+	}
+
 	d.Set("name", space.Name)
 	d.Set("description", space.Description)
 	d.Set("inherit_entities", space.InheritEntities)
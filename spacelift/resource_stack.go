@@ -3,8 +3,7 @@ package spacelift
 import (
 	"context"
 	"fmt"
-	"net/http"
-	"os"
+	"slices"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -41,54 +40,21 @@ func resourceStack() *schema.Resource {
 		DeleteContext: resourceStackDelete,
 
 		Importer: &schema.ResourceImporter{
-			StateContext: resourceStackImport,
+			StateContext: importBySpacePath(stackSpacePathImport, resourceStackImport),
 		},
 
+		Identity: slugIdentity("stack_id", "immutable ID (slug) of the stack"),
+
 		CustomizeDiff: func(ctx context.Context, diff *schema.ResourceDiff, meta any) error {
 			if err := validateSpaceliftRepoVCS(diff); err != nil {
 				return err
 			}
 
-			// Skip on initial resource creation — there is no old state.
-			if diff.Id() == "" {
-				return nil
-			}
-
-			oldUSM, newUSM := diff.GetChange("terragrunt.0.use_state_management")
-			oldTGCount, newTGCount := diff.GetChange("terragrunt.#")
-			isMigration := oldTGCount.(int) != newTGCount.(int)
-
-			if isMigration {
-				// During vendor migration (adding/removing the terragrunt block),
-				// the API does not support changing state management.
-				// Block the plan if the effective value would change.
-				oldMS, newMS := diff.GetChange("manage_state")
-				var wasStateManaged, willBeStateManaged bool
-				if oldTGCount.(int) == 0 {
-					wasStateManaged = oldMS.(bool)
-					willBeStateManaged = newUSM.(bool)
-				} else {
-					wasStateManaged = oldUSM.(bool)
-					willBeStateManaged = newMS.(bool)
-				}
-				if wasStateManaged != willBeStateManaged {
-					return fmt.Errorf(
-						"cannot change state management during vendor migration "+
-							"(effective value would change from %t to %t); "+
-							"destroy the stack first with `terraform destroy`, then recreate it with the new configuration",
-						wasStateManaged, willBeStateManaged,
-					)
-				}
-				return nil
-			}
-
-			// Within an existing terragrunt block, any change to
-			// use_state_management requires replacement.
-			if oldUSM.(bool) != newUSM.(bool) {
-				diff.ForceNew("terragrunt.0.use_state_management")
+			if err := customizeStackAutoattachments(ctx, diff, meta); err != nil {
+				return err
 			}
 
-			return nil
+			return customizeStackVendorMigration(diff)
 		},
 
 		SchemaVersion: 1,
@@ -184,6 +150,12 @@ func resourceStack() *schema.Resource {
 				},
 				Optional: true,
 			},
+			"autoattachments": {
+				Type:        schema.TypeList,
+				Description: "Contexts, policies and cloud integrations which attach themselves to the stack through `autoattach:` labels, sorted by type and ID. Computed at plan time whenever `labels` or `space_id` change, so that the effect of a label change shows up in the plan. They are not refreshed, so entities which start or stop attaching themselves to an unchanged stack don't show up; use the `spacelift_autoattachments` data source to look them up.",
+				Computed:    true,
+				Elem:        autoattachmentSchema(),
+			},
 			"autodeploy": {
 				Type:        schema.TypeBool,
 				Description: "Indicates whether changes to this stack can be automatically deployed. Defaults to `false`.",
@@ -501,9 +473,14 @@ func resourceStack() *schema.Resource {
 				DiffSuppressFunc: ignoreOnceCreated,
 				Sensitive:        true,
 			},
+			"import_state_checksum": {
+				Type:        schema.TypeString,
+				Description: "SHA-256 checksum of the state uploaded from `import_state` or `import_state_file` when the stack was created",
+				Computed:    true,
+			},
 			"import_state_file": {
 				Type:             schema.TypeString,
-				Description:      "Path to the state file to upload when creating a new stack",
+				Description:      "Path to the state file to upload when creating a new stack. The file is streamed rather than loaded into memory, verified against its checksum and retried if the upload is interrupted.",
 				ConflictsWith:    []string{"import_state"},
 				Optional:         true,
 				DiffSuppressFunc: ignoreOnceCreated,
@@ -637,6 +614,18 @@ func resourceStack() *schema.Resource {
 				Optional:    true,
 				Default:     false,
 			},
+			"guard_managed_resources": {
+				Type:        schema.TypeBool,
+				Description: "Refuse to delete this stack while it still manages resources, so that they are not orphaned. A `spacelift_stack_destructor` which is not deactivated destroys the resources before the stack is deleted, so it is not affected. Defaults to `false`.",
+				Optional:    true,
+				Default:     false,
+			},
+			"abandon_managed_resources": {
+				Type:        schema.TypeBool,
+				Description: "Delete this stack even if `guard_managed_resources` is set and it still manages resources, leaving them in place. Like all settings checked on deletion, it must be applied before the stack is deleted. Defaults to `false`.",
+				Optional:    true,
+				Default:     false,
+			},
 			"pulumi": {
 				Type:          schema.TypeList,
 				ConflictsWith: []string{"ansible", "cloudformation", "kubernetes", "opentofu", "terraform_version", "terraform_workflow_tool", "terraform_workspace", "terragrunt"},
@@ -861,15 +850,20 @@ func resourceStackCreate(ctx context.Context, d *schema.ResourceData, meta any)
 		variables["slug"] = toOptionalString(slug)
 	}
 
-	var stateContent string
+	client := meta.(*internal.Client)
+
+	var objectID, checksum string
 
 	content, ok := d.GetOk("import_state")
 	if ok && !isStateManaged {
 		return diag.Errorf(`"import_state" requires "manage_state" or "terragrunt.use_state_management" to be true`)
 	} else if ok {
-		stateContent = content.(string)
-		// After we've saved the imported state to memory, set the value to an empty string so it doesn't get saved to this state file.
-		// We purposefully ignore this value after creation, so we have no reason to save it.
+		var err error
+		if objectID, checksum, err = uploadStateContent(ctx, client, content.(string)); err != nil {
+			return diag.FromErr(err)
+		}
+		// We purposefully ignore this value after creation, so we have no reason to save it,
+		// keeping only the checksum of what was uploaded.
 		d.Set("import_state", "")
 	}
 
@@ -877,19 +871,15 @@ func resourceStackCreate(ctx context.Context, d *schema.ResourceData, meta any)
 	if ok && !isStateManaged {
 		return diag.Errorf(`"import_state_file" requires "manage_state" or "terragrunt.use_state_management" to be true`)
 	} else if ok {
-		data, err := os.ReadFile(path.(string))
-		if err != nil {
-			return diag.Errorf("failed to read imported state file: %s", err)
+		var err error
+		if objectID, checksum, err = uploadStateFile(ctx, client, path.(string)); err != nil {
+			return diag.Errorf("failed to upload imported state file: %s", err)
 		}
-		stateContent = string(data)
 	}
 
-	if stateContent != "" {
-		objectID, err := uploadStateFile(ctx, stateContent, meta)
-		if err != nil {
-			return diag.FromErr(err)
-		}
+	if objectID != "" {
 		variables["stackObjectID"] = toOptionalString(objectID)
+		d.Set("import_state_checksum", checksum)
 	}
 
 	if v, ok := d.GetOk("terraform_external_state_access"); ok {
@@ -898,13 +888,18 @@ func resourceStackCreate(ctx context.Context, d *schema.ResourceData, meta any)
 		}
 	}
 
-	if err := meta.(*internal.Client).Mutate(ctx, "StackCreate", &mutation, variables); err != nil {
+	if err := client.Mutate(ctx, "StackCreate", &mutation, variables); err != nil {
 		return diag.Errorf("could not create stack: %v", internal.FromSpaceliftError(err))
 	}
 
 	d.SetId(mutation.CreateStack.ID)
 
-	return resourceStackRead(ctx, d, meta)
+	diags := resourceStackRead(ctx, d, meta)
+	if diags.HasError() {
+		return diags
+	}
+
+	return append(diags, resolveStackAutoattachments(ctx, d, meta)...)
 }
 
 func getStackByID(ctx context.Context, client *internal.Client, stackID string) (*structs.Stack, error) {
@@ -932,6 +927,10 @@ func resourceStackRead(ctx context.Context, d *schema.ResourceData, meta any) di
 		return nil
 	}
 
+	if err := setIdentity(d, map[string]string{"stack_id": stack.ID}); err != nil {
+		return diag.Errorf("could not set stack identity: %v", err)
+	}
+
 	return structs.PopulateStack(d, stack)
 }
 
@@ -968,7 +967,11 @@ func resourceStackUpdate(ctx context.Context, d *schema.ResourceData, meta any)
 		ret = diag.Errorf("could not update stack: %v", internal.FromSpaceliftError(err))
 	}
 
-	return append(ret, resourceStackRead(ctx, d, meta)...)
+	if ret = append(ret, resourceStackRead(ctx, d, meta)...); ret.HasError() {
+		return ret
+	}
+
+	return append(ret, resolveStackAutoattachments(ctx, d, meta)...)
 }
 
 func resourceStackDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
@@ -979,6 +982,17 @@ func resourceStackDelete(ctx context.Context, d *schema.ResourceData, meta any)
 	id := d.Id()
 	variables := map[string]any{"id": toID(id)}
 
+	// Resources which use spacelift_stack's delete, like stack set members,
+	// may not have the guard settings at all.
+	guard, _ := d.Get("guard_managed_resources").(bool)
+	abandon, _ := d.Get("abandon_managed_resources").(bool)
+
+	if guard && !abandon {
+		if err := checkStackManagedResources(ctx, meta.(*internal.Client), id); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	if err := meta.(*internal.Client).Mutate(ctx, "StackDelete", &mutation, variables); err != nil {
 		return diag.Errorf("could not delete stack: %v", internal.FromSpaceliftError(err))
 	}
@@ -998,6 +1012,69 @@ func resourceStackDelete(ctx context.Context, d *schema.ResourceData, meta any)
 	return nil
 }
 
+// stackManagedResourcesListed is the number of resources listed by name when
+// the deletion of a stack is refused because it still manages resources.
+const stackManagedResourcesListed = 20
+
+// checkStackManagedResources fails if the stack still manages resources which
+// deleting it would orphan.
+func checkStackManagedResources(ctx context.Context, client *internal.Client, stackID string) error {
+	var query struct {
+		Stack *struct {
+			Entities []struct {
+				Address string `graphql:"address"`
+				Type    string `graphql:"type"`
+			} `graphql:"entities"`
+		} `graphql:"stack(id: $id)"`
+	}
+
+	variables := map[string]any{"id": graphql.ID(stackID)}
+
+	if err := client.Query(ctx, "StackManagedResources", &query, variables); err != nil {
+		return errors.Wrapf(err, "could not query for resources managed by stack %s", stackID)
+	}
+
+	// A stack which is gone, for example because a destructor deleted it,
+	// does not manage anything anymore.
+	if query.Stack == nil {
+		return nil
+	}
+
+	var addresses []string
+	for _, entity := range query.Stack.Entities {
+		if isManagedEntity(entity.Address, entity.Type) {
+			addresses = append(addresses, entity.Address)
+		}
+	}
+
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	slices.Sort(addresses)
+
+	listed := strings.Join(addresses[:min(len(addresses), stackManagedResourcesListed)], ", ")
+	if len(addresses) > stackManagedResourcesListed {
+		listed += fmt.Sprintf(" and %d more", len(addresses)-stackManagedResourcesListed)
+	}
+
+	return errors.Errorf(
+		"stack %s still manages %d resource(s), which would be orphaned by deleting it: %s; destroy them with a spacelift_stack_destructor, or set abandon_managed_resources to true and apply before deleting the stack to leave them in place",
+		stackID, len(addresses), listed,
+	)
+}
+
+// isManagedEntity tells whether an entity of a stack is a resource the stack
+// manages, as opposed to e.g. an output or a data source it merely reads.
+func isManagedEntity(address, entityType string) bool {
+	switch entityType {
+	case "output", "module", "stack":
+		return false
+	}
+
+	return !strings.HasPrefix(address, "data.") && !strings.Contains(address, ".data.")
+}
+
 func stackInput(d *schema.ResourceData) structs.StackInput {
 	// Prefer new parameter, fallback to deprecated if not set
 	allowRunPromotion := d.Get("allow_run_promotion").(bool)
@@ -1358,6 +1435,52 @@ func shouldWeReComputeTerraformVersionForTerraformWorkflowTool(d *schema.Resourc
 	return false
 }
 
+// customizeStackVendorMigration makes sure that adding or removing the
+// terragrunt block leaves state management as it is, and replaces the stack
+// if use_state_management changes within the terragrunt block.
+func customizeStackVendorMigration(diff *schema.ResourceDiff) error {
+	// Skip on initial resource creation — there is no old state.
+	if diff.Id() == "" {
+		return nil
+	}
+
+	oldUSM, newUSM := diff.GetChange("terragrunt.0.use_state_management")
+	oldTGCount, newTGCount := diff.GetChange("terragrunt.#")
+	isMigration := oldTGCount.(int) != newTGCount.(int)
+
+	if isMigration {
+		// During vendor migration (adding/removing the terragrunt block),
+		// the API does not support changing state management.
+		// Block the plan if the effective value would change.
+		oldMS, newMS := diff.GetChange("manage_state")
+		var wasStateManaged, willBeStateManaged bool
+		if oldTGCount.(int) == 0 {
+			wasStateManaged = oldMS.(bool)
+			willBeStateManaged = newUSM.(bool)
+		} else {
+			wasStateManaged = oldUSM.(bool)
+			willBeStateManaged = newMS.(bool)
+		}
+		if wasStateManaged != willBeStateManaged {
+			return fmt.Errorf(
+				"cannot change state management during vendor migration "+
+					"(effective value would change from %t to %t); "+
+					"destroy the stack first with `terraform destroy`, then recreate it with the new configuration",
+				wasStateManaged, willBeStateManaged,
+			)
+		}
+		return nil
+	}
+
+	// Within an existing terragrunt block, any change to
+	// use_state_management requires replacement.
+	if oldUSM.(bool) != newUSM.(bool) {
+		diff.ForceNew("terragrunt.0.use_state_management")
+	}
+
+	return nil
+}
+
 // vendorMigrationDirection detects if the vendor type is changing between
 // terraform and terragrunt, and returns the target vendor. Returns empty
 // string if no migration is happening.
@@ -1413,42 +1536,20 @@ func getStrings(d *schema.ResourceData, fieldName string) []graphql.String {
 	return values
 }
 
-func uploadStateFile(ctx context.Context, content string, meta any) (string, error) {
-	var mutation struct {
-		StateUploadURL struct {
-			ObjectID string `graphql:"objectId"`
-			URL      string `graphql:"url"`
-		} `graphql:"stateUploadUrl"`
-	}
-
-	if err := meta.(*internal.Client).Mutate(ctx, "StateUploadUrl", &mutation, nil); err != nil {
-		return "", errors.Wrap(err, "could not generate state upload URL")
-	}
-
-	request, err := http.NewRequestWithContext(ctx, http.MethodPut, mutation.StateUploadURL.URL, strings.NewReader(content))
-	if err != nil {
-		return "", errors.Wrap(err, "could not create state upload request")
-	}
-	request.Header.Set("Content-Type", "application/json")
-
-	response, err := http.DefaultClient.Do(request)
-	if err != nil {
-		return "", errors.Wrap(err, "could not upload the state to remote URL")
-	}
-
-	if (response.StatusCode / 100) != 2 {
-		return "", errors.Errorf("unexpected HTTP status code when uploading the state: %d", response.StatusCode)
-	}
-
-	return mutation.StateUploadURL.ObjectID, nil
-}
-
 func ignoreOnceCreated(_, _, _ string, d *schema.ResourceData) bool {
 	return d.Id() != ""
 }
 
 func resourceStackImport(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
 	stackID := d.Id()
+	if stackID == "" {
+		// Importing by identity rather than by ID.
+		if identity, err := d.Identity(); err == nil {
+			stackID, _ = identity.Get("stack_id").(string)
+			d.SetId(stackID)
+		}
+	}
+
 	if stackID == "" {
 		return nil, errors.New("stack ID is required to import a stack")
 	}
@@ -1462,10 +1563,18 @@ func resourceStackImport(ctx context.Context, d *schema.ResourceData, meta any)
 		return nil, fmt.Errorf("stack with ID %q does not exist (or you may not have access to it)", stackID)
 	}
 
+	if err := setIdentity(d, map[string]string{"stack_id": stack.ID}); err != nil {
+		return nil, fmt.Errorf("could not set stack identity: %v", err)
+	}
+
 	if diags := structs.PopulateStack(d, stack); diags.HasError() {
 		return nil, fmt.Errorf("could not import stack into state: %s", diags[0].Summary)
 	}
 
+	// The deletion guard only lives in Terraform state.
+	d.Set("guard_managed_resources", false)
+	d.Set("abandon_managed_resources", false)
+
 	return []*schema.ResourceData{d}, nil
 }
 
@@ -0,0 +1,428 @@
+package spacelift
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	tfpath "github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/validations"
+)
+
+var (
+	_ resource.Resource                   = (*stackDependenciesResource)(nil)
+	_ resource.ResourceWithConfigure      = (*stackDependenciesResource)(nil)
+	_ resource.ResourceWithImportState    = (*stackDependenciesResource)(nil)
+	_ resource.ResourceWithModifyPlan     = (*stackDependenciesResource)(nil)
+	_ resource.ResourceWithValidateConfig = (*stackDependenciesResource)(nil)
+)
+
+// NewStackDependenciesResource returns the Plugin Framework implementation of
+// spacelift_stack_dependencies.
+func NewStackDependenciesResource() resource.Resource { return &stackDependenciesResource{} }
+
+type stackDependenciesResource struct {
+	client *internal.Client
+}
+
+type stackDependenciesModel struct {
+	ID                types.String `tfsdk:"id"`
+	StackID           types.String `tfsdk:"stack_id"`
+	DependsOnStackIDs types.Set    `tfsdk:"depends_on_stack_ids"`
+	References        types.Set    `tfsdk:"references"`
+}
+
+type stackDependenciesReferenceModel struct {
+	DependsOnStackID types.String `tfsdk:"depends_on_stack_id"`
+	OutputName       types.String `tfsdk:"output_name"`
+	InputName        types.String `tfsdk:"input_name"`
+	TriggerAlways    types.Bool   `tfsdk:"trigger_always"`
+}
+
+var stackDependenciesReferenceType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"depends_on_stack_id": types.StringType,
+	"output_name":         types.StringType,
+	"input_name":          types.StringType,
+	"trigger_always":      types.BoolType,
+}}
+
+func (r *stackDependenciesResource) Metadata(_ context.Context, _ resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = "spacelift_stack_dependencies"
+}
+
+func (r *stackDependenciesResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// ProviderData is nil during schema-validation walks.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*internal.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"unexpected provider data",
+			fmt.Sprintf("expected *internal.Client, got %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *stackDependenciesResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "" +
+			"`spacelift_stack_dependencies` manages the full set of **stack dependencies** of a stack, " +
+			"with their references. It is authoritative: dependencies and references of the stack which " +
+			"are not declared here, including those added in the UI, are removed, and show up in the plan " +
+			"as such. Destroying it removes all the dependencies of the stack. Don't use it together with " +
+			"`spacelift_stack_dependency` or `spacelift_stack_dependency_reference` for the same stack.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"stack_id": schema.StringAttribute{
+				Description: "immutable ID (slug) of stack which has the dependencies.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"depends_on_stack_ids": schema.SetAttribute{
+				Description: "IDs (slugs) of all the stacks to depend on. Dependencies on any other stacks are removed.",
+				Required:    true,
+				ElementType: types.StringType,
+			},
+			"references": schema.SetNestedAttribute{
+				Description: "All the references of the stack, each passing an output of a stack depended on to an input of this stack. Any other references are removed.",
+				Optional:    true,
+				Computed:    true,
+				Default:     setdefault.StaticValue(types.SetValueMust(stackDependenciesReferenceType, nil)),
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"depends_on_stack_id": schema.StringAttribute{
+							Description: "ID (slug) of the stack providing the output. It has to be in `depends_on_stack_ids`.",
+							Required:    true,
+						},
+						"output_name": schema.StringAttribute{
+							Description: "Name of the output of the stack depended on",
+							Required:    true,
+						},
+						"input_name": schema.StringAttribute{
+							Description: "Name of the input of this stack. It has to be a valid environment variable name, and a valid Terraform variable name has to follow the `TF_VAR_` prefix.",
+							Required:    true,
+						},
+						"trigger_always": schema.BoolAttribute{
+							Description: "Whether this stack should be triggered even if the value of the reference did not change. Defaults to `false`.",
+							Optional:    true,
+							Computed:    true,
+							Default:     booldefault.StaticBool(false),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// ValidateConfig checks that every reference is to a stack depended on, and
+// that no two references set the same input.
+func (r *stackDependenciesResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config stackDependenciesModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var references []stackDependenciesReferenceModel
+	if !config.References.IsNull() && !config.References.IsUnknown() {
+		resp.Diagnostics.Append(config.References.ElementsAs(ctx, &references, false)...)
+	}
+
+	upstreamIDs, known := knownStrings(config.DependsOnStackIDs)
+
+	inputNames := map[string]bool{}
+	for _, reference := range references {
+		if known && !reference.DependsOnStackID.IsUnknown() && !slices.Contains(upstreamIDs, reference.DependsOnStackID.ValueString()) {
+			resp.Diagnostics.AddAttributeError(
+				tfpath.Root("references"),
+				"reference to a stack not depended on",
+				fmt.Sprintf("Input %s references stack %s, which is not in depends_on_stack_ids.", reference.InputName.ValueString(), reference.DependsOnStackID.ValueString()),
+			)
+		}
+
+		if reference.InputName.IsUnknown() {
+			continue
+		}
+
+		inputName := reference.InputName.ValueString()
+		for _, d := range validations.EnvironmentVariableName(inputName, nil) {
+			resp.Diagnostics.AddAttributeError(tfpath.Root("references"), "invalid input name", d.Summary)
+		}
+
+		if inputNames[inputName] {
+			resp.Diagnostics.AddAttributeError(tfpath.Root("references"), "duplicate input name", fmt.Sprintf("Input %s is set by more than one reference.", inputName))
+		}
+		inputNames[inputName] = true
+	}
+}
+
+// ModifyPlan rejects dependencies which would close a cycle between stacks,
+// the same way spacelift_stack_dependency does.
+func (r *stackDependenciesResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// The client is nil during schema-validation walks.
+	if r.client == nil {
+		return
+	}
+
+	planned := plannedStackDependenciesFor(r.client)
+
+	var state *stackDependenciesModel
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	}
+
+	var plan *stackDependenciesModel
+	if !req.Plan.Raw.IsNull() {
+		resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var before []string
+	if state != nil {
+		before, _ = knownStrings(state.DependsOnStackIDs)
+	}
+
+	var after []string
+	if plan != nil {
+		var known bool
+		if after, known = knownStrings(plan.DependsOnStackIDs); !known || plan.StackID.IsUnknown() {
+			return
+		}
+	}
+
+	for _, upstreamID := range before {
+		if plan == nil || !state.StackID.Equal(plan.StackID) || !slices.Contains(after, upstreamID) {
+			planned.remove(stackDependencyEdge{stackID: state.StackID.ValueString(), dependsOnStackID: upstreamID})
+		}
+	}
+
+	for _, upstreamID := range after {
+		edge := stackDependencyEdge{stackID: plan.StackID.ValueString(), dependsOnStackID: upstreamID}
+
+		if state != nil && state.StackID.Equal(plan.StackID) && slices.Contains(before, upstreamID) {
+			planned.keep(edge)
+			continue
+		}
+
+		check, err := checkPlannedStackDependency(ctx, r.client, planned, edge)
+		if err != nil {
+			resp.Diagnostics.AddError("could not check stack dependencies for cycles", err.Error())
+			return
+		}
+
+		switch {
+		case check.certain:
+			resp.Diagnostics.AddAttributeError(tfpath.Root("depends_on_stack_ids"), "stack dependency would create a cycle", check.detail)
+		case check.detail != "":
+			resp.Diagnostics.AddAttributeWarning(tfpath.Root("depends_on_stack_ids"), "stack dependency may create a cycle", check.detail)
+		}
+	}
+}
+
+func (r *stackDependenciesResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan stackDependenciesModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *stackDependenciesResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state stackDependenciesModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	upstreams, err := getStackUpstreams(ctx, r.client, state.StackID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("could not query for stack dependencies", err.Error())
+		return
+	}
+
+	if upstreams == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(state.setUpstreams(ctx, upstreams)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *stackDependenciesResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan stackDependenciesModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *stackDependenciesResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state stackDependenciesModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := setStackUpstreams(ctx, r.client, state.StackID.ValueString(), map[string]stackUpstream{}); err != nil {
+		resp.Diagnostics.AddError("could not remove stack dependencies", err.Error())
+	}
+}
+
+// ImportState takes the ID of the stack, and manages all of its dependencies
+// from then on.
+func (r *stackDependenciesResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, tfpath.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, tfpath.Root("stack_id"), req.ID)...)
+}
+
+// apply makes the dependencies of the stack match the plan, and records the
+// outcome in it.
+func (r *stackDependenciesResource) apply(ctx context.Context, plan *stackDependenciesModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var upstreamIDs []string
+	diags.Append(plan.DependsOnStackIDs.ElementsAs(ctx, &upstreamIDs, false)...)
+
+	var references []stackDependenciesReferenceModel
+	diags.Append(plan.References.ElementsAs(ctx, &references, false)...)
+
+	if diags.HasError() {
+		return diags
+	}
+
+	desired := map[string]stackUpstream{}
+	for _, upstreamID := range upstreamIDs {
+		desired[upstreamID] = stackUpstream{references: map[string]stackUpstreamReference{}}
+	}
+
+	for _, reference := range references {
+		upstream, ok := desired[reference.DependsOnStackID.ValueString()]
+		if !ok {
+			diags.AddAttributeError(
+				tfpath.Root("references"),
+				"reference to a stack not depended on",
+				fmt.Sprintf("Input %s references stack %s, which is not in depends_on_stack_ids.", reference.InputName.ValueString(), reference.DependsOnStackID.ValueString()),
+			)
+			continue
+		}
+
+		upstream.references[reference.InputName.ValueString()] = stackUpstreamReference{
+			outputName:    reference.OutputName.ValueString(),
+			inputName:     reference.InputName.ValueString(),
+			triggerAlways: reference.TriggerAlways.ValueBool(),
+		}
+	}
+
+	if diags.HasError() {
+		return diags
+	}
+
+	stackID := plan.StackID.ValueString()
+
+	if err := setStackUpstreams(ctx, r.client, stackID, desired); err != nil {
+		diags.AddError("could not set stack dependencies", err.Error())
+		return diags
+	}
+
+	plan.ID = types.StringValue(stackID)
+
+	return diags
+}
+
+// setUpstreams records the dependencies a stack has in the model.
+func (m *stackDependenciesModel) setUpstreams(ctx context.Context, upstreams map[string]stackUpstream) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	upstreamIDs := make([]string, 0, len(upstreams))
+	references := []stackDependenciesReferenceModel{}
+
+	for upstreamID, upstream := range upstreams {
+		upstreamIDs = append(upstreamIDs, upstreamID)
+
+		for _, reference := range upstream.references {
+			references = append(references, stackDependenciesReferenceModel{
+				DependsOnStackID: types.StringValue(upstreamID),
+				OutputName:       types.StringValue(reference.outputName),
+				InputName:        types.StringValue(reference.inputName),
+				TriggerAlways:    types.BoolValue(reference.triggerAlways),
+			})
+		}
+	}
+
+	var d diag.Diagnostics
+	m.DependsOnStackIDs, d = types.SetValueFrom(ctx, types.StringType, upstreamIDs)
+	diags.Append(d...)
+
+	m.References, d = types.SetValueFrom(ctx, stackDependenciesReferenceType, references)
+	diags.Append(d...)
+
+	return diags
+}
+
+// knownStrings returns the elements of a set of strings, and whether they are
+// all known.
+func knownStrings(set types.Set) ([]string, bool) {
+	if set.IsUnknown() {
+		return nil, false
+	}
+
+	var values []string
+	for _, element := range set.Elements() {
+		value, ok := element.(types.String)
+		if !ok || value.IsUnknown() {
+			return nil, false
+		}
+		if !value.IsNull() {
+			values = append(values, value.ValueString())
+		}
+	}
+
+	return values, true
+}
@@ -0,0 +1,86 @@
+package spacelift
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+
+	. "github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/testhelpers"
+)
+
+func TestStackDependenciesResource(t *testing.T) {
+	const resourceName = "spacelift_stack_dependencies.test"
+
+	randomID := acctest.RandStringFromCharSet(5, acctest.CharSetAlphaNum)
+
+	config := func(dependencies string) string {
+		return fmt.Sprintf(`
+			resource "spacelift_stack" "app" {
+				branch     = "master"
+				repository = "demo"
+				name       = "app-stack-%s"
+			}
+
+			resource "spacelift_stack" "db" {
+				branch     = "master"
+				repository = "demo"
+				name       = "db-stack-%s"
+			}
+
+			resource "spacelift_stack" "network" {
+				branch     = "master"
+				repository = "demo"
+				name       = "network-stack-%s"
+			}
+
+			resource "spacelift_stack_dependencies" "test" {
+				stack_id = spacelift_stack.app.id
+				%s
+			}
+		`, randomID, randomID, randomID, dependencies)
+	}
+
+	testStepsMux(t, []resource.TestStep{
+		{
+			Config: config(`
+				depends_on_stack_ids = [spacelift_stack.db.id, spacelift_stack.network.id]
+
+				references = [
+					{
+						depends_on_stack_id = spacelift_stack.db.id
+						output_name         = "url"
+						input_name          = "DB_URL"
+					},
+					{
+						depends_on_stack_id = spacelift_stack.network.id
+						output_name         = "vpc_id"
+						input_name          = "TF_VAR_vpc_id"
+						trigger_always      = true
+					},
+				]
+			`),
+			Check: Resource(
+				resourceName,
+				Attribute("id", StartsWith("app-stack")),
+				Attribute("stack_id", StartsWith("app-stack")),
+				Attribute("depends_on_stack_ids.#", Equals("2")),
+				Attribute("references.#", Equals("2")),
+			),
+		},
+		{
+			ResourceName:      resourceName,
+			ImportState:       true,
+			ImportStateVerify: true,
+		},
+		{
+			Config: config(`depends_on_stack_ids = [spacelift_stack.network.id]`),
+			Check: Resource(
+				resourceName,
+				Attribute("depends_on_stack_ids.#", Equals("1")),
+				Attribute("references.#", Equals("0")),
+			),
+		},
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"path"
 	"strings"
 
+	tfpath "github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -27,6 +28,7 @@ var (
 	_ resource.Resource                = (*stackDependencyResource)(nil)
 	_ resource.ResourceWithConfigure   = (*stackDependencyResource)(nil)
 	_ resource.ResourceWithImportState = (*stackDependencyResource)(nil)
+	_ resource.ResourceWithModifyPlan  = (*stackDependencyResource)(nil)
 )
 
 // NewStackDependencyResource returns the Plugin Framework implementation of
@@ -71,7 +73,9 @@ func (r *stackDependencyResource) Schema(_ context.Context, _ resource.SchemaReq
 			"`spacelift_stack_dependency` represents a Spacelift **stack dependency** - " +
 			"a dependency between two stacks. When one stack depends on another, the tracked runs " +
 			"of the stack will not start until the dependent stack is successfully finished. Additionally, " +
-			"changes to the dependency will trigger the dependent.",
+			"changes to the dependency will trigger the dependent. Dependencies which would " +
+			"create a cycle between stacks are rejected at plan time. If the cycle goes through " +
+			"an existing dependency which the same plan may destroy, it is only warned about.",
 
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -98,6 +102,73 @@ func (r *stackDependencyResource) Schema(_ context.Context, _ resource.SchemaReq
 	}
 }
 
+// ModifyPlan rejects dependencies which would close a cycle between stacks,
+// taking into account the dependencies created, kept and destroyed by the same
+// plan as far as they have been planned already. Cycles through dependencies
+// the plan has yet to decide on are only warned about, so that the outcome
+// does not depend on the order Terraform plans resources in.
+func (r *stackDependencyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// The client is nil during schema-validation walks.
+	if r.client == nil {
+		return
+	}
+
+	planned := plannedStackDependenciesFor(r.client)
+
+	var state *stackDependencyModel
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	}
+
+	var plan *stackDependencyModel
+	if !req.Plan.Raw.IsNull() {
+		resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state != nil && (plan == nil || !state.StackID.Equal(plan.StackID) || !state.DependsOnStackID.Equal(plan.DependsOnStackID)) {
+		planned.remove(stackDependencyEdge{
+			stackID:          state.StackID.ValueString(),
+			dependsOnStackID: state.DependsOnStackID.ValueString(),
+		})
+	}
+
+	if plan == nil {
+		return
+	}
+
+	if state != nil && state.StackID.Equal(plan.StackID) && state.DependsOnStackID.Equal(plan.DependsOnStackID) {
+		planned.keep(stackDependencyEdge{
+			stackID:          state.StackID.ValueString(),
+			dependsOnStackID: state.DependsOnStackID.ValueString(),
+		})
+		return
+	}
+
+	if plan.StackID.IsUnknown() || plan.DependsOnStackID.IsUnknown() {
+		return
+	}
+
+	check, err := checkPlannedStackDependency(ctx, r.client, planned, stackDependencyEdge{
+		stackID:          plan.StackID.ValueString(),
+		dependsOnStackID: plan.DependsOnStackID.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("could not check stack dependencies for cycles", err.Error())
+		return
+	}
+
+	switch {
+	case check.certain:
+		resp.Diagnostics.AddAttributeError(tfpath.Root("depends_on_stack_id"), "stack dependency would create a cycle", check.detail)
+	case check.detail != "":
+		resp.Diagnostics.AddAttributeWarning(tfpath.Root("depends_on_stack_id"), "stack dependency may create a cycle", check.detail)
+	}
+}
+
 func (r *stackDependencyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan stackDependencyModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
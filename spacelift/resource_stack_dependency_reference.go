@@ -6,13 +6,16 @@ import (
 	"path"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/oklog/ulid/v2"
 	"github.com/shurcooL/graphql"
 
 	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
 	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/validations"
 )
 
 func resourceStackDependencyReference() *schema.Resource {
@@ -20,17 +23,47 @@ func resourceStackDependencyReference() *schema.Resource {
 		Description: "" +
 			"`spacelift_stack_dependency_reference` represents a Spacelift **stack dependency reference** - " +
 			"a reference matches a stack's output to another stack's input. It is similar to an environment variable " +
-			"(`spacelift_environment_variable`), except that value is provided by another stack's output.",
+			"(`spacelift_environment_variable`), except that value is provided by another stack's output. " +
+			"The output is looked up at plan time, so that a typo in its name is caught before runs " +
+			"of the dependent stack miss the input.",
 
 		CreateContext: resourceStackDependencyReferenceCreate,
 		ReadContext:   resourceStackDependencyReferenceRead,
 		UpdateContext: resourceStackDependencyReferenceUpdate,
 		DeleteContext: resourceStackDependencyReferenceDelete,
+		CustomizeDiff: resourceStackDependencyReferenceCustomizeDiff,
 
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceStackDependencyReferenceImport,
 		},
 
+		Identity: &schema.ResourceIdentity{
+			SchemaFunc: func() map[string]*schema.Schema {
+				return map[string]*schema.Schema{
+					"stack_id": {
+						Type:              schema.TypeString,
+						Description:       "ID of the stack receiving the reference",
+						RequiredForImport: true,
+					},
+					"depends_on_stack_id": {
+						Type:              schema.TypeString,
+						Description:       "ID of the stack providing the output",
+						RequiredForImport: true,
+					},
+					"input_name": {
+						Type:              schema.TypeString,
+						Description:       "Name of the input of the stack dependency reference",
+						RequiredForImport: true,
+					},
+				}
+			},
+		},
+
+		// The input name is part of the identity, yet it can be updated in place.
+		ResourceBehavior: schema.ResourceBehavior{
+			MutableIdentity: true,
+		},
+
 		Schema: map[string]*schema.Schema{
 			"stack_dependency_id": {
 				Type:        schema.TypeString,
@@ -44,9 +77,10 @@ func resourceStackDependencyReference() *schema.Resource {
 				Required:    true,
 			},
 			"input_name": {
-				Type:        schema.TypeString,
-				Description: "Name of the input of the stack dependency reference",
-				Required:    true,
+				Type:             schema.TypeString,
+				Description:      "Name of the input of the stack dependency reference. It has to be a valid environment variable name, and a valid Terraform variable name has to follow the `TF_VAR_` prefix.",
+				Required:         true,
+				ValidateDiagFunc: validations.EnvironmentVariableName,
 			},
 			"trigger_always": {
 				Type:        schema.TypeBool,
@@ -54,6 +88,18 @@ func resourceStackDependencyReference() *schema.Resource {
 				Default:     false,
 				Optional:    true,
 			},
+			"on_missing_output": {
+				Type:         schema.TypeString,
+				Description:  "What to do when the stack depended on has no output named `output_name`: `FAIL` fails the plan, `WARN` lets the plan through and shows a warning only after the reference is applied, since warnings can't be shown at plan time, `IGNORE` does nothing. Defaults to `WARN`, so that a reference can be added together with the output.",
+				Optional:     true,
+				Default:      stackDependencyReferenceOnMissingOutputWarn,
+				ValidateFunc: validation.StringInSlice([]string{stackDependencyReferenceOnMissingOutputFail, stackDependencyReferenceOnMissingOutputWarn, stackDependencyReferenceOnMissingOutputIgnore}, false),
+			},
+			"output_sensitive": {
+				Type:        schema.TypeBool,
+				Description: "Whether the output of the stack depended on is sensitive, as of when the reference was last created or changed",
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -83,18 +129,18 @@ func resourceStackDependencyReferenceCreate(ctx context.Context, d *schema.Resou
 	}
 
 	d.SetId(path.Join(stackID, depID, query.StackDependencyReference.ID))
-	d.Set("output_name", query.StackDependencyReference.OutputName)
-	d.Set("input_name", query.StackDependencyReference.InputName)
-	d.Set("trigger_always", query.StackDependencyReference.TriggerAlways)
 
-	return nil
+	diags = stackDependencyReferenceOutputDiagnostics(ctx, d, meta.(*internal.Client), stackID, depID)
+
+	return append(diags, resourceStackDependencyReferenceRead(ctx, d, meta)...)
 }
 
 func resourceStackDependencyReferenceRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	var query struct {
 		Stack *struct {
 			Dependency *struct {
-				Reference *structs.StackDependencyReference `graphql:"reference(id: $reference_id)"`
+				DependsOnStack struct{ ID string }               `graphql:"dependsOnStack"`
+				Reference      *structs.StackDependencyReference `graphql:"reference(id: $reference_id)"`
 			} `graphql:"dependency(id: $dependency_id)"`
 		} `graphql:"stack(id: $stack_id)"`
 	}
@@ -133,11 +179,25 @@ func resourceStackDependencyReferenceRead(ctx context.Context, d *schema.Resourc
 		}}
 	}
 
+	if err := setIdentity(d, map[string]string{
+		"stack_id":            stackID,
+		"depends_on_stack_id": query.Stack.Dependency.DependsOnStack.ID,
+		"input_name":          query.Stack.Dependency.Reference.InputName,
+	}); err != nil {
+		return diag.Errorf("could not set stack dependency reference identity: %v", err)
+	}
+
 	d.Set("stack_dependency_id", path.Join(stackID, depID))
 	d.Set("output_name", query.Stack.Dependency.Reference.OutputName)
 	d.Set("input_name", query.Stack.Dependency.Reference.InputName)
 	d.Set("trigger_always", query.Stack.Dependency.Reference.TriggerAlways)
 
+	// Imported references, and those created before it was added, don't
+	// have on_missing_output set yet.
+	if _, ok := d.GetOk("on_missing_output"); !ok {
+		d.Set("on_missing_output", stackDependencyReferenceOnMissingOutputWarn)
+	}
+
 	return nil
 }
 
@@ -165,12 +225,66 @@ func resourceStackDependencyReferenceUpdate(ctx context.Context, d *schema.Resou
 		return diag.Errorf("could not update stack dependency reference: %s", err)
 	}
 
+	if err := setIdentity(d, map[string]string{"input_name": query.StackDependencyReference.InputName}); err != nil {
+		return diag.Errorf("could not set stack dependency reference identity: %v", err)
+	}
+
 	d.Set("stack_dependency_id", path.Join(stackID, depID))
 	d.Set("output_name", query.StackDependencyReference.OutputName)
 	d.Set("input_name", query.StackDependencyReference.InputName)
 	d.Set("trigger_always", query.StackDependencyReference.TriggerAlways)
 
-	return nil
+	return stackDependencyReferenceOutputDiagnostics(ctx, d, meta.(*internal.Client), stackID, depID)
+}
+
+func resourceStackDependencyReferenceCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, meta any) error {
+	// An existing reference is only checked again if it changes, so that an
+	// output going away does not break plans of unrelated changes.
+	if diff.Id() != "" && !diff.HasChanges("stack_dependency_id", "output_name", "on_missing_output") {
+		return nil
+	}
+
+	// A dependency created in the same plan can't be looked up until then.
+	if !diff.NewValueKnown("stack_dependency_id") || !diff.NewValueKnown("output_name") {
+		return diff.SetNewComputed("output_sensitive")
+	}
+
+	stackID, depID, err := parseStackDependencyID(diff.Get("stack_dependency_id").(string))
+	if err != nil {
+		return err
+	}
+
+	// Failing to look the output up doesn't make the reference wrong, so
+	// the plan goes ahead and the output is checked again once applied.
+	output, err := lookupStackDependencyReferenceOutput(ctx, meta.(*internal.Client), stackID, depID, diff.Get("output_name").(string))
+	if err != nil {
+		tflog.Warn(ctx, "could not check the output passed on by the stack dependency reference", map[string]any{"error": err.Error()})
+		return diff.SetNewComputed("output_sensitive")
+	}
+
+	if err := output.check(ctx, diff.Get("on_missing_output").(string)); err != nil {
+		return err
+	}
+
+	return diff.SetNew("output_sensitive", output.sensitive())
+}
+
+// stackDependencyReferenceOutputDiagnostics warns about the output passed on
+// by a reference which has just been applied. The reference is in place by
+// then, so failing to look the output up is only warned about too.
+func stackDependencyReferenceOutputDiagnostics(ctx context.Context, d *schema.ResourceData, client *internal.Client, stackID, depID string) diag.Diagnostics {
+	output, err := lookupStackDependencyReferenceOutput(ctx, client, stackID, depID, d.Get("output_name").(string))
+	if err != nil {
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("could not check output %q of the stack depended on", d.Get("output_name").(string)),
+			Detail:   err.Error(),
+		}}
+	}
+
+	d.Set("output_sensitive", output.sensitive())
+
+	return output.diagnostics(d.Get("on_missing_output").(string))
 }
 
 func resourceStackDependencyReferenceDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
@@ -207,6 +321,21 @@ func getStackDependencyIDParts(d *schema.ResourceData) (string, string, diag.Dia
 }
 
 func resourceStackDependencyReferenceImport(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
+	if d.Id() == "" {
+		identity, err := d.Identity()
+		if err != nil {
+			return nil, err
+		}
+
+		// Importing by identity is the same as importing by the
+		// $stackId/$dependsOnStackId/$inputName ID.
+		d.SetId(path.Join(
+			identity.Get("stack_id").(string),
+			identity.Get("depends_on_stack_id").(string),
+			identity.Get("input_name").(string),
+		))
+	}
+
 	stackID, secondPart, thirdPart, err := getStackDependencyReferenceIDParts(d)
 	if err != nil {
 		return nil, fmt.Errorf("invalid import ID format: %w", err)
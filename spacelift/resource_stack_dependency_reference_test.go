@@ -2,6 +2,7 @@ package spacelift
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
@@ -80,6 +81,16 @@ func TestStackDependencyReferenceResource(t *testing.T) {
 					Attribute("trigger_always", Equals("true")),
 				),
 			},
+			{ // fails on a missing output if asked to
+				Config: configWithoutReference() + `
+				resource "spacelift_stack_dependency_reference" "test" {
+					stack_dependency_id = spacelift_stack_dependency.test.id
+					output_name = "output_missing"
+					input_name = "input_456"
+					on_missing_output = "FAIL"
+				}`,
+				ExpectError: regexp.MustCompile(`has no output named "output_missing"`),
+			},
 			{ // deletes reference
 				Config: configWithoutReference(),
 				Check: func(state *terraform.State) error {
@@ -0,0 +1,178 @@
+package spacelift
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/validations"
+)
+
+func resourceStackLock() *schema.Resource {
+	return &schema.Resource{
+		Description: "" +
+			"`spacelift_stack_lock` locks a stack for exclusive use by the API user " +
+			"for as long as the resource exists, and releases the lock when it is " +
+			"destroyed. It is meant for maintenance windows managed by Terraform. " +
+			"Only locks held by the API user can be imported.",
+
+		CreateContext: resourceStackLockCreate,
+		ReadContext:   resourceStackLockRead,
+		UpdateContext: resourceStackLockRead,
+		DeleteContext: resourceStackLockDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceStackLockImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"stack_id": {
+				Type:             schema.TypeString,
+				Description:      "ID of the stack to lock",
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validations.DisallowEmptyString,
+			},
+			"note": {
+				Type:        schema.TypeString,
+				Description: "Note explaining why the stack is locked",
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"take_over": {
+				Type:        schema.TypeBool,
+				Description: "Whether to release a lock held by someone else on the stack before locking it. Requires admin access to the stack. Defaults to `false`.",
+				Optional:    true,
+				Default:     false,
+			},
+			"holder": {
+				Type:        schema.TypeString,
+				Description: "Who holds the lock on the stack",
+				Computed:    true,
+			},
+			"locked_at": {
+				Type:        schema.TypeInt,
+				Description: "Unix timestamp when the stack was locked",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceStackLockCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*internal.Client)
+	stackID := d.Get("stack_id").(string)
+
+	lock, err := getStackLock(ctx, client, stackID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if lock == nil {
+		return diag.Errorf("stack %s does not exist (or you may not have access to it)", stackID)
+	}
+
+	if lock.LockedBy != nil {
+		if !d.Get("take_over").(bool) {
+			return diag.Errorf("stack %s is already locked by %s; set take_over to take the lock over", stackID, *lock.LockedBy)
+		}
+
+		if err := unlockStack(ctx, client, stackID); err != nil {
+			return diag.Errorf("could not take the lock over from %s: %v", *lock.LockedBy, err)
+		}
+	}
+
+	if err := lockStack(ctx, client, stackID, d.Get("note").(string)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(stackID)
+
+	return resourceStackLockRead(ctx, d, meta)
+}
+
+func resourceStackLockRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	lock, err := getStackLock(ctx, meta.(*internal.Client), d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if lock == nil || lock.LockedBy == nil {
+		d.SetId("")
+		return nil
+	}
+
+	// Someone else taking the lock over means ours is gone.
+	if holder := d.Get("holder").(string); holder != "" && holder != *lock.LockedBy {
+		d.SetId("")
+
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  "stack lock was taken over",
+			Detail:   fmt.Sprintf("stack %s is now locked by %s", lock.ID, *lock.LockedBy),
+		}}
+	}
+
+	d.Set("stack_id", lock.ID)
+	d.Set("holder", *lock.LockedBy)
+	d.Set("locked_at", lock.LockedAt)
+	d.Set("note", lock.LockNote)
+
+	return nil
+}
+
+func resourceStackLockDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*internal.Client)
+
+	lock, err := getStackLock(ctx, client, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Only release the lock if it is still ours.
+	if lock != nil && lock.LockedBy != nil && *lock.LockedBy == d.Get("holder").(string) {
+		if err := unlockStack(ctx, client, d.Id()); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// resourceStackLockImport only adopts locks held by the API user. Adopting
+// someone else's would make destroying the resource release their lock.
+func resourceStackLockImport(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
+	client := meta.(*internal.Client)
+
+	lock, err := getStackLock(ctx, client, d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	if lock == nil {
+		return nil, errors.Errorf("stack %s does not exist (or you may not have access to it)", d.Id())
+	}
+
+	if lock.LockedBy == nil {
+		return nil, errors.Errorf("stack %s is not locked", d.Id())
+	}
+
+	viewerID, err := getViewerID(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	if *lock.LockedBy != viewerID {
+		return nil, errors.Errorf("stack %s is locked by %s, not by the API user; only locks held by the API user can be imported", d.Id(), *lock.LockedBy)
+	}
+
+	d.Set("take_over", false)
+
+	return []*schema.ResourceData{d}, nil
+}
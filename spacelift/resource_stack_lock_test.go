@@ -0,0 +1,103 @@
+package spacelift
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+
+	. "github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/testhelpers"
+)
+
+func TestStackLockResource(t *testing.T) {
+	const resourceName = "spacelift_stack_lock.test"
+
+	randomID := acctest.RandStringFromCharSet(5, acctest.CharSetAlphaNum)
+
+	config := func(note string) string {
+		return fmt.Sprintf(`
+			resource "spacelift_stack" "test" {
+				branch     = "master"
+				repository = "demo"
+				name       = "Test stack %s"
+			}
+
+			resource "spacelift_stack_lock" "test" {
+				stack_id = spacelift_stack.test.id
+				note     = "%s"
+			}
+
+			data "spacelift_stacks" "locked" {
+				depends_on = [spacelift_stack_lock.test]
+
+				locked {
+					equals = true
+				}
+			}
+		`, randomID, note)
+	}
+
+	testSteps(t, []resource.TestStep{
+		{
+			Config: config("maintenance"),
+			Check: resource.ComposeTestCheckFunc(
+				Resource(
+					resourceName,
+					Attribute("id", StartsWith("test-stack-")),
+					Attribute("note", Equals("maintenance")),
+					Attribute("holder", IsNotEmpty()),
+					Attribute("locked_at", IsNotEmpty()),
+					Attribute("take_over", Equals("false")),
+				),
+				Resource("data.spacelift_stacks.locked", Attribute("stacks.#", IsNotEmpty())),
+			),
+		},
+		{
+			ResourceName:      resourceName,
+			ImportState:       true,
+			ImportStateVerify: true,
+		},
+		{
+			Config: config("longer maintenance"),
+			Check:  Resource(resourceName, Attribute("note", Equals("longer maintenance"))),
+		},
+	})
+}
+
+func TestStackLockImport(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		lockedBy any
+		wantErr  string
+	}{
+		{name: "held by the API user", lockedBy: "api-key"},
+		{name: "held by someone else", lockedBy: "alice", wantErr: "stack app is locked by alice, not by the API user"},
+		{name: "not locked", lockedBy: nil, wantErr: "stack app is not locked"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := NewGraphQLServer(t, map[string]GraphQLHandler{
+				"StackLockRead": func(GraphQLRequest) any {
+					return map[string]any{"stack": map[string]any{"id": "app", "lockedBy": tc.lockedBy, "lockedAt": 1700000000}}
+				},
+				"ViewerRead": func(GraphQLRequest) any {
+					return map[string]any{"viewer": map[string]any{"id": "api-key"}}
+				},
+			})
+
+			d := resourceStackLock().TestResourceData()
+			d.SetId("app")
+
+			_, err := resourceStackLockImport(context.Background(), d, server.Client())
+
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+				t.Errorf("expected error %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
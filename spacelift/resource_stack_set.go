@@ -0,0 +1,783 @@
+package spacelift
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-cty/cty"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/pkg/errors"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+)
+
+// stackSetExcludedAttributes lists the attributes of spacelift_stack which
+// members of a stack set can't use: state imports are one-off uploads which
+// don't make sense to share, and members are deleted without loading their
+// settings, so there is nothing for the deletion guard to check on deletion.
+var stackSetExcludedAttributes = []string{
+	"abandon_managed_resources",
+	"guard_managed_resources",
+	"import_state",
+	"import_state_file",
+}
+
+// stackSetMemberResource is spacelift_stack as used by the members of a stack
+// set, turning the merged config of a member into the same ResourceData
+// spacelift_stack works with. It makes the same checks at plan time, except
+// that members don't track their autoattachments, and manage_state and slug
+// only take effect when a member is first created rather than replacing it.
+var stackSetMemberResource = sync.OnceValue(func() *schema.Resource {
+	stack := resourceStack()
+
+	members := make(map[string]*schema.Schema, len(stack.Schema))
+	for name, attribute := range stack.Schema {
+		member := *attribute
+		member.ForceNew = false
+		members[name] = &member
+	}
+
+	return &schema.Resource{
+		Schema:        members,
+		Identity:      stack.Identity,
+		SchemaVersion: stack.SchemaVersion,
+
+		CustomizeDiff: func(ctx context.Context, diff *schema.ResourceDiff, meta any) error {
+			if err := validateSpaceliftRepoVCS(diff); err != nil {
+				return err
+			}
+
+			return customizeStackVendorMigration(diff)
+		},
+	}
+})
+
+func resourceStackSet() *schema.Resource {
+	memberSchema := stackSetMemberSchema()
+	memberSchema["key"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Description: "Unique key of the member within the set, used to track the stack created for it",
+		Required:    true,
+	}
+
+	return &schema.Resource{
+		Description: "" +
+			"`spacelift_stack_set` manages a fleet of similar stacks. Every member " +
+			"takes its settings from `defaults`, overridden by whatever the member " +
+			"sets itself, and is created and updated exactly like a `spacelift_stack` " +
+			"with the merged settings. Nested blocks such as `terragrunt` are " +
+			"overridden as a whole. Members are created, updated and deleted " +
+			"concurrently, and only members whose settings changed are updated. " +
+			"If members fail to update, the others are still applied and the " +
+			"failed ones are retried on the next apply. Creating the set is all " +
+			"or nothing: if any member fails to be created, the stacks created " +
+			"for the other members are deleted again. Changes made to the stacks " +
+			"outside of Terraform are not detected, and `manage_state` and `slug` " +
+			"only take effect when a member is first created. The plan shows the " +
+			"settings which change for every member in `member_settings`.",
+
+		CreateContext: resourceStackSetCreate,
+		ReadContext:   resourceStackSetRead,
+		UpdateContext: resourceStackSetUpdate,
+		DeleteContext: resourceStackSetDelete,
+
+		CustomizeDiff: resourceStackSetCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"defaults": {
+				Type:        schema.TypeList,
+				Description: "Settings shared by all members of the set. Takes the same arguments as `spacelift_stack`.",
+				Optional:    true,
+				MaxItems:    1,
+				Elem:        &schema.Resource{Schema: stackSetMemberSchema()},
+			},
+			"member": {
+				Type:        schema.TypeList,
+				Description: "A member of the set. Takes the same arguments as `spacelift_stack`, which override `defaults`.",
+				Required:    true,
+				MinItems:    1,
+				Elem:        &schema.Resource{Schema: memberSchema},
+			},
+			"max_concurrency": {
+				Type:         schema.TypeInt,
+				Description:  "Maximum number of members created, updated or deleted at the same time. Defaults to `10`.",
+				Optional:     true,
+				Default:      10,
+				ValidateFunc: validation.IntBetween(1, 50),
+			},
+			"stack_ids": {
+				Type:        schema.TypeMap,
+				Description: "IDs (slugs) of the stacks created for the members, keyed by member key",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"member_settings": {
+				Type:        schema.TypeMap,
+				Description: "Settings last applied to each member as JSON, leaving out those which are not set, keyed by member key",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// stackSetMemberSchema derives the arguments of a stack set member from those
+// of spacelift_stack. Everything becomes optional, since a member may take any
+// setting from the defaults, and checks spanning several arguments are left
+// to the validation of the merged settings.
+func stackSetMemberSchema() map[string]*schema.Schema {
+	members := make(map[string]*schema.Schema)
+
+	for name, attribute := range stackSetMemberResource().Schema {
+		if slices.Contains(stackSetExcludedAttributes, name) || attribute.Deprecated != "" || (attribute.Computed && !attribute.Optional) {
+			continue
+		}
+
+		member := stackSetLooseSchema(attribute)
+		member.Required = false
+		member.Optional = true
+		member.Computed = false
+		member.Default = nil
+		member.DefaultFunc = nil
+
+		members[name] = member
+	}
+
+	return members
+}
+
+// stackSetLooseSchema copies an argument without the checks which refer to
+// other arguments, or which only make sense on a resource of its own.
+func stackSetLooseSchema(attribute *schema.Schema) *schema.Schema {
+	loose := *attribute
+	loose.ForceNew = false
+	loose.ConflictsWith = nil
+	loose.ExactlyOneOf = nil
+	loose.AtLeastOneOf = nil
+	loose.RequiredWith = nil
+	loose.DiffSuppressFunc = nil
+
+	if block, ok := attribute.Elem.(*schema.Resource); ok {
+		nested := make(map[string]*schema.Schema, len(block.Schema))
+		for name, nestedAttribute := range block.Schema {
+			nested[name] = stackSetLooseSchema(nestedAttribute)
+		}
+		loose.Elem = &schema.Resource{Schema: nested}
+	}
+
+	return &loose
+}
+
+// stackSetMember is a member of a stack set, with its merged settings loaded
+// into spacelift_stack's ResourceData, diffed against the settings last
+// applied to it. The data is nil if the settings are not known yet.
+type stackSetMember struct {
+	index    int
+	key      string
+	settings string
+	data     *schema.ResourceData
+}
+
+// stackSetMembers merges the settings of every member of a stack set with the
+// defaults, and diffs them against the settings last applied to the stack of
+// the member, if there is one, just like spacelift_stack would.
+func stackSetMembers(ctx context.Context, config cty.Value, stackIDs, applied map[string]string, meta any) ([]stackSetMember, diag.Diagnostics) {
+	if config.IsNull() || !config.IsKnown() {
+		return nil, nil
+	}
+
+	defaults := cty.NullVal(cty.DynamicPseudoType)
+	if raw := config.GetAttr("defaults"); raw.IsKnown() && !raw.IsNull() && raw.LengthInt() > 0 {
+		defaults = raw.Index(cty.NumberIntVal(0))
+	}
+
+	rawMembers := config.GetAttr("member")
+	if !rawMembers.IsKnown() || rawMembers.IsNull() {
+		return nil, nil
+	}
+
+	resource := stackSetMemberResource()
+	coreSchema := resource.CoreConfigSchema()
+
+	var members []stackSetMember
+	var diags diag.Diagnostics
+	seen := make(map[string]bool)
+
+	for index, raw := range rawMembers.AsValueSlice() {
+		path := cty.GetAttrPath("member").IndexInt(index)
+
+		rawKey := raw.GetAttr("key")
+		if !rawKey.IsKnown() {
+			return nil, diags
+		}
+
+		key := rawKey.AsString()
+		if seen[key] {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("Duplicate stack set member key %q", key),
+				AttributePath: path.GetAttr("key"),
+			})
+			continue
+		}
+		seen[key] = true
+
+		member := stackSetMember{index: index, key: key}
+
+		merged := mergeStackSetMemberConfig(resource, defaults, raw)
+		if !merged.IsWhollyKnown() {
+			members = append(members, member)
+			continue
+		}
+
+		resourceConfig := terraform.NewResourceConfigShimmed(merged, coreSchema)
+
+		if memberDiags := resource.Validate(resourceConfig); memberDiags.HasError() {
+			for _, d := range memberDiags {
+				d.Summary = fmt.Sprintf("Stack set member %q: %s", key, d.Summary)
+				d.AttributePath = path
+				diags = append(diags, d)
+			}
+			continue
+		}
+
+		settings, err := stackSetMemberSettings(merged)
+		if err != nil {
+			diags = append(diags, stackSetMemberDiagnostic(key, "could not process settings", err, path))
+			continue
+		}
+		member.settings = settings
+
+		state := &terraform.InstanceState{RawState: cty.NullVal(coreSchema.ImpliedType())}
+		if stackID, ok := stackIDs[key]; ok {
+			if state, err = stackSetMemberState(ctx, resource, stackID, applied[key]); err != nil {
+				diags = append(diags, stackSetMemberDiagnostic(key, "could not process the settings last applied", err, path))
+				continue
+			}
+		}
+		state.RawConfig = merged
+
+		instanceDiff, err := resource.SimpleDiff(ctx, state, resourceConfig, meta)
+		if err != nil {
+			diags = append(diags, stackSetMemberDiagnostic(key, "invalid settings", err, path))
+			continue
+		}
+
+		var replaced []string
+		for attribute, attributeDiff := range instanceDiff.Attributes {
+			// Settings left for Spacelift to compute, like the space, are
+			// simply not sent, just like for a spacelift_stack.
+			if attributeDiff.NewComputed {
+				delete(instanceDiff.Attributes, attribute)
+			}
+
+			if attributeDiff.RequiresNew {
+				replaced = append(replaced, attribute)
+			}
+		}
+
+		if len(replaced) > 0 {
+			slices.Sort(replaced)
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("Stack set member %q: changing %s requires replacing its stack", key, strings.Join(replaced, ", ")),
+				Detail:        "Stack sets don't replace the stacks of their members. Remove the member and add it back under a new key instead.",
+				AttributePath: path,
+			})
+			continue
+		}
+
+		if member.data, err = schema.InternalMap(resource.SchemaMap()).Data(state, instanceDiff); err != nil {
+			diags = append(diags, stackSetMemberDiagnostic(key, "could not process settings", err, path))
+			continue
+		}
+
+		members = append(members, member)
+	}
+
+	return members, diags
+}
+
+// mergeStackSetMemberConfig builds the configuration of a spacelift_stack out
+// of the configuration of a member, falling back to the defaults for anything
+// the member does not set. Nested blocks are taken as a whole.
+func mergeStackSetMemberConfig(resource *schema.Resource, defaults, member cty.Value) cty.Value {
+	coreSchema := resource.CoreConfigSchema()
+	stackType := coreSchema.ImpliedType()
+
+	isBlock := func(name string) bool {
+		_, ok := coreSchema.BlockTypes[name]
+		return ok
+	}
+
+	pick := func(source cty.Value, name string) (cty.Value, bool) {
+		if source.IsNull() || !source.Type().IsObjectType() || !source.Type().HasAttribute(name) {
+			return cty.NilVal, false
+		}
+
+		value := source.GetAttr(name)
+		if value.IsNull() {
+			return cty.NilVal, false
+		}
+
+		// Blocks which are not in the configuration show up as empty
+		// collections rather than nulls.
+		if isBlock(name) && value.IsKnown() && value.LengthInt() == 0 {
+			return cty.NilVal, false
+		}
+
+		return value, true
+	}
+
+	attributes := make(map[string]cty.Value, len(stackType.AttributeTypes()))
+	for name, attributeType := range stackType.AttributeTypes() {
+		if value, ok := pick(member, name); ok {
+			attributes[name] = value
+		} else if value, ok := pick(defaults, name); ok {
+			attributes[name] = value
+		} else if isBlock(name) && attributeType.IsListType() {
+			attributes[name] = cty.ListValEmpty(attributeType.ElementType())
+		} else if isBlock(name) && attributeType.IsSetType() {
+			attributes[name] = cty.SetValEmpty(attributeType.ElementType())
+		} else {
+			attributes[name] = cty.NullVal(attributeType)
+		}
+	}
+
+	return cty.ObjectVal(attributes)
+}
+
+// stackSetMemberSettings serializes the merged settings of a member as JSON,
+// leaving out everything which is not set, so that the plan of a stack set
+// shows which settings of a member change.
+func stackSetMemberSettings(merged cty.Value) (string, error) {
+	raw, err := ctyjson.Marshal(merged, merged.Type())
+	if err != nil {
+		return "", errors.Wrap(err, "could not serialize stack settings")
+	}
+
+	var settings any
+	if err := json.Unmarshal(raw, &settings); err != nil {
+		return "", errors.Wrap(err, "could not serialize stack settings")
+	}
+
+	compact, err := json.Marshal(pruneStackSetMemberSettings(settings))
+	if err != nil {
+		return "", errors.Wrap(err, "could not serialize stack settings")
+	}
+
+	return string(compact), nil
+}
+
+// pruneStackSetMemberSettings drops the nulls and empty lists from serialized
+// settings, including those of nested blocks.
+func pruneStackSetMemberSettings(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		for name, nested := range value {
+			if list, ok := nested.([]any); nested == nil || (ok && len(list) == 0) {
+				delete(value, name)
+				continue
+			}
+			value[name] = pruneStackSetMemberSettings(nested)
+		}
+	case []any:
+		for i, nested := range value {
+			value[i] = pruneStackSetMemberSettings(nested)
+		}
+	}
+
+	return value
+}
+
+// stackSetMemberState rebuilds the state of the stack of a member from the
+// settings last applied to it, so that its settings are diffed against them.
+func stackSetMemberState(ctx context.Context, resource *schema.Resource, stackID, settings string) (*terraform.InstanceState, error) {
+	state := &terraform.InstanceState{ID: stackID, Attributes: map[string]string{"id": stackID}}
+	if settings == "" {
+		return state, nil
+	}
+
+	coreSchema := resource.CoreConfigSchema()
+
+	raw, err := ctyjson.Unmarshal([]byte(settings), coreSchema.ImpliedType())
+	if err != nil {
+		return nil, err
+	}
+
+	config := mergeStackSetMemberConfig(resource, cty.NullVal(cty.DynamicPseudoType), raw)
+
+	blank := &terraform.InstanceState{RawConfig: config}
+
+	instanceDiff, err := schema.InternalMap(resource.SchemaMap()).Diff(ctx, blank, terraform.NewResourceConfigShimmed(config, coreSchema), nil, nil, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if instanceDiff != nil {
+		for attribute, attributeDiff := range instanceDiff.Attributes {
+			if attributeDiff.NewComputed {
+				delete(instanceDiff.Attributes, attribute)
+			}
+		}
+		state = state.MergeDiff(instanceDiff)
+	}
+
+	if state.RawState, err = state.AttrsAsObjectValue(coreSchema.ImpliedType()); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+func stackSetMemberDiagnostic(key, summary string, err error, path cty.Path) diag.Diagnostic {
+	return diag.Diagnostic{
+		Severity:      diag.Error,
+		Summary:       fmt.Sprintf("Stack set member %q: %s", key, summary),
+		Detail:        err.Error(),
+		AttributePath: path,
+	}
+}
+
+func resourceStackSetCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, meta any) error {
+	oldStackIDs := toStringMap(diff.Get("stack_ids"))
+	oldSettingsRaw, _ := diff.GetChange("member_settings")
+	oldSettings := toStringMap(oldSettingsRaw)
+
+	members, diags := stackSetMembers(ctx, diff.GetRawConfig(), oldStackIDs, oldSettings, meta)
+	if diags.HasError() {
+		return stackSetDiagsError(diags)
+	}
+
+	settings := make(map[string]string, len(members))
+	allKnown := len(members) > 0
+	keysChanged := len(members) != len(oldStackIDs)
+
+	for _, member := range members {
+		if _, ok := oldStackIDs[member.key]; !ok {
+			keysChanged = true
+		}
+
+		if member.data == nil {
+			allKnown = false
+			continue
+		}
+
+		settings[member.key] = member.settings
+	}
+
+	if keysChanged {
+		if err := diff.SetNewComputed("stack_ids"); err != nil {
+			return err
+		}
+	}
+
+	if !allKnown {
+		return diff.SetNewComputed("member_settings")
+	}
+
+	if !maps.Equal(settings, oldSettings) {
+		return diff.SetNew("member_settings", settings)
+	}
+
+	return nil
+}
+
+func resourceStackSetCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	members, diags := stackSetMembers(ctx, d.GetRawConfig(), nil, nil, meta)
+	if diags.HasError() {
+		return diags
+	}
+
+	stackIDs, settings, diags := applyStackSetMembers(ctx, d, meta, members, map[string]string{}, map[string]string{})
+	if !diags.HasError() {
+		d.SetId(id.UniqueId())
+		d.Set("stack_ids", stackIDs)
+		d.Set("member_settings", settings)
+
+		return resourceStackSetRead(ctx, d, meta)
+	}
+
+	// Leaving the set half-created would get it tainted and all of its
+	// stacks replaced on the next apply, so undo what was done instead.
+	rollbackKeys := make([]string, 0, len(stackIDs))
+	for key := range stackIDs {
+		rollbackKeys = append(rollbackKeys, key)
+	}
+
+	deleteErrors := deleteStackSetMembers(ctx, d, meta, rollbackKeys, stackIDs)
+	for key, err := range deleteErrors {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Stack set member %q: could not delete stack %s after the stack set failed to be created", key, stackIDs[key]),
+			Detail:   err.Error(),
+		})
+	}
+
+	return diags
+}
+
+func resourceStackSetRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*internal.Client)
+
+	stackIDs := toStringMap(d.Get("stack_ids"))
+	settings := toStringMap(d.Get("member_settings"))
+
+	keys := slices.Sorted(maps.Keys(stackIDs))
+
+	var mu sync.Mutex
+	errs := runStackSetWorkers(ctx, d.Get("max_concurrency").(int), keys, func(ctx context.Context, key string) error {
+		stack, err := getStackByID(ctx, client, stackIDs[key])
+		if err != nil {
+			return err
+		}
+
+		// A member whose stack is gone loses its settings, so that the
+		// next plan creates it again.
+		if stack == nil {
+			mu.Lock()
+			delete(stackIDs, key)
+			delete(settings, key)
+			mu.Unlock()
+		}
+
+		return nil
+	})
+
+	if diags := stackSetErrorDiags("could not read stack", errs, nil); diags.HasError() {
+		return diags
+	}
+
+	d.Set("stack_ids", stackIDs)
+	d.Set("member_settings", settings)
+
+	return nil
+}
+
+func resourceStackSetUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	oldStackIDsRaw, _ := d.GetChange("stack_ids")
+	oldSettingsRaw, _ := d.GetChange("member_settings")
+	oldStackIDs := toStringMap(oldStackIDsRaw)
+	oldSettings := toStringMap(oldSettingsRaw)
+
+	members, diags := stackSetMembers(ctx, d.GetRawConfig(), oldStackIDs, oldSettings, meta)
+	if diags.HasError() {
+		return diags
+	}
+
+	stackIDs, settings, diags := applyStackSetMembers(ctx, d, meta, members, oldStackIDs, oldSettings)
+
+	var removed []string
+	for key := range oldStackIDs {
+		if !slices.ContainsFunc(members, func(member stackSetMember) bool { return member.key == key }) {
+			removed = append(removed, key)
+		}
+	}
+
+	deleteErrors := deleteStackSetMembers(ctx, d, meta, removed, oldStackIDs)
+	for _, key := range removed {
+		if err, failed := deleteErrors[key]; failed {
+			// Keep track of the stack until it is actually gone.
+			stackIDs[key] = oldStackIDs[key]
+			settings[key] = oldSettings[key]
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Stack set member %q: could not delete stack %s", key, oldStackIDs[key]),
+				Detail:   err.Error(),
+			})
+		}
+	}
+
+	d.Set("stack_ids", stackIDs)
+	d.Set("member_settings", settings)
+
+	return diags
+}
+
+func resourceStackSetDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	stackIDs := toStringMap(d.Get("stack_ids"))
+	keys := slices.Sorted(maps.Keys(stackIDs))
+
+	var diags diag.Diagnostics
+	for key, err := range deleteStackSetMembers(ctx, d, meta, keys, stackIDs) {
+		delete(stackIDs, key)
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Stack set member %q: could not delete stack", key),
+			Detail:   err.Error(),
+		})
+	}
+
+	if diags.HasError() {
+		d.Set("stack_ids", stackIDs)
+		return diags
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// applyStackSetMembers creates the members which have no stack yet and
+// updates those whose settings changed. It returns the stack IDs and settings
+// of all members which are in sync, which for failed members means their
+// previous values, if any.
+func applyStackSetMembers(ctx context.Context, d *schema.ResourceData, meta any, members []stackSetMember, oldStackIDs, oldSettings map[string]string) (map[string]string, map[string]string, diag.Diagnostics) {
+	stackIDs := make(map[string]string, len(members))
+	settings := make(map[string]string, len(members))
+
+	byKey := make(map[string]stackSetMember, len(members))
+	var keys []string
+
+	for _, member := range members {
+		if stackID, ok := oldStackIDs[member.key]; ok {
+			stackIDs[member.key] = stackID
+			settings[member.key] = oldSettings[member.key]
+		}
+
+		byKey[member.key] = member
+		keys = append(keys, member.key)
+	}
+
+	var mu sync.Mutex
+	errs := runStackSetWorkers(ctx, d.Get("max_concurrency").(int), keys, func(ctx context.Context, key string) error {
+		member := byKey[key]
+		if member.data == nil {
+			return errors.New("settings are not known")
+		}
+
+		_, exists := oldStackIDs[key]
+		if exists && oldSettings[key] == member.settings {
+			return nil
+		}
+
+		var memberDiags diag.Diagnostics
+		if exists {
+			memberDiags = resourceStackUpdate(ctx, member.data, meta)
+		} else {
+			memberDiags = resourceStackCreate(ctx, member.data, meta)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		// Even a failed create may have left a stack behind, which has to
+		// be tracked so that it is not orphaned.
+		if member.data.Id() != "" {
+			stackIDs[key] = member.data.Id()
+		}
+
+		if memberDiags.HasError() {
+			return stackSetDiagsError(memberDiags)
+		}
+
+		settings[key] = member.settings
+
+		return nil
+	})
+
+	diags := stackSetErrorDiags("could not apply settings", errs, func(key string) cty.Path {
+		return cty.GetAttrPath("member").IndexInt(byKey[key].index)
+	})
+
+	return stackIDs, settings, diags
+}
+
+// deleteStackSetMembers deletes the stacks of the given members, returning
+// the errors of those which could not be deleted.
+func deleteStackSetMembers(ctx context.Context, d *schema.ResourceData, meta any, keys []string, stackIDs map[string]string) map[string]error {
+	return runStackSetWorkers(ctx, d.Get("max_concurrency").(int), keys, func(ctx context.Context, key string) error {
+		data := stackSetMemberResource().Data(nil)
+		data.SetId(stackIDs[key])
+
+		if diags := resourceStackDelete(ctx, data, meta); diags.HasError() {
+			return stackSetDiagsError(diags)
+		}
+
+		return nil
+	})
+}
+
+// runStackSetWorkers calls fn for every key, with at most workers calls in
+// flight at the same time, and returns the errors keyed by member key.
+func runStackSetWorkers(ctx context.Context, workers int, keys []string, fn func(ctx context.Context, key string) error) map[string]error {
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs = make(map[string]error)
+	)
+
+	semaphore := make(chan struct{}, max(workers, 1))
+
+	for _, key := range keys {
+		wg.Go(func() {
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				mu.Lock()
+				errs[key] = ctx.Err()
+				mu.Unlock()
+				return
+			}
+
+			if err := fn(ctx, key); err != nil {
+				mu.Lock()
+				errs[key] = err
+				mu.Unlock()
+			}
+		})
+	}
+
+	wg.Wait()
+
+	return errs
+}
+
+// stackSetErrorDiags turns per-member errors into diagnostics, sorted by
+// member key so that the output is stable. The path, if given, points each
+// diagnostic at the configuration of the member.
+func stackSetErrorDiags(summary string, errs map[string]error, path func(key string) cty.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, key := range slices.Sorted(maps.Keys(errs)) {
+		d := diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Stack set member %q: %s", key, summary),
+			Detail:   errs[key].Error(),
+		}
+
+		if path != nil {
+			d.AttributePath = path(key)
+		}
+
+		diags = append(diags, d)
+	}
+	return diags
+}
+
+func stackSetDiagsError(diags diag.Diagnostics) error {
+	var messages []string
+	for _, d := range diags {
+		if d.Severity != diag.Error {
+			continue
+		}
+
+		message := d.Summary
+		if d.Detail != "" {
+			message += ": " + d.Detail
+		}
+		messages = append(messages, message)
+	}
+
+	return errors.New(strings.Join(messages, "; "))
+}
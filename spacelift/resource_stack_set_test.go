@@ -0,0 +1,341 @@
+package spacelift
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs"
+	. "github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/testhelpers"
+)
+
+func TestStackSetResource(t *testing.T) {
+	const resourceName = "spacelift_stack_set.test"
+
+	randomID := acctest.RandStringFromCharSet(5, acctest.CharSetAlphaNum)
+
+	config := func(autodeploy bool, members ...string) string {
+		var blocks string
+		for _, member := range members {
+			blocks += fmt.Sprintf(`
+				member {
+					key          = "%s"
+					name         = "Stack set %s %s"
+					project_root = "services/%s"
+					labels       = ["service:%s"]
+				}
+			`, member, randomID, member, member, member)
+		}
+
+		return fmt.Sprintf(`
+			resource "spacelift_stack_set" "test" {
+				max_concurrency = 2
+
+				defaults {
+					branch     = "master"
+					repository = "demo"
+					autodeploy = %t
+					labels     = ["stack-set"]
+				}
+
+				%s
+			}
+		`, autodeploy, blocks)
+	}
+
+	testSteps(t, []resource.TestStep{
+		{
+			Config: config(false, "api", "web", "worker"),
+			Check: Resource(
+				resourceName,
+				Attribute("stack_ids.%", Equals("3")),
+				Attribute("stack_ids.api", StartsWith("stack-set-")),
+				Attribute("member_settings.%", Equals("3")),
+				Attribute("member_settings.api", Contains(`"project_root":"services/api"`)),
+			),
+		},
+		{
+			Config: config(true, "api", "web"),
+			Check: Resource(
+				resourceName,
+				Attribute("stack_ids.%", Equals("2")),
+				AttributeNotPresent("stack_ids.worker"),
+			),
+		},
+		{
+			Config:      config(true, "api", "api"),
+			ExpectError: regexp.MustCompile(`Duplicate stack set member key "api"`),
+		},
+	})
+}
+
+// stackSetConfig builds the raw configuration of a stack set, with every
+// attribute which is not given set to null or, for blocks, empty.
+func stackSetConfig(t *testing.T, attributes map[string]cty.Value) cty.Value {
+	t.Helper()
+
+	return objectWith(resourceStackSet().CoreConfigSchema().ImpliedType(), attributes)
+}
+
+func objectWith(objectType cty.Type, attributes map[string]cty.Value) cty.Value {
+	values := make(map[string]cty.Value)
+	for name, attributeType := range objectType.AttributeTypes() {
+		switch value, ok := attributes[name]; {
+		case ok:
+			values[name] = value
+		case attributeType.IsListType() && attributeType.ElementType().IsObjectType():
+			values[name] = cty.ListValEmpty(attributeType.ElementType())
+		default:
+			values[name] = cty.NullVal(attributeType)
+		}
+	}
+	return cty.ObjectVal(values)
+}
+
+func TestStackSetMembers(t *testing.T) {
+	setType := resourceStackSet().CoreConfigSchema().ImpliedType()
+	memberType := setType.AttributeType("member").ElementType()
+	defaultsType := setType.AttributeType("defaults").ElementType()
+	terragruntType := memberType.AttributeType("terragrunt").ElementType()
+
+	config := stackSetConfig(t, map[string]cty.Value{
+		"max_concurrency": cty.NumberIntVal(5),
+		"defaults": cty.ListVal([]cty.Value{objectWith(defaultsType, map[string]cty.Value{
+			"branch":     cty.StringVal("main"),
+			"repository": cty.StringVal("monorepo"),
+			"autodeploy": cty.True,
+			"labels":     cty.SetVal([]cty.Value{cty.StringVal("shared")}),
+		})}),
+		"member": cty.ListVal([]cty.Value{
+			objectWith(memberType, map[string]cty.Value{
+				"key":          cty.StringVal("api"),
+				"name":         cty.StringVal("API"),
+				"project_root": cty.StringVal("services/api"),
+			}),
+			objectWith(memberType, map[string]cty.Value{
+				"key":        cty.StringVal("legacy"),
+				"name":       cty.StringVal("Legacy"),
+				"branch":     cty.StringVal("release"),
+				"autodeploy": cty.False,
+				"labels":     cty.SetVal([]cty.Value{cty.StringVal("legacy")}),
+				"terragrunt": cty.ListVal([]cty.Value{objectWith(terragruntType, map[string]cty.Value{
+					"use_run_all": cty.True,
+				})}),
+			}),
+		}),
+	})
+
+	members, diags := stackSetMembers(context.Background(), config, nil, nil, nil)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	if len(members) != 2 {
+		t.Fatalf("got %d members, want 2", len(members))
+	}
+
+	api := stackInput(members[0].data)
+	if api.Name != "API" || api.Branch != "main" || api.Repository != "monorepo" || !api.Autodeploy {
+		t.Errorf("api member did not take the defaults: %+v", api)
+	}
+	if api.ProjectRoot == nil || *api.ProjectRoot != "services/api" {
+		t.Errorf("api project root = %v, want services/api", api.ProjectRoot)
+	}
+	if api.Labels == nil || len(*api.Labels) != 1 || (*api.Labels)[0] != "shared" {
+		t.Errorf("api labels = %v, want [shared]", api.Labels)
+	}
+	if api.VendorConfig.Terraform == nil || api.VendorConfig.TerragruntInput != nil {
+		t.Errorf("api vendor config = %+v, want Terraform", api.VendorConfig)
+	}
+	if !members[0].data.Get("manage_state").(bool) {
+		t.Errorf("api manage_state did not default to true")
+	}
+
+	legacy := stackInput(members[1].data)
+	if legacy.Branch != "release" || legacy.Autodeploy {
+		t.Errorf("legacy member did not override the defaults: %+v", legacy)
+	}
+	if legacy.Labels == nil || len(*legacy.Labels) != 1 || (*legacy.Labels)[0] != "legacy" {
+		t.Errorf("legacy labels = %v, want [legacy]", legacy.Labels)
+	}
+	if legacy.VendorConfig.TerragruntInput == nil || legacy.VendorConfig.TerragruntInput.UseRunAll != true {
+		t.Errorf("legacy vendor config = %+v, want Terragrunt with run-all", legacy.VendorConfig)
+	}
+
+	if want := `{"autodeploy":true,"branch":"main","labels":["shared"],"name":"API","project_root":"services/api","repository":"monorepo"}`; members[0].settings != want {
+		t.Errorf("api settings = %s, want %s", members[0].settings, want)
+	}
+
+	again, _ := stackSetMembers(context.Background(), config, nil, nil, nil)
+	if again[0].settings != members[0].settings {
+		t.Errorf("settings are not stable: %s != %s", again[0].settings, members[0].settings)
+	}
+}
+
+func TestStackSetMembersApplied(t *testing.T) {
+	setType := resourceStackSet().CoreConfigSchema().ImpliedType()
+	memberType := setType.AttributeType("member").ElementType()
+	terragruntType := memberType.AttributeType("terragrunt").ElementType()
+
+	config := func(member map[string]cty.Value) cty.Value {
+		return stackSetConfig(t, map[string]cty.Value{
+			"member": cty.ListVal([]cty.Value{objectWith(memberType, member)}),
+		})
+	}
+
+	api := map[string]cty.Value{
+		"key":        cty.StringVal("api"),
+		"name":       cty.StringVal("API"),
+		"branch":     cty.StringVal("main"),
+		"repository": cty.StringVal("monorepo"),
+	}
+
+	applied, diags := stackSetMembers(context.Background(), config(api), nil, nil, nil)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	stackIDs := map[string]string{"api": "api-stack"}
+	settings := map[string]string{"api": applied[0].settings}
+
+	t.Run("changed settings", func(t *testing.T) {
+		changed := maps.Clone(api)
+		changed["name"] = cty.StringVal("Public API")
+
+		members, diags := stackSetMembers(context.Background(), config(changed), stackIDs, settings, nil)
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+
+		data := members[0].data
+		if data.Id() != "api-stack" {
+			t.Errorf("ID = %q, want api-stack", data.Id())
+		}
+
+		if oldName, newName := data.GetChange("name"); oldName != "API" || newName != "Public API" {
+			t.Errorf("name changes from %v to %v, want from API to Public API", oldName, newName)
+		}
+
+		if data.HasChange("branch") {
+			t.Errorf("branch changed, want it unchanged")
+		}
+	})
+
+	t.Run("vendor migration", func(t *testing.T) {
+		migrated := maps.Clone(api)
+		migrated["terragrunt"] = cty.ListVal([]cty.Value{objectWith(terragruntType, map[string]cty.Value{
+			"use_state_management": cty.True,
+		})})
+
+		members, diags := stackSetMembers(context.Background(), config(migrated), stackIDs, settings, nil)
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+
+		if direction := vendorMigrationDirection(members[0].data); direction != structs.StackVendorTerragrunt {
+			t.Errorf("vendor migration direction = %q, want %q", direction, structs.StackVendorTerragrunt)
+		}
+	})
+
+	t.Run("stack checks", func(t *testing.T) {
+		migrated := maps.Clone(api)
+		migrated["terragrunt"] = cty.ListVal([]cty.Value{objectWith(terragruntType, map[string]cty.Value{
+			"use_state_management": cty.False,
+		})})
+
+		_, diags := stackSetMembers(context.Background(), config(migrated), stackIDs, settings, nil)
+		if !diags.HasError() || !strings.Contains(diags[0].Detail, "cannot change state management during vendor migration") {
+			t.Errorf("state management change not reported, got %v", diags)
+		}
+	})
+}
+
+func TestStackSetMembersValidation(t *testing.T) {
+	memberType := resourceStackSet().CoreConfigSchema().ImpliedType().AttributeType("member").ElementType()
+
+	config := stackSetConfig(t, map[string]cty.Value{
+		"member": cty.ListVal([]cty.Value{
+			objectWith(memberType, map[string]cty.Value{
+				"key":        cty.StringVal("api"),
+				"branch":     cty.StringVal("main"),
+				"repository": cty.StringVal("monorepo"),
+			}),
+			objectWith(memberType, map[string]cty.Value{
+				"key":        cty.StringVal("api"),
+				"name":       cty.StringVal("API"),
+				"branch":     cty.StringVal("main"),
+				"repository": cty.StringVal("monorepo"),
+			}),
+			objectWith(memberType, map[string]cty.Value{
+				"key":            cty.StringVal("repo"),
+				"name":           cty.StringVal("Repo"),
+				"branch":         cty.StringVal("master"),
+				"repository":     cty.StringVal("monorepo"),
+				"spacelift_repo": cty.ListVal([]cty.Value{cty.EmptyObjectVal}),
+			}),
+		}),
+	})
+
+	_, diags := stackSetMembers(context.Background(), config, nil, nil, nil)
+
+	var summaries []string
+	for _, d := range diags {
+		summaries = append(summaries, d.Summary+": "+d.Detail)
+	}
+
+	if !slices.ContainsFunc(summaries, regexp.MustCompile(`^Stack set member "api": Missing required argument: .*"name"`).MatchString) {
+		t.Errorf("missing name not reported, got %v", summaries)
+	}
+
+	if !slices.Contains(summaries, `Duplicate stack set member key "api": `) {
+		t.Errorf("duplicate key not reported, got %v", summaries)
+	}
+
+	if !slices.Contains(summaries, `Stack set member "repo": invalid settings: branch must be "main" when using a Spacelift repo, got "master"`) {
+		t.Errorf("stack checks not run, got %v", summaries)
+	}
+}
+
+func TestRunStackSetWorkers(t *testing.T) {
+	var inFlight, peak atomic.Int32
+
+	keys := []string{"a", "b", "c", "d", "e", "f"}
+
+	errs := runStackSetWorkers(context.Background(), 2, keys, func(_ context.Context, key string) error {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+
+		for {
+			previous := peak.Load()
+			if current <= previous || peak.CompareAndSwap(previous, current) {
+				break
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+
+		if key == "c" {
+			return errors.New("boom")
+		}
+		return nil
+	})
+
+	if got := peak.Load(); got > 2 {
+		t.Errorf("%d workers ran at the same time, want at most 2", got)
+	}
+
+	if len(errs) != 1 || errs["c"] == nil {
+		t.Errorf("errors = %v, want only c to fail", errs)
+	}
+}
@@ -0,0 +1,297 @@
+package spacelift
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/graphql"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/validations"
+)
+
+func resourceStackState() *schema.Resource {
+	return &schema.Resource{
+		Description: "" +
+			"`spacelift_stack_state` pushes a Terraform state to an existing stack " +
+			"with `manage_state` enabled, either by uploading a state file or by " +
+			"restoring one of the stack's earlier state versions. Unless `force` is " +
+			"set, the state must share the lineage of the stack's current state, and " +
+			"an uploaded state must have a higher serial. Destroying the resource " +
+			"leaves the stack's state as it is.",
+
+		CreateContext: resourceStackStateCreate,
+		ReadContext:   resourceStackStateRead,
+		DeleteContext: schema.NoopContext,
+
+		Schema: map[string]*schema.Schema{
+			"stack_id": {
+				Type:             schema.TypeString,
+				Description:      "ID of the stack to push the state to",
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validations.DisallowEmptyString,
+			},
+			"state_file": {
+				Type:         schema.TypeString,
+				Description:  "Path to the state file to upload",
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"state_file", "state_version_id"},
+			},
+			"state_version_id": {
+				Type:        schema.TypeString,
+				Description: "ID of the state version to restore, as listed by `spacelift_stack_state_versions`",
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"force": {
+				Type:        schema.TypeBool,
+				Description: "Skip the lineage and serial checks. Defaults to `false`.",
+				Optional:    true,
+				Default:     false,
+				ForceNew:    true,
+			},
+			"keepers": {
+				Type:        schema.TypeMap,
+				Description: "Arbitrary map of values that, when changed, will trigger recreation of the resource, pushing the state again.",
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"lineage": {
+				Type:        schema.TypeString,
+				Description: "Lineage of the pushed state",
+				Computed:    true,
+			},
+			"serial": {
+				Type:        schema.TypeInt,
+				Description: "Serial of the stack's state after the push",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// stateFileHeader holds the fields of a state file which tell its history.
+type stateFileHeader struct {
+	Lineage string `json:"lineage"`
+	Serial  int    `json:"serial"`
+}
+
+func resourceStackStateCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*internal.Client)
+	stackID := d.Get("stack_id").(string)
+	force := d.Get("force").(bool)
+
+	versions, err := getStackStateVersions(ctx, client, stackID)
+	if err != nil {
+		return diag.FromErr(err)
+	} else if versions == nil {
+		return diag.Errorf("stack %s does not exist (or you may not have access to it)", stackID)
+	}
+
+	var current *structs.StateVersion
+	if len(versions) > 0 {
+		current = &versions[0]
+	}
+
+	if versionID, ok := d.GetOk("state_version_id"); ok {
+		err = restoreStateVersion(ctx, client, stackID, versionID.(string), versions, current, force)
+	} else {
+		err = pushStateFile(ctx, client, stackID, d.Get("state_file").(string), current, force)
+	}
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	versions, err = getStackStateVersions(ctx, client, stackID)
+	if err != nil {
+		return diag.FromErr(err)
+	} else if len(versions) == 0 {
+		return diag.Errorf("stack %s has no state after the push", stackID)
+	}
+
+	d.SetId(path.Join(stackID, versions[0].ID))
+	d.Set("lineage", versions[0].Lineage)
+	d.Set("serial", versions[0].Serial)
+
+	return nil
+}
+
+func resourceStackStateRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	stackID, _, _ := strings.Cut(d.Id(), "/")
+
+	stack, err := getStackByID(ctx, meta.(*internal.Client), stackID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// The push is a one-off, so there is nothing to read back. Only a stack
+	// which is gone takes the state with it.
+	if stack == nil {
+		d.SetId("")
+	}
+
+	return nil
+}
+
+func pushStateFile(ctx context.Context, client *internal.Client, stackID, statePath string, current *structs.StateVersion, force bool) error {
+	header, err := readStateFileHeader(statePath)
+	if err != nil {
+		return err
+	}
+
+	if current != nil && !force {
+		if header.Lineage != current.Lineage {
+			return errors.Errorf("state file lineage %s does not match the lineage %s of the stack's state; set force to push it anyway", header.Lineage, current.Lineage)
+		}
+
+		if header.Serial <= current.Serial {
+			return errors.Errorf("state file serial %d is not higher than the serial %d of the stack's state; set force to push it anyway", header.Serial, current.Serial)
+		}
+	}
+
+	objectID, _, err := uploadStateFile(ctx, client, statePath)
+	if err != nil {
+		return err
+	}
+
+	var mutation struct {
+		StackStateImport struct {
+			ID string `graphql:"id"`
+		} `graphql:"stackStateImport(id: $id, stateObjectID: $stateObjectID)"`
+	}
+
+	variables := map[string]any{
+		"id":            graphql.ID(stackID),
+		"stateObjectID": graphql.String(objectID),
+	}
+
+	if err := client.Mutate(ctx, "StackStateImport", &mutation, variables); err != nil {
+		return errors.Wrapf(internal.FromSpaceliftError(err), "could not push state to stack %s", stackID)
+	}
+
+	return nil
+}
+
+// readStateFileHeader reads the state file up to its lineage and serial,
+// skipping over anything before them without decoding it, so that the
+// resources of a large state are never held in memory.
+func readStateFileHeader(statePath string) (*stateFileHeader, error) {
+	file, err := os.Open(statePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read state file")
+	}
+	defer file.Close()
+
+	header, err := decodeStateFileHeader(json.NewDecoder(file))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse state file")
+	}
+
+	return header, nil
+}
+
+func decodeStateFileHeader(decoder *json.Decoder) (*stateFileHeader, error) {
+	if token, err := decoder.Token(); err != nil {
+		return nil, err
+	} else if token != json.Delim('{') {
+		return nil, errors.New("state is not a JSON object")
+	}
+
+	var header stateFileHeader
+	var hasLineage, hasSerial bool
+
+	for !hasLineage || !hasSerial {
+		if !decoder.More() {
+			return nil, errors.New("state has no lineage or serial")
+		}
+
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch token {
+		case "lineage":
+			hasLineage = true
+			err = decoder.Decode(&header.Lineage)
+		case "serial":
+			hasSerial = true
+			err = decoder.Decode(&header.Serial)
+		default:
+			err = skipJSONValue(decoder)
+		}
+
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not read %v", token)
+		}
+	}
+
+	return &header, nil
+}
+
+// skipJSONValue reads past the next value token by token.
+func skipJSONValue(decoder *json.Decoder) error {
+	depth := 0
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+func restoreStateVersion(ctx context.Context, client *internal.Client, stackID, versionID string, versions []structs.StateVersion, current *structs.StateVersion, force bool) error {
+	var restored *structs.StateVersion
+	for i := range versions {
+		if versions[i].ID == versionID {
+			restored = &versions[i]
+		}
+	}
+
+	if restored == nil {
+		return errors.Errorf("stack %s has no state version %s", stackID, versionID)
+	}
+
+	if !force && restored.Lineage != current.Lineage {
+		return errors.Errorf("state version lineage %s does not match the lineage %s of the stack's state; set force to restore it anyway", restored.Lineage, current.Lineage)
+	}
+
+	var mutation struct {
+		StackStateVersionRestore struct {
+			ID string `graphql:"id"`
+		} `graphql:"stackStateVersionRestore(id: $id, stateVersionId: $stateVersionId)"`
+	}
+
+	variables := map[string]any{
+		"id":             graphql.ID(stackID),
+		"stateVersionId": graphql.ID(versionID),
+	}
+
+	if err := client.Mutate(ctx, "StackStateVersionRestore", &mutation, variables); err != nil {
+		return errors.Wrapf(internal.FromSpaceliftError(err), "could not restore state version %s of stack %s", versionID, stackID)
+	}
+
+	return nil
+}
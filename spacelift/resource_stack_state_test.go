@@ -0,0 +1,129 @@
+package spacelift
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+
+	. "github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/testhelpers"
+)
+
+func TestStackStateResource(t *testing.T) {
+	const resourceName = "spacelift_stack_state.test"
+
+	randomID := acctest.RandStringFromCharSet(5, acctest.CharSetAlphaNum)
+	lineage := "test-lineage-" + randomID
+
+	stateFile := func(serial int, lineage string) string {
+		path := filepath.Join(t.TempDir(), "terraform.tfstate")
+		content := fmt.Sprintf(`{"version": 4, "serial": %d, "lineage": %q, "resources": [], "outputs": {}}`, serial, lineage)
+
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		return path
+	}
+
+	config := func(push string) string {
+		return fmt.Sprintf(`
+			resource "spacelift_stack" "test" {
+				name         = "Test stack %s"
+				branch       = "master"
+				repository   = "demo"
+				manage_state = true
+				import_state = jsonencode({ version = 4, serial = 3, lineage = %q, resources = [], outputs = {} })
+			}
+
+			resource "spacelift_stack_state" "test" {
+				stack_id = spacelift_stack.test.id
+				%s
+			}
+		`, randomID, lineage, push)
+	}
+
+	testSteps(t, []resource.TestStep{
+		{
+			Config:      config(fmt.Sprintf("state_file = %q", stateFile(2, lineage))),
+			ExpectError: regexp.MustCompile(`state file serial 2 is not higher than the serial 3`),
+		},
+		{
+			Config:      config(fmt.Sprintf("state_file = %q", stateFile(4, "other-lineage"))),
+			ExpectError: regexp.MustCompile(`does not match the lineage`),
+		},
+		{
+			Config: config(fmt.Sprintf("state_file = %q", stateFile(4, lineage))),
+			Check: Resource(
+				resourceName,
+				Attribute("id", StartsWith("test-stack-")),
+				Attribute("lineage", Equals(lineage)),
+				Attribute("serial", Equals("4")),
+			),
+		},
+	})
+}
+
+func TestDecodeStateFileHeader(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		state       string
+		wantLineage string
+		wantSerial  int
+		wantErr     string
+	}{
+		{
+			name:        "header first",
+			state:       `{"version":4,"serial":3,"lineage":"abc","resources":[]}`,
+			wantLineage: "abc",
+			wantSerial:  3,
+		},
+		{
+			name:        "header after resources",
+			state:       `{"resources":[{"instances":[{"attributes":{"lineage":"nested","serial":9}}]}],"outputs":{},"lineage":"abc","serial":3}`,
+			wantLineage: "abc",
+			wantSerial:  3,
+		},
+		{
+			name:        "rest of the state is not read",
+			state:       `{"version":4,"serial":3,"lineage":"abc","resources":[{`,
+			wantLineage: "abc",
+			wantSerial:  3,
+		},
+		{
+			name:    "no lineage",
+			state:   `{"version":4,"serial":3}`,
+			wantErr: "state has no lineage or serial",
+		},
+		{
+			name:    "not an object",
+			state:   `[]`,
+			wantErr: "state is not a JSON object",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			header, err := decodeStateFileHeader(json.NewDecoder(strings.NewReader(tc.state)))
+
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if header.Lineage != tc.wantLineage || header.Serial != tc.wantSerial {
+				t.Errorf("got lineage %q and serial %d, want %q and %d", header.Lineage, header.Serial, tc.wantLineage, tc.wantSerial)
+			}
+		})
+	}
+}
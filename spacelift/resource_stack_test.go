@@ -1,8 +1,10 @@
 package spacelift
 
 import (
+	"context"
 	"fmt"
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
@@ -2374,6 +2376,7 @@ func TestStackResourceSpace(t *testing.T) {
 				Check: Resource(
 					"spacelift_stack.state_import",
 					Attribute("import_state", Equals("")),
+					Attribute("import_state_checksum", Equals("44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a")),
 				),
 			},
 		})
@@ -2528,3 +2531,97 @@ func TestStackResourceInSpaceDestroy(t *testing.T) {
 		})
 	})
 }
+
+func TestStackResourceDeletionGuard(t *testing.T) {
+	const resourceName = "spacelift_stack.test"
+
+	randomID := acctest.RandStringFromCharSet(5, acctest.CharSetAlphaNum)
+
+	// A stack which has never run manages nothing, so the guard lets it go.
+	testSteps(t, []resource.TestStep{
+		{
+			Config: fmt.Sprintf(`
+				resource "spacelift_stack" "test" {
+					branch                  = "master"
+					repository              = "demo"
+					name                    = "Guarded stack %s"
+					guard_managed_resources = true
+				}
+			`, randomID),
+			Check: Resource(
+				resourceName,
+				Attribute("guard_managed_resources", Equals("true")),
+				Attribute("abandon_managed_resources", Equals("false")),
+			),
+		},
+	})
+}
+
+func TestCheckStackManagedResources(t *testing.T) {
+	entity := func(address, entityType string) map[string]string {
+		return map[string]string{"address": address, "type": entityType}
+	}
+
+	stack := func(entities ...map[string]string) map[string]any {
+		return map[string]any{"stack": map[string]any{"entities": entities}}
+	}
+
+	for _, tc := range []struct {
+		name    string
+		data    map[string]any
+		wantErr string
+	}{
+		{
+			name: "stack is gone",
+			data: map[string]any{"stack": nil},
+		},
+		{
+			name: "only outputs and data sources",
+			data: stack(entity("bucket_arn", "output"), entity("data.aws_caller_identity.current", "aws_caller_identity"), entity("module.vpc.data.aws_region.current", "aws_region")),
+		},
+		{
+			name:    "managed resources",
+			data:    stack(entity("module.vpc.aws_vpc.main", "aws_vpc"), entity("bucket_arn", "output"), entity("aws_s3_bucket.logs", "aws_s3_bucket")),
+			wantErr: "stack my-stack still manages 2 resource(s), which would be orphaned by deleting it: aws_s3_bucket.logs, module.vpc.aws_vpc.main;",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := NewGraphQLServer(t, map[string]GraphQLHandler{
+				"StackManagedResources": func(GraphQLRequest) any { return tc.data },
+			})
+
+			err := checkStackManagedResources(context.Background(), server.Client(), "my-stack")
+
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tc.wantErr != "" && err == nil:
+				t.Errorf("expected an error, got none")
+			case tc.wantErr != "" && !strings.Contains(err.Error(), tc.wantErr):
+				t.Errorf("error %q does not contain %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckStackManagedResourcesTruncatesList(t *testing.T) {
+	var entities []map[string]string
+	for i := range stackManagedResourcesListed + 5 {
+		entities = append(entities, map[string]string{"address": fmt.Sprintf("null_resource.r%02d", i), "type": "null_resource"})
+	}
+
+	server := NewGraphQLServer(t, map[string]GraphQLHandler{
+		"StackManagedResources": func(GraphQLRequest) any {
+			return map[string]any{"stack": map[string]any{"entities": entities}}
+		},
+	})
+
+	err := checkStackManagedResources(context.Background(), server.Client(), "my-stack")
+	if err == nil {
+		t.Fatal("expected an error, got none")
+	}
+
+	if message := err.Error(); !strings.Contains(message, "null_resource.r19 and 5 more;") || strings.Contains(message, "null_resource.r20") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -15,8 +15,10 @@ import (
 )
 
 func resourceTask() *schema.Resource {
-	return &schema.Resource{
-		Description: "`spacelift_task` represents a task in Spacelift.",
+	taskResource := &schema.Resource{
+		Description: "" +
+			"`spacelift_task` represents a task in Spacelift. On Terraform 1.14 and later, " +
+			"consider the stateless `spacelift_run_task` action instead.",
 
 		CreateContext: resourceTaskCreate,
 		ReadContext:   schema.NoopContext,
@@ -93,8 +95,19 @@ func resourceTask() *schema.Resource {
 					},
 				},
 			},
+			"commit_sha": {
+				Type:        schema.TypeString,
+				Description: "SHA of the commit the task ran on",
+				Computed:    true,
+			},
 		},
 	}
+
+	for name, attribute := range runOutcomeSchema() {
+		taskResource.Schema[name] = attribute
+	}
+
+	return taskResource
 }
 
 func resourceTaskCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
@@ -118,14 +131,17 @@ func resourceTaskCreate(ctx context.Context, d *schema.ResourceData, meta any) d
 		return diag.Errorf("could not create task: %v", internal.FromSpaceliftError(err))
 	}
 
+	runID := mutation.CreateTask.ID.(string)
+
+	// The task exists whatever happens next, so keep track of it even if
+	// waiting for it fails.
+	d.SetId(fmt.Sprintf("%s/%s", task.StackID, runID))
+
+	var diags diag.Diagnostics
 	if waitRaw, ok := d.GetOk("wait"); ok {
 		wait := structs.NewWaitConfiguration(waitRaw.([]any))
-		if d := wait.Wait(ctx, d, client, task.StackID, mutation.CreateTask.ID.(string)); len(d) > 0 {
-			return d
-		}
+		diags = wait.Wait(ctx, client, task.StackID, runID, d.Timeout(schema.TimeoutCreate))
 	}
 
-	d.SetId(fmt.Sprintf("%s/%s", d.Get("stack_id"), mutation.CreateTask.ID))
-
-	return nil
+	return append(diags, setRunOutcome(ctx, d, client, task.StackID, runID, true)...)
 }
@@ -16,7 +16,8 @@ func resourceVersion() *schema.Resource {
 	return &schema.Resource{
 		Description: "" +
 			"`spacelift_version` allows to programmatically trigger a module version creation " +
-			"in response to arbitrary changes in the keepers section.",
+			"in response to arbitrary changes in the keepers section. On Terraform 1.14 " +
+			"and later, consider the stateless `spacelift_publish_module_version` action instead.",
 
 		CreateContext: resourceVersionCreate,
 		ReadContext:   schema.NoopContext,
@@ -13,7 +13,7 @@ import (
 
 func resourceWorkerPoolRecycle() *schema.Resource {
 	return &schema.Resource{
-		Description: "`spacelift_worker_pool_recycle` represents a worker pool recycle operation in Spacelift. This resource triggers a recycle of all workers in the specified worker pool, causing them to be replaced with fresh instances.",
+		Description: "`spacelift_worker_pool_recycle` represents a worker pool recycle operation in Spacelift. This resource triggers a recycle of all workers in the specified worker pool, causing them to be replaced with fresh instances. On Terraform 1.14 and later, consider the stateless `spacelift_recycle_worker_pool` action instead.",
 
 		CreateContext: resourceWorkerPoolRecycleCreate,
 		ReadContext:   schema.NoopContext,
@@ -0,0 +1,242 @@
+package spacelift
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/pkg/errors"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs"
+)
+
+func autoConfirmSchema() *schema.Schema {
+	return &schema.Schema{
+		Type: schema.TypeList,
+		Description: "" +
+			"Confirm the run once it has planned, but only if the planned " +
+			"changes pass every rule. If any rule fails, the run is discarded " +
+			"and the failing rules are reported. Only tracked runs can be " +
+			"confirmed. The run is always waited for until it has planned, " +
+			"whether or not `wait` is set.",
+		Optional: true,
+		ForceNew: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"allow_deletions": {
+					Type:        schema.TypeBool,
+					Description: "Whether the run may delete resources. Defaults to `false`.",
+					Optional:    true,
+					Default:     false,
+				},
+				"allow_replacements": {
+					Type:        schema.TypeBool,
+					Description: "Whether the run may replace resources. Defaults to `false`.",
+					Optional:    true,
+					Default:     false,
+				},
+				"max_changes": {
+					Type:         schema.TypeInt,
+					Description:  "Maximum number of resources the run may change in any way, e.g. create, update, delete, replace or import. Unlimited if not set.",
+					Optional:     true,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"allowed_resource_types": {
+					Type:        schema.TypeSet,
+					Description: "Resource types the run may change, e.g. `aws_s3_bucket`. Any resource type if not set.",
+					Optional:    true,
+					Elem:        &schema.Schema{Type: schema.TypeString},
+				},
+			},
+		},
+	}
+}
+
+// autoConfirmRules are the conditions planned changes must meet for a run to
+// be confirmed without a human.
+type autoConfirmRules struct {
+	allowDeletions       bool
+	allowReplacements    bool
+	maxChanges           *int
+	allowedResourceTypes []string
+}
+
+func newAutoConfirmRules(d *schema.ResourceData) *autoConfirmRules {
+	input, ok := d.Get("auto_confirm").([]any)
+	if !ok || len(input) == 0 {
+		return nil
+	}
+
+	// The block may be empty, in which case all the rules take their
+	// defaults.
+	raw, _ := input[0].(map[string]any)
+
+	rules := &autoConfirmRules{}
+	rules.allowDeletions, _ = raw["allow_deletions"].(bool)
+	rules.allowReplacements, _ = raw["allow_replacements"].(bool)
+
+	// An unset max_changes reads as 0, which is a valid limit of its own.
+	if rawBlock := d.GetRawConfig().GetAttr("auto_confirm"); rawBlock.IsKnown() && !rawBlock.IsNull() && rawBlock.LengthInt() > 0 {
+		if !rawBlock.Index(cty.NumberIntVal(0)).GetAttr("max_changes").IsNull() {
+			maxChanges := raw["max_changes"].(int)
+			rules.maxChanges = &maxChanges
+		}
+	}
+
+	if types, ok := raw["allowed_resource_types"].(*schema.Set); ok {
+		for _, resourceType := range types.List() {
+			rules.allowedResourceTypes = append(rules.allowedResourceTypes, resourceType.(string))
+		}
+		slices.Sort(rules.allowedResourceTypes)
+	}
+
+	return rules
+}
+
+// unchangedRunChangeTypes are the change types of resources a run leaves
+// as they are. Any other type, including those yet to be added to
+// Spacelift, counts as a change, so that the rules never let an unknown
+// kind of change through.
+var unchangedRunChangeTypes = []string{"NOOP", "READ"}
+
+// check returns a description of every rule the planned changes break.
+func (rules *autoConfirmRules) check(changes []structs.RunResourceChange) []string {
+	var changed, deleted, replaced, disallowed []string
+
+	for _, change := range changes {
+		if slices.Contains(unchangedRunChangeTypes, change.Metadata.Type) {
+			continue
+		}
+
+		switch action, replace := runChangeAction(change.Metadata.Type); {
+		case replace:
+			replaced = append(replaced, change.Address)
+		case action == "delete":
+			deleted = append(deleted, change.Address)
+		}
+		changed = append(changed, change.Address)
+
+		if len(rules.allowedResourceTypes) > 0 && !slices.Contains(rules.allowedResourceTypes, resourceTypeFromAddress(change.Address)) {
+			disallowed = append(disallowed, change.Address)
+		}
+	}
+
+	var failures []string
+
+	if !rules.allowDeletions && len(deleted) > 0 {
+		failures = append(failures, fmt.Sprintf("allow_deletions: the run deletes %s", strings.Join(deleted, ", ")))
+	}
+
+	if !rules.allowReplacements && len(replaced) > 0 {
+		failures = append(failures, fmt.Sprintf("allow_replacements: the run replaces %s", strings.Join(replaced, ", ")))
+	}
+
+	if rules.maxChanges != nil && len(changed) > *rules.maxChanges {
+		failures = append(failures, fmt.Sprintf("max_changes: the run changes %d resources, at most %d are allowed", len(changed), *rules.maxChanges))
+	}
+
+	if len(disallowed) > 0 {
+		failures = append(failures, fmt.Sprintf("allowed_resource_types: the run changes %s, which are not of the types %s", strings.Join(disallowed, ", "), strings.Join(rules.allowedResourceTypes, ", ")))
+	}
+
+	return failures
+}
+
+// resourceTypeFromAddress extracts the resource type out of a resource
+// address such as module.network["eu"].aws_subnet.private[0].
+func resourceTypeFromAddress(address string) string {
+	var parts []string
+	var current strings.Builder
+	depth, quoted := 0, false
+
+	for _, r := range address {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case quoted:
+		case r == '[':
+			depth++
+		case r == ']':
+			depth--
+		case r == '.' && depth == 0:
+			parts = append(parts, current.String())
+			current.Reset()
+			continue
+		}
+		current.WriteRune(r)
+	}
+	parts = append(parts, current.String())
+
+	for i := 0; i < len(parts); i++ {
+		switch parts[i] {
+		case "module":
+			i++
+		case "data":
+		default:
+			return parts[i]
+		}
+	}
+
+	return ""
+}
+
+// autoConfirmRun confirms a run which is waiting for confirmation if its
+// planned changes pass the rules, and discards it otherwise. Runs which did
+// not stop for confirmation, e.g. because they had nothing to change, are
+// left alone.
+func autoConfirmRun(ctx context.Context, client *internal.Client, stackID, runID string, rules *autoConfirmRules) diag.Diagnostics {
+	run, err := getRunChanges(ctx, client, stackID, runID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if run == nil {
+		return diag.Errorf("run %s not found on stack %s", runID, stackID)
+	}
+
+	if run.State != "UNCONFIRMED" {
+		return nil
+	}
+
+	variables := map[string]any{
+		"stack": toID(stackID),
+		"run":   toID(runID),
+	}
+
+	if failures := rules.check(run.Changes); len(failures) > 0 {
+		var mutation struct {
+			RunDiscard structs.Run `graphql:"runDiscard(stack: $stack, run: $run)"`
+		}
+
+		detail := "Failed rules:\n  - " + strings.Join(failures, "\n  - ")
+
+		summary := fmt.Sprintf("run %s on stack %s was discarded because its planned changes break the auto_confirm rules", runID, stackID)
+		if err := client.Mutate(ctx, "RunDiscard", &mutation, variables); err != nil {
+			summary = fmt.Sprintf("run %s on stack %s breaks the auto_confirm rules and could not be discarded, so it is still waiting for confirmation", runID, stackID)
+			detail += fmt.Sprintf("\n\nCould not discard the run: %v", internal.FromSpaceliftError(err))
+		}
+
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  summary,
+			Detail:   detail,
+		}}
+	}
+
+	var mutation struct {
+		RunConfirm structs.Run `graphql:"runConfirm(stack: $stack, run: $run)"`
+	}
+
+	if err := client.Mutate(ctx, "RunConfirm", &mutation, variables); err != nil {
+		return diag.FromErr(errors.Wrapf(internal.FromSpaceliftError(err), "could not confirm run %s on stack %s", runID, stackID))
+	}
+
+	return nil
+}
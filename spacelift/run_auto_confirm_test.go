@@ -0,0 +1,149 @@
+package spacelift
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs"
+	. "github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/testhelpers"
+)
+
+func TestResourceTypeFromAddress(t *testing.T) {
+	for address, want := range map[string]string{
+		"aws_s3_bucket.logs":                          "aws_s3_bucket",
+		"aws_subnet.private[0]":                       "aws_subnet",
+		`module.network["eu.west"].aws_subnet.public`: "aws_subnet",
+		"module.a.module.b[1].random_pet.name":        "random_pet",
+		"module.a.data.aws_region.current":            "aws_region",
+		`aws_iam_role.ci["a.b"]`:                      "aws_iam_role",
+	} {
+		if got := resourceTypeFromAddress(address); got != want {
+			t.Errorf("%s: got %q, want %q", address, got, want)
+		}
+	}
+}
+
+func runChangesOf(changes map[string]string) []structs.RunResourceChange {
+	var out []structs.RunResourceChange
+	for address, changeType := range changes {
+		change := structs.RunResourceChange{Address: address}
+		change.Metadata.Type = changeType
+		out = append(out, change)
+	}
+	return out
+}
+
+func TestAutoConfirmRulesCheck(t *testing.T) {
+	two := 2
+
+	changes := runChangesOf(map[string]string{
+		"aws_s3_bucket.logs":   "ADDED",
+		"aws_iam_role.ci":      "CHANGED",
+		"random_pet.name":      "REPLACE_DESTROY_BEFORE_CREATE",
+		"aws_sqs_queue.jobs":   "DELETED",
+		"aws_s3_bucket.legacy": "IMPORT",
+		"data.aws_region.here": "READ",
+		"aws_s3_bucket.static": "NOOP",
+	})
+
+	for _, tc := range []struct {
+		name  string
+		rules autoConfirmRules
+		want  []string
+	}{
+		{
+			name:  "defaults",
+			rules: autoConfirmRules{},
+			want:  []string{"allow_deletions: the run deletes aws_sqs_queue.jobs", "allow_replacements: the run replaces random_pet.name"},
+		},
+		{
+			name:  "everything allowed",
+			rules: autoConfirmRules{allowDeletions: true, allowReplacements: true},
+		},
+		{
+			name:  "too many changes",
+			rules: autoConfirmRules{allowDeletions: true, allowReplacements: true, maxChanges: &two},
+			want:  []string{"max_changes: the run changes 5 resources, at most 2 are allowed"},
+		},
+		{
+			name:  "resource types",
+			rules: autoConfirmRules{allowDeletions: true, allowReplacements: true, allowedResourceTypes: []string{"aws_iam_role", "aws_s3_bucket", "aws_sqs_queue"}},
+			want:  []string{"allowed_resource_types: the run changes random_pet.name, which are not of the types aws_iam_role, aws_s3_bucket, aws_sqs_queue"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.rules.check(changes)
+			if strings.Join(got, "\n") != strings.Join(tc.want, "\n") {
+				t.Errorf("got failures %q, want %q", got, tc.want)
+			}
+		})
+	}
+
+	// Change types the provider doesn't know of are held to the rules all
+	// the same.
+	zero := 0
+	rules := autoConfirmRules{maxChanges: &zero, allowedResourceTypes: []string{"aws_s3_bucket"}}
+	got := rules.check(runChangesOf(map[string]string{"aws_kms_key.main": "FORGET"}))
+	want := []string{
+		"max_changes: the run changes 1 resources, at most 0 are allowed",
+		"allowed_resource_types: the run changes aws_kms_key.main, which are not of the types aws_s3_bucket",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got failures %q for an unknown change type, want %q", got, want)
+	}
+}
+
+func TestAutoConfirmRun(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		state        string
+		changeType   string
+		wantMutation string
+		wantErr      string
+	}{
+		{name: "safe plan", state: "UNCONFIRMED", changeType: "ADDED", wantMutation: "RunConfirm"},
+		{name: "unsafe plan", state: "UNCONFIRMED", changeType: "DELETED", wantMutation: "RunDiscard", wantErr: "was discarded because its planned changes break the auto_confirm rules"},
+		{name: "nothing to confirm", state: "FINISHED", changeType: "DELETED"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := NewGraphQLServer(t, map[string]GraphQLHandler{
+				"RunChangesRead": func(GraphQLRequest) any {
+					return map[string]any{"stack": map[string]any{"run": map[string]any{
+						"state":   tc.state,
+						"delta":   nil,
+						"changes": []any{runChange("aws_sqs_queue.jobs", tc.changeType)},
+					}}}
+				},
+				"RunConfirm": func(GraphQLRequest) any {
+					return map[string]any{"runConfirm": map[string]any{"id": "run-id"}}
+				},
+				"RunDiscard": func(GraphQLRequest) any {
+					return map[string]any{"runDiscard": map[string]any{"id": "run-id"}}
+				},
+			})
+
+			diags := autoConfirmRun(context.Background(), server.Client(), "stack-id", "run-id", &autoConfirmRules{})
+
+			var mutations []string
+			for _, request := range server.Requests("") {
+				if request.Operation != "RunChangesRead" {
+					mutations = append(mutations, request.Operation)
+				}
+			}
+
+			if got := strings.Join(mutations, ","); got != tc.wantMutation {
+				t.Errorf("got mutations %q, want %q", got, tc.wantMutation)
+			}
+
+			switch {
+			case tc.wantErr == "" && diags.HasError():
+				t.Errorf("unexpected diagnostics: %v", diags)
+			case tc.wantErr != "" && (!diags.HasError() || !strings.Contains(diags[0].Summary, tc.wantErr)):
+				t.Errorf("expected error %q, got %v", tc.wantErr, diags)
+			case tc.wantErr != "" && !strings.Contains(diags[0].Detail, "allow_deletions: the run deletes aws_sqs_queue.jobs"):
+				t.Errorf("failed rule not named: %q", diags[0].Detail)
+			}
+		})
+	}
+}
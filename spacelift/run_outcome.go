@@ -0,0 +1,174 @@
+package spacelift
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/graphql"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs"
+)
+
+// runOutcomeSchema describes the outcome of a run triggered by a resource.
+// The outcome is recorded once the resource is created, after waiting for the
+// run if a wait is configured, and is not refreshed afterwards.
+func runOutcomeSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"state": {
+			Type:        schema.TypeString,
+			Description: "State of the run once the resource was created, e.g. `finished`",
+			Computed:    true,
+		},
+		"url": {
+			Type:        schema.TypeString,
+			Description: "URL of the run in Spacelift",
+			Computed:    true,
+		},
+		"created_at": {
+			Type:        schema.TypeInt,
+			Description: "Unix timestamp when the run was created",
+			Computed:    true,
+		},
+		"updated_at": {
+			Type:        schema.TypeInt,
+			Description: "Unix timestamp when the run was last updated, as of when the resource was created",
+			Computed:    true,
+		},
+		"delta_added": {
+			Type:        schema.TypeInt,
+			Description: "Number of resources the run adds",
+			Computed:    true,
+		},
+		"delta_changed": {
+			Type:        schema.TypeInt,
+			Description: "Number of resources the run changes",
+			Computed:    true,
+		},
+		"delta_deleted": {
+			Type:        schema.TypeInt,
+			Description: "Number of resources the run deletes",
+			Computed:    true,
+		},
+	}
+}
+
+// runWaitSchema describes waiting for a run triggered or reviewed by a
+// resource to finish.
+func runWaitSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		Description: description,
+		MaxItems:    1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"disabled": {
+					Type:        schema.TypeBool,
+					Description: "Whether waiting for the run is disabled or not. Default: `false`",
+					Optional:    true,
+					Default:     false,
+				},
+				"continue_on_state": {
+					Type: schema.TypeSet,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+					Description: "Continue on the specified states of a finished run. If not specified, the default is `[ 'finished' ]`. You can use following states: `applying`, `canceled`, `confirmed`, `destroying`, `discarded`, `failed`, `finished`, `initializing`, `pending_review`, `performing`, `planning`, `preparing_apply`, `preparing_replan`, `preparing`, `queued`, `ready`, `replan_requested`, `skipped`, `stopped`, `unconfirmed`.",
+					Optional:    true,
+				},
+				"continue_on_timeout": {
+					Type:        schema.TypeBool,
+					Description: "Continue if run timed out, i.e. did not reach any defined end state in time. Default: `false`",
+					Optional:    true,
+					Default:     false,
+				},
+			},
+		},
+	}
+}
+
+// getStackRun returns a run of a stack, or nil if it does not exist.
+func getStackRun(ctx context.Context, client *internal.Client, stackID, runID string) (*structs.StackRun, error) {
+	var query struct {
+		Stack *struct {
+			Run *structs.StackRun `graphql:"run(id: $runId)"`
+		} `graphql:"stack(id: $stackId)"`
+	}
+
+	variables := map[string]any{
+		"stackId": graphql.ID(stackID),
+		"runId":   graphql.ID(runID),
+	}
+
+	if err := client.Query(ctx, "StackRunOutcomeRead", &query, variables); err != nil {
+		return nil, errors.Wrapf(err, "could not query for run %s", runID)
+	}
+
+	if query.Stack == nil {
+		return nil, nil
+	}
+
+	return query.Stack.Run, nil
+}
+
+// runOutcomeTimeout bounds the query for the outcome of a run, which runs
+// even if the resource ran out of time waiting for the run.
+const runOutcomeTimeout = 30 * time.Second
+
+// setRunOutcome records the outcome of a run on the resource which triggered
+// it, including the commit the run ran on if setCommitSHA is set. A configured
+// commit_sha may name the same commit differently, e.g. as a short SHA, so it
+// is never overwritten. Failures to look the run up are reported as warnings,
+// since the run itself went ahead.
+func setRunOutcome(ctx context.Context, d *schema.ResourceData, client *internal.Client, stackID, runID string, setCommitSHA bool) diag.Diagnostics {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), runOutcomeTimeout)
+	defer cancel()
+
+	if err := d.Set("url", fmt.Sprintf("%s/stack/%s/run/%s", strings.TrimSuffix(client.Endpoint, "/"), stackID, runID)); err != nil {
+		return diag.Errorf("could not set url: %v", err)
+	}
+
+	run, err := getStackRun(ctx, client, stackID, runID)
+	if err == nil && run == nil {
+		err = errors.Errorf("run %s not found on stack %s", runID, stackID)
+	}
+	if err != nil {
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  "Could not record the outcome of the run",
+			Detail:   err.Error(),
+		}}
+	}
+
+	var delta structs.RunDelta
+	if run.Delta != nil {
+		delta = *run.Delta
+	}
+
+	outcome := map[string]any{
+		"state":         strings.ToLower(run.State),
+		"created_at":    run.CreatedAt,
+		"updated_at":    run.UpdatedAt,
+		"delta_added":   delta.AddCount,
+		"delta_changed": delta.ChangeCount,
+		"delta_deleted": delta.DeleteCount,
+	}
+
+	if setCommitSHA {
+		outcome["commit_sha"] = run.Commit.Hash
+	}
+
+	for key, value := range outcome {
+		if err := d.Set(key, value); err != nil {
+			return diag.Errorf("could not set %s: %v", key, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package spacelift
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	. "github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/testhelpers"
+)
+
+func TestSetRunOutcome(t *testing.T) {
+	finishedRun := map[string]any{
+		"id":        "run-id",
+		"type":      "TASK",
+		"state":     "FINISHED",
+		"finished":  true,
+		"commit":    map[string]any{"hash": "abc123"},
+		"delta":     map[string]any{"addCount": 1, "changeCount": 2, "deleteCount": 3},
+		"createdAt": 100,
+		"updatedAt": 200,
+	}
+
+	taskData := func(t *testing.T) *schema.ResourceData {
+		return schema.TestResourceDataRaw(t, resourceTask().Schema, map[string]any{
+			"stack_id": "stack-id",
+			"command":  "terraform state list",
+		})
+	}
+
+	reviewData := func(t *testing.T) *schema.ResourceData {
+		return schema.TestResourceDataRaw(t, resourceRunReview().Schema, map[string]any{
+			"stack_id": "stack-id",
+			"run_id":   "run-id",
+			"decision": "APPROVE",
+		})
+	}
+
+	for _, tc := range []struct {
+		name         string
+		data         func(t *testing.T) *schema.ResourceData
+		setCommitSHA bool
+		run          map[string]any
+		wantWarning  bool
+		want         map[string]any
+	}{
+		{
+			name:         "finished run",
+			data:         taskData,
+			setCommitSHA: true,
+			run:          finishedRun,
+			want: map[string]any{
+				"state":         "finished",
+				"commit_sha":    "abc123",
+				"created_at":    100,
+				"updated_at":    200,
+				"delta_added":   1,
+				"delta_changed": 2,
+				"delta_deleted": 3,
+				"url":           "/stack/stack-id/run/run-id",
+			},
+		},
+		{
+			name: "resource without a commit",
+			data: reviewData,
+			run:  finishedRun,
+			want: map[string]any{
+				"state":       "finished",
+				"delta_added": 1,
+				"url":         "/stack/stack-id/run/run-id",
+			},
+		},
+		{
+			name:         "missing run",
+			data:         taskData,
+			setCommitSHA: true,
+			wantWarning:  true,
+			want: map[string]any{
+				"state": "",
+				"url":   "/stack/stack-id/run/run-id",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := NewGraphQLServer(t, map[string]GraphQLHandler{
+				"StackRunOutcomeRead": func(GraphQLRequest) any {
+					return map[string]any{"stack": map[string]any{"run": tc.run}}
+				},
+			})
+
+			d := tc.data(t)
+
+			diags := setRunOutcome(context.Background(), d, server.Client(), "stack-id", "run-id", tc.setCommitSHA)
+
+			if gotWarning := len(diags) == 1 && diags[0].Severity == diag.Warning; gotWarning != tc.wantWarning || diags.HasError() {
+				t.Errorf("unexpected diagnostics: %v", diags)
+			}
+
+			for key, want := range tc.want {
+				got := d.Get(key)
+				if key == "url" {
+					want = server.URL() + want.(string)
+				}
+				if got != want {
+					t.Errorf("%s = %v, want %v", key, got, want)
+				}
+			}
+		})
+	}
+}
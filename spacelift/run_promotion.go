@@ -0,0 +1,91 @@
+package spacelift
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/shurcooL/graphql"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs"
+)
+
+// checkRunPromotable makes sure a run can be promoted: it has to be a finished
+// proposed run of the stack, and the stack's branch must still point at the
+// commit the run planned, or the tracked run would apply something else than
+// what was previewed.
+func checkRunPromotable(ctx context.Context, client *internal.Client, stackID, runID string) error {
+	var query struct {
+		Stack *struct {
+			GitHubActionDeploy bool `graphql:"githubActionDeploy"`
+			TrackedCommit      *struct {
+				Hash string `graphql:"hash"`
+			} `graphql:"trackedCommit"`
+			Run *struct {
+				Type   string            `graphql:"type"`
+				State  string            `graphql:"state"`
+				Commit structs.RunCommit `graphql:"commit"`
+			} `graphql:"run(id: $runId)"`
+		} `graphql:"stack(id: $stackId)"`
+	}
+
+	variables := map[string]any{
+		"stackId": graphql.ID(stackID),
+		"runId":   graphql.ID(runID),
+	}
+
+	if err := client.Query(ctx, "RunPromotionCheck", &query, variables); err != nil {
+		return errors.Wrapf(err, "could not query for run %s", runID)
+	}
+
+	if query.Stack == nil {
+		return errors.Errorf("stack %s not found", stackID)
+	}
+
+	run := query.Stack.Run
+	if run == nil {
+		return errors.Errorf("run %s not found on stack %s, only runs of the same stack can be promoted", runID, stackID)
+	}
+
+	if !query.Stack.GitHubActionDeploy {
+		return errors.Errorf("stack %s does not allow run promotion, see allow_run_promotion", stackID)
+	}
+
+	if run.Type != string(structs.RunTypeProposed) {
+		return errors.Errorf("run %s is a %s run, only proposed runs can be promoted", runID, strings.ToLower(run.Type))
+	}
+
+	if run.State != "FINISHED" {
+		return errors.Errorf("run %s has not finished (it is %s), only finished runs can be promoted", runID, strings.ToLower(run.State))
+	}
+
+	if tracked := query.Stack.TrackedCommit; tracked != nil && tracked.Hash != run.Commit.Hash {
+		return errors.Errorf("stack %s has moved on from commit %s of run %s to %s, so the run no longer previews what would be applied", stackID, run.Commit.Hash, runID, tracked.Hash)
+	}
+
+	return nil
+}
+
+// promoteRun promotes a proposed run to a tracked run, and returns the ID of
+// the tracked run.
+func promoteRun(ctx context.Context, client *internal.Client, stackID, runID string) (string, error) {
+	if err := checkRunPromotable(ctx, client, stackID, runID); err != nil {
+		return "", errors.Wrapf(err, "could not promote run %s", runID)
+	}
+
+	var mutation struct {
+		PromoteRun structs.Run `graphql:"runPromote(stack: $stack, run: $run)"`
+	}
+
+	variables := map[string]any{
+		"stack": toID(stackID),
+		"run":   toID(runID),
+	}
+
+	if err := client.Mutate(ctx, "RunPromote", &mutation, variables); err != nil {
+		return "", errors.Errorf("could not promote run %s on stack %s: %v", runID, stackID, internal.FromSpaceliftError(err))
+	}
+
+	return mutation.PromoteRun.ID, nil
+}
@@ -0,0 +1,120 @@
+package spacelift
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	. "github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/testhelpers"
+)
+
+// fakeRunPromotionServer serves the stack a run is promoted on, and promotes
+// it to the run promoted-run-id.
+func fakeRunPromotionServer(t *testing.T, stack map[string]any) *GraphQLServer {
+	return NewGraphQLServer(t, map[string]GraphQLHandler{
+		"RunPromotionCheck": func(GraphQLRequest) any {
+			return map[string]any{"stack": stack}
+		},
+		"RunPromote": func(GraphQLRequest) any {
+			return map[string]any{"runPromote": map[string]any{"id": "promoted-run-id"}}
+		},
+		"StackRunOutcomeRead": func(GraphQLRequest) any {
+			return map[string]any{"stack": map[string]any{"run": map[string]any{
+				"id":     "promoted-run-id",
+				"type":   "TRACKED",
+				"state":  "QUEUED",
+				"commit": map[string]any{"hash": "abc123"},
+			}}}
+		},
+	})
+}
+
+// promotionStack is a stack as seen when checking whether its run can be
+// promoted. A nil run is a run which is not found on the stack.
+func promotionStack(allowed bool, trackedCommit string, run map[string]any) map[string]any {
+	return map[string]any{
+		"githubActionDeploy": allowed,
+		"trackedCommit":      map[string]any{"hash": trackedCommit},
+		"run":                run,
+	}
+}
+
+func promotionRun(runType, state, commit string) map[string]any {
+	return map[string]any{"type": runType, "state": state, "commit": map[string]any{"hash": commit}}
+}
+
+func TestRunResourcePromotion(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		stack   map[string]any
+		wantErr string
+	}{
+		{
+			name:  "finished proposed run",
+			stack: promotionStack(true, "abc123", promotionRun("PROPOSED", "FINISHED", "abc123")),
+		},
+		{
+			name:    "run of another stack",
+			stack:   promotionStack(true, "abc123", nil),
+			wantErr: "run proposed-run-id not found on stack stack-id, only runs of the same stack can be promoted",
+		},
+		{
+			name:    "promotion not allowed",
+			stack:   promotionStack(false, "abc123", promotionRun("PROPOSED", "FINISHED", "abc123")),
+			wantErr: "does not allow run promotion",
+		},
+		{
+			name:    "tracked run",
+			stack:   promotionStack(true, "abc123", promotionRun("TRACKED", "FINISHED", "abc123")),
+			wantErr: "is a tracked run, only proposed runs can be promoted",
+		},
+		{
+			name:    "unfinished run",
+			stack:   promotionStack(true, "abc123", promotionRun("PROPOSED", "PLANNING", "abc123")),
+			wantErr: "has not finished (it is planning)",
+		},
+		{
+			name:    "commit moved",
+			stack:   promotionStack(true, "def456", promotionRun("PROPOSED", "FINISHED", "abc123")),
+			wantErr: "has moved on from commit abc123 of run proposed-run-id to def456",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := fakeRunPromotionServer(t, tc.stack)
+
+			d := schema.TestResourceDataRaw(t, resourceRun().Schema, map[string]any{
+				"stack_id":       "stack-id",
+				"promote_run_id": "proposed-run-id",
+			})
+
+			diags := resourceRunCreate(context.Background(), d, server.Client())
+
+			promoted := len(server.Requests("RunPromote")) > 0
+
+			if tc.wantErr != "" {
+				if !diags.HasError() || !strings.Contains(diags[0].Summary, tc.wantErr) {
+					t.Fatalf("expected error %q, got %v", tc.wantErr, diags)
+				}
+
+				if promoted || d.Id() != "" {
+					t.Error("run was promoted despite failing the checks")
+				}
+				return
+			}
+
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+
+			if !promoted || d.Id() != "promoted-run-id" {
+				t.Errorf("got ID %q, want promoted-run-id", d.Id())
+			}
+
+			if got := d.Get("state"); got != "queued" {
+				t.Errorf("got state %v, want queued", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package spacelift
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/pkg/errors"
+	"github.com/shurcooL/graphql"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs"
+)
+
+// stackUpstream is a dependency of a stack on an upstream stack, with the
+// references passing the outputs of the upstream stack on.
+type stackUpstream struct {
+	// id is empty if the dependency is yet to be created.
+	id string
+
+	// references are keyed by the name of the input.
+	references map[string]stackUpstreamReference
+}
+
+type stackUpstreamReference struct {
+	// id is empty if the reference is yet to be created.
+	id            string
+	outputName    string
+	inputName     string
+	triggerAlways bool
+}
+
+// stackDependenciesChanges is what it takes to turn the upstream dependencies
+// a stack has into those it should have.
+type stackDependenciesChanges struct {
+	// removeDependencies and addDependencies hold the IDs of the upstream
+	// stacks, sorted.
+	removeDependencies []string
+	addDependencies    []string
+
+	// References are keyed by the ID of the upstream stack. Those of
+	// removed dependencies go away with them, so they are not listed.
+	removeReferences map[string][]stackUpstreamReference
+	updateReferences map[string][]stackUpstreamReference
+	addReferences    map[string][]stackUpstreamReference
+}
+
+// diffStackDependencies compares the upstream dependencies a stack has with
+// those it should have, both keyed by the ID of the upstream stack.
+func diffStackDependencies(current, desired map[string]stackUpstream) stackDependenciesChanges {
+	changes := stackDependenciesChanges{
+		removeReferences: map[string][]stackUpstreamReference{},
+		updateReferences: map[string][]stackUpstreamReference{},
+		addReferences:    map[string][]stackUpstreamReference{},
+	}
+
+	for _, upstreamID := range slices.Sorted(maps.Keys(current)) {
+		if _, ok := desired[upstreamID]; !ok {
+			changes.removeDependencies = append(changes.removeDependencies, upstreamID)
+		}
+	}
+
+	for _, upstreamID := range slices.Sorted(maps.Keys(desired)) {
+		existing, ok := current[upstreamID]
+		if !ok {
+			changes.addDependencies = append(changes.addDependencies, upstreamID)
+		}
+
+		for _, inputName := range slices.Sorted(maps.Keys(existing.references)) {
+			if _, ok := desired[upstreamID].references[inputName]; !ok {
+				changes.removeReferences[upstreamID] = append(changes.removeReferences[upstreamID], existing.references[inputName])
+			}
+		}
+
+		for _, inputName := range slices.Sorted(maps.Keys(desired[upstreamID].references)) {
+			reference := desired[upstreamID].references[inputName]
+
+			existingReference, ok := existing.references[inputName]
+			switch {
+			case !ok:
+				changes.addReferences[upstreamID] = append(changes.addReferences[upstreamID], reference)
+			case existingReference.outputName != reference.outputName || existingReference.triggerAlways != reference.triggerAlways:
+				reference.id = existingReference.id
+				changes.updateReferences[upstreamID] = append(changes.updateReferences[upstreamID], reference)
+			}
+		}
+	}
+
+	return changes
+}
+
+// getStackUpstreams returns the upstream dependencies of a stack, or nil if
+// the stack does not exist.
+func getStackUpstreams(ctx context.Context, client *internal.Client, stackID string) (map[string]stackUpstream, error) {
+	var query struct {
+		Stack *struct {
+			DependsOn []struct {
+				ID             string `graphql:"id"`
+				DependsOnStack struct {
+					ID string `graphql:"id"`
+				} `graphql:"dependsOnStack"`
+				References []structs.StackDependencyReference `graphql:"references"`
+			} `graphql:"dependsOn"`
+		} `graphql:"stack(id: $stackId)"`
+	}
+
+	if err := client.Query(ctx, "StackDependenciesRead", &query, map[string]any{"stackId": toID(stackID)}); err != nil {
+		return nil, errors.Wrapf(err, "could not query for dependencies of stack %s", stackID)
+	}
+
+	if query.Stack == nil {
+		return nil, nil
+	}
+
+	upstreams := map[string]stackUpstream{}
+	for _, dependency := range query.Stack.DependsOn {
+		upstream := stackUpstream{id: dependency.ID, references: map[string]stackUpstreamReference{}}
+
+		for _, reference := range dependency.References {
+			upstream.references[reference.InputName] = stackUpstreamReference{
+				id:            reference.ID,
+				outputName:    reference.OutputName,
+				inputName:     reference.InputName,
+				triggerAlways: bool(reference.TriggerAlways),
+			}
+		}
+
+		upstreams[dependency.DependsOnStack.ID] = upstream
+	}
+
+	return upstreams, nil
+}
+
+// setStackUpstreams makes the upstream dependencies of a stack exactly the
+// desired ones, removing any others. Changes are made against what the stack
+// has at the moment, so that a partially applied change is completed on the
+// next run.
+func setStackUpstreams(ctx context.Context, client *internal.Client, stackID string, desired map[string]stackUpstream) error {
+	current, err := getStackUpstreams(ctx, client, stackID)
+	if err != nil {
+		return err
+	}
+
+	if current == nil {
+		return errors.Errorf("stack %s not found", stackID)
+	}
+
+	changes := diffStackDependencies(current, desired)
+
+	// Whatever goes away goes first, so that an input can move from one
+	// upstream stack to another.
+	for _, upstreamID := range changes.removeDependencies {
+		var mutation struct {
+			StackDependency *structs.StackDependency `graphql:"stackDependencyDelete(id: $id)"`
+		}
+
+		if err := client.Mutate(ctx, "StackDependencyDelete", &mutation, map[string]any{"id": graphql.ID(current[upstreamID].id)}); err != nil {
+			return errors.Wrapf(err, "could not remove dependency of stack %s on stack %s", stackID, upstreamID)
+		}
+	}
+
+	for _, upstreamID := range slices.Sorted(maps.Keys(changes.removeReferences)) {
+		for _, reference := range changes.removeReferences[upstreamID] {
+			var mutation struct {
+				StackDependencyReference *structs.StackDependencyReference `graphql:"stackDependenciesDeleteReference(id: $id)"`
+			}
+
+			if err := client.Mutate(ctx, "StackDependenciesDeleteReference", &mutation, map[string]any{"id": graphql.ID(reference.id)}); err != nil {
+				return errors.Wrapf(err, "could not remove reference %s of stack %s", reference.inputName, stackID)
+			}
+		}
+	}
+
+	for _, upstreamID := range changes.addDependencies {
+		var mutation struct {
+			StackDependency structs.StackDependency `graphql:"stackDependencyCreate(input: $input)"`
+		}
+
+		variables := map[string]any{
+			"input": structs.StackDependencyInput{
+				StackID:          toID(stackID),
+				DependsOnStackID: toID(upstreamID),
+			},
+		}
+
+		if err := client.Mutate(ctx, "StackDependencyCreate", &mutation, variables); err != nil {
+			return errors.Wrapf(err, "could not add dependency of stack %s on stack %s", stackID, upstreamID)
+		}
+
+		current[upstreamID] = stackUpstream{id: mutation.StackDependency.ID}
+	}
+
+	for _, upstreamID := range slices.Sorted(maps.Keys(changes.updateReferences)) {
+		for _, reference := range changes.updateReferences[upstreamID] {
+			var mutation struct {
+				StackDependencyReference structs.StackDependencyReference `graphql:"stackDependenciesUpdateReference(reference: $reference)"`
+			}
+
+			variables := map[string]any{
+				"reference": structs.StackDependencyReferenceUpdateInput{
+					ID:            toID(reference.id),
+					OutputName:    toString(reference.outputName),
+					InputName:     toString(reference.inputName),
+					Type:          toString("ENVIRONMENT_VARIABLE"),
+					TriggerAlways: toBool(reference.triggerAlways),
+				},
+			}
+
+			if err := client.Mutate(ctx, "StackDependenciesUpdateReference", &mutation, variables); err != nil {
+				return errors.Wrapf(err, "could not update reference %s of stack %s", reference.inputName, stackID)
+			}
+		}
+	}
+
+	for _, upstreamID := range slices.Sorted(maps.Keys(changes.addReferences)) {
+		for _, reference := range changes.addReferences[upstreamID] {
+			var mutation struct {
+				StackDependencyReference structs.StackDependencyReference `graphql:"stackDependenciesAddReference(stackDependencyID: $stackDependencyID, reference: $reference)"`
+			}
+
+			variables := map[string]any{
+				"stackDependencyID": toID(current[upstreamID].id),
+				"reference": structs.StackDependencyReferenceInput{
+					OutputName:    toString(reference.outputName),
+					InputName:     toString(reference.inputName),
+					Type:          toString("ENVIRONMENT_VARIABLE"),
+					TriggerAlways: toBool(reference.triggerAlways),
+				},
+			}
+
+			if err := client.Mutate(ctx, "StackDependenciesAddReference", &mutation, variables); err != nil {
+				return errors.Wrapf(err, "could not add reference %s of stack %s", reference.inputName, stackID)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,466 @@
+package spacelift
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	. "github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/testhelpers"
+)
+
+func TestDiffStackDependencies(t *testing.T) {
+	reference := func(id, outputName, inputName string, triggerAlways bool) stackUpstreamReference {
+		return stackUpstreamReference{id: id, outputName: outputName, inputName: inputName, triggerAlways: triggerAlways}
+	}
+
+	current := map[string]stackUpstream{
+		"db": {id: "dep-db", references: map[string]stackUpstreamReference{
+			"DB_URL":      reference("ref-url", "url", "DB_URL", false),
+			"DB_USER":     reference("ref-user", "user", "DB_USER", false),
+			"DB_PASSWORD": reference("ref-password", "password", "DB_PASSWORD", false),
+		}},
+		"legacy": {id: "dep-legacy", references: map[string]stackUpstreamReference{
+			"LEGACY": reference("ref-legacy", "legacy", "LEGACY", false),
+		}},
+	}
+
+	desired := map[string]stackUpstream{
+		"db": {references: map[string]stackUpstreamReference{
+			"DB_URL":      reference("", "url", "DB_URL", false),
+			"DB_PASSWORD": reference("", "password", "DB_PASSWORD", true),
+			"DB_NAME":     reference("", "name", "DB_NAME", false),
+		}},
+		"network": {references: map[string]stackUpstreamReference{
+			"TF_VAR_vpc_id": reference("", "vpc_id", "TF_VAR_vpc_id", false),
+		}},
+	}
+
+	changes := diffStackDependencies(current, desired)
+
+	want := stackDependenciesChanges{
+		removeDependencies: []string{"legacy"},
+		addDependencies:    []string{"network"},
+		removeReferences: map[string][]stackUpstreamReference{
+			"db": {reference("ref-user", "user", "DB_USER", false)},
+		},
+		updateReferences: map[string][]stackUpstreamReference{
+			"db": {reference("ref-password", "password", "DB_PASSWORD", true)},
+		},
+		addReferences: map[string][]stackUpstreamReference{
+			"db":      {reference("", "name", "DB_NAME", false)},
+			"network": {reference("", "vpc_id", "TF_VAR_vpc_id", false)},
+		},
+	}
+
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("got changes\n%+v\nwant\n%+v", changes, want)
+	}
+
+	if changes := diffStackDependencies(current, current); len(changes.removeDependencies)+len(changes.addDependencies)+len(changes.removeReferences)+len(changes.updateReferences)+len(changes.addReferences) != 0 {
+		t.Errorf("got changes between identical dependencies: %+v", changes)
+	}
+}
+
+// fakeStackDependencies is the upstream dependencies of stack app, as far as
+// a fake API server is concerned.
+type fakeStackDependencies struct {
+	nextID    int
+	upstreams map[string]*fakeStackUpstream
+	mutations []string
+}
+
+type fakeStackUpstream struct {
+	id         string
+	references map[string]*stackUpstreamReference
+}
+
+func (f *fakeStackDependencies) id(kind string) string {
+	f.nextID++
+	return fmt.Sprintf("%s-%d", kind, f.nextID)
+}
+
+// handlers answer the queries and mutations of the stack dependencies, the
+// same way the API would.
+func (f *fakeStackDependencies) handlers() map[string]GraphQLHandler {
+	upstreamByID := func(id string) *fakeStackUpstream {
+		for _, upstream := range f.upstreams {
+			if upstream.id == id {
+				return upstream
+			}
+		}
+		return nil
+	}
+
+	return map[string]GraphQLHandler{
+		"StackDependenciesRead": func(r GraphQLRequest) any {
+			if r.Variable("stackId") != "app" {
+				return map[string]any{"stack": nil}
+			}
+
+			var dependsOn []map[string]any
+			for upstreamID, upstream := range f.upstreams {
+				var references []map[string]any
+				for _, reference := range upstream.references {
+					references = append(references, map[string]any{
+						"id":            reference.id,
+						"outputName":    reference.outputName,
+						"inputName":     reference.inputName,
+						"triggerAlways": reference.triggerAlways,
+					})
+				}
+				dependsOn = append(dependsOn, stackDependency(upstream.id, upstreamID, references...))
+			}
+
+			return map[string]any{"stack": map[string]any{"dependsOn": dependsOn}}
+		},
+		"StackDependencyCreate": func(r GraphQLRequest) any {
+			var variables struct {
+				Input struct {
+					DependsOnStackID string `json:"dependsOnStackId"`
+				} `json:"input"`
+			}
+			if err := r.DecodeVariables(&variables); err != nil {
+				return err
+			}
+
+			upstream := &fakeStackUpstream{id: f.id("dep"), references: map[string]*stackUpstreamReference{}}
+			f.upstreams[variables.Input.DependsOnStackID] = upstream
+			f.mutations = append(f.mutations, "add "+variables.Input.DependsOnStackID)
+
+			return map[string]any{"stackDependencyCreate": map[string]any{"id": upstream.id}}
+		},
+		"StackDependencyDelete": func(r GraphQLRequest) any {
+			for upstreamID, upstream := range f.upstreams {
+				if upstream.id == r.Variable("id") {
+					delete(f.upstreams, upstreamID)
+					f.mutations = append(f.mutations, "remove "+upstreamID)
+				}
+			}
+
+			return map[string]any{"stackDependencyDelete": map[string]any{"id": r.Variable("id")}}
+		},
+		"StackDependenciesAddReference": func(r GraphQLRequest) any {
+			var variables struct {
+				StackDependencyID string             `json:"stackDependencyID"`
+				Reference         fakeReferenceInput `json:"reference"`
+			}
+			if err := r.DecodeVariables(&variables); err != nil {
+				return err
+			}
+
+			if upstream := upstreamByID(variables.StackDependencyID); upstream != nil {
+				upstream.references[variables.Reference.InputName] = &stackUpstreamReference{
+					id:            f.id("ref"),
+					outputName:    variables.Reference.OutputName,
+					inputName:     variables.Reference.InputName,
+					triggerAlways: variables.Reference.TriggerAlways,
+				}
+			}
+			f.mutations = append(f.mutations, "add reference "+variables.Reference.InputName)
+
+			return map[string]any{"stackDependenciesAddReference": map[string]any{"id": "ref"}}
+		},
+		"StackDependenciesUpdateReference": func(r GraphQLRequest) any {
+			var variables struct {
+				Reference fakeReferenceInput `json:"reference"`
+			}
+			if err := r.DecodeVariables(&variables); err != nil {
+				return err
+			}
+
+			for _, upstream := range f.upstreams {
+				for _, reference := range upstream.references {
+					if reference.id == variables.Reference.ID {
+						reference.outputName = variables.Reference.OutputName
+						reference.triggerAlways = variables.Reference.TriggerAlways
+					}
+				}
+			}
+			f.mutations = append(f.mutations, "update reference "+variables.Reference.InputName)
+
+			return map[string]any{"stackDependenciesUpdateReference": map[string]any{"id": variables.Reference.ID}}
+		},
+		"StackDependenciesDeleteReference": func(r GraphQLRequest) any {
+			for _, upstream := range f.upstreams {
+				for inputName, reference := range upstream.references {
+					if reference.id == r.Variable("id") {
+						delete(upstream.references, inputName)
+						f.mutations = append(f.mutations, "remove reference "+inputName)
+					}
+				}
+			}
+
+			return map[string]any{"stackDependenciesDeleteReference": map[string]any{"id": r.Variable("id")}}
+		},
+	}
+}
+
+type fakeReferenceInput struct {
+	ID            string `json:"id"`
+	OutputName    string `json:"outputName"`
+	InputName     string `json:"inputName"`
+	TriggerAlways bool   `json:"triggerAlways"`
+}
+
+func TestSetStackUpstreams(t *testing.T) {
+	fake := &fakeStackDependencies{upstreams: map[string]*fakeStackUpstream{
+		// Added in the UI, so unknown to Terraform.
+		"legacy": {id: "dep-legacy", references: map[string]*stackUpstreamReference{
+			"DB_URL": {id: "ref-legacy", outputName: "url", inputName: "DB_URL"},
+		}},
+		"network": {id: "dep-network", references: map[string]*stackUpstreamReference{
+			"VPC_ID": {id: "ref-vpc", outputName: "vpc_id", inputName: "VPC_ID"},
+			"SUBNET": {id: "ref-subnet", outputName: "subnet_id", inputName: "SUBNET"},
+		}},
+	}}
+
+	client := NewGraphQLServer(t, fake.handlers()).Client()
+
+	desired := map[string]stackUpstream{
+		"db": {references: map[string]stackUpstreamReference{
+			"DB_URL": {outputName: "url", inputName: "DB_URL"},
+		}},
+		"network": {references: map[string]stackUpstreamReference{
+			"VPC_ID": {outputName: "vpc_id", inputName: "VPC_ID", triggerAlways: true},
+		}},
+	}
+
+	if err := setStackUpstreams(context.Background(), client, "app", desired); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The input moves from one upstream stack to another, so it has to go
+	// before it is added again.
+	wantMutations := []string{
+		"remove legacy",
+		"remove reference SUBNET",
+		"add db",
+		"update reference VPC_ID",
+		"add reference DB_URL",
+	}
+	if !reflect.DeepEqual(fake.mutations, wantMutations) {
+		t.Errorf("got mutations %v, want %v", fake.mutations, wantMutations)
+	}
+
+	upstreams, err := getStackUpstreams(context.Background(), client, "app")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for upstreamID, upstream := range upstreams {
+		upstream.id = ""
+		for inputName, reference := range upstream.references {
+			reference.id = ""
+			upstream.references[inputName] = reference
+		}
+		upstreams[upstreamID] = upstream
+	}
+
+	if !reflect.DeepEqual(upstreams, desired) {
+		t.Errorf("got dependencies %+v, want %+v", upstreams, desired)
+	}
+
+	// Once applied, there is nothing left to do.
+	fake.mutations = nil
+	if err := setStackUpstreams(context.Background(), client, "app", desired); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fake.mutations) != 0 {
+		t.Errorf("got mutations %v for dependencies already in place", fake.mutations)
+	}
+
+	if err := setStackUpstreams(context.Background(), client, "missing", desired); err == nil || !strings.Contains(err.Error(), "stack missing not found") {
+		t.Errorf("got error %v for a missing stack", err)
+	}
+}
+
+func TestStackDependenciesValidateConfig(t *testing.T) {
+	r := &stackDependenciesResource{}
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(context.Background(), resource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(context.Background())
+	referenceType := stackDependenciesReferenceType.TerraformType(context.Background())
+
+	reference := func(dependsOnStackID any, inputName string) tftypes.Value {
+		return tftypes.NewValue(referenceType, map[string]tftypes.Value{
+			"depends_on_stack_id": tftypes.NewValue(tftypes.String, dependsOnStackID),
+			"output_name":         tftypes.NewValue(tftypes.String, "output"),
+			"input_name":          tftypes.NewValue(tftypes.String, inputName),
+			"trigger_always":      tftypes.NewValue(tftypes.Bool, nil),
+		})
+	}
+
+	config := func(dependsOnStackIDs any, references ...tftypes.Value) tftypes.Value {
+		return tftypes.NewValue(objectType, map[string]tftypes.Value{
+			"id":                   tftypes.NewValue(tftypes.String, nil),
+			"stack_id":             tftypes.NewValue(tftypes.String, "app"),
+			"depends_on_stack_ids": tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, dependsOnStackIDs),
+			"references":           tftypes.NewValue(tftypes.Set{ElementType: referenceType}, references),
+		})
+	}
+
+	stackIDs := func(values ...string) []tftypes.Value {
+		var elements []tftypes.Value
+		for _, value := range values {
+			elements = append(elements, tftypes.NewValue(tftypes.String, value))
+		}
+		return elements
+	}
+
+	for _, tc := range []struct {
+		name    string
+		config  tftypes.Value
+		wantErr string
+	}{
+		{name: "valid", config: config(stackIDs("db", "network"), reference("db", "DB_URL"), reference("network", "TF_VAR_vpc_id"))},
+		{name: "no references", config: config(stackIDs("db"))},
+		{name: "unknown stacks", config: config(tftypes.UnknownValue, reference("db", "DB_URL"))},
+		{name: "unknown stack of reference", config: config(stackIDs("db"), reference(tftypes.UnknownValue, "DB_URL"))},
+		{name: "stack not depended on", config: config(stackIDs("db"), reference("network", "VPC_ID")), wantErr: "Input VPC_ID references stack network, which is not in depends_on_stack_ids."},
+		{name: "duplicate input", config: config(stackIDs("db", "network"), reference("db", "ID"), reference("network", "ID")), wantErr: "Input ID is set by more than one reference."},
+		{name: "invalid input", config: config(stackIDs("db"), reference("db", "DB-URL")), wantErr: `"DB-URL" is not a valid environment variable name`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tc.config}}
+			var resp resource.ValidateConfigResponse
+
+			r.ValidateConfig(context.Background(), req, &resp)
+
+			if tc.wantErr == "" {
+				if resp.Diagnostics.HasError() {
+					t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+				}
+				return
+			}
+
+			var messages []string
+			for _, d := range resp.Diagnostics {
+				messages = append(messages, d.Summary()+": "+d.Detail())
+			}
+
+			if !resp.Diagnostics.HasError() || !strings.Contains(strings.Join(messages, "\n"), tc.wantErr) {
+				t.Fatalf("expected error %q, got %v", tc.wantErr, messages)
+			}
+		})
+	}
+}
+
+func TestStackDependenciesModifyPlan(t *testing.T) {
+	var schemaResp resource.SchemaResponse
+	(&stackDependenciesResource{}).Schema(context.Background(), resource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(context.Background())
+	referenceType := stackDependenciesReferenceType.TerraformType(context.Background())
+
+	value := func(stackID string, dependsOnStackIDs ...string) tftypes.Value {
+		if stackID == "" {
+			return tftypes.NewValue(objectType, nil)
+		}
+
+		var elements []tftypes.Value
+		for _, dependsOnStackID := range dependsOnStackIDs {
+			elements = append(elements, tftypes.NewValue(tftypes.String, dependsOnStackID))
+		}
+
+		return tftypes.NewValue(objectType, map[string]tftypes.Value{
+			"id":                   tftypes.NewValue(tftypes.String, stackID),
+			"stack_id":             tftypes.NewValue(tftypes.String, stackID),
+			"depends_on_stack_ids": tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, elements),
+			"references":           tftypes.NewValue(tftypes.Set{ElementType: referenceType}, nil),
+		})
+	}
+
+	for _, tc := range []struct {
+		name        string
+		earlier     [][2]tftypes.Value
+		wantErr     string
+		wantWarning string
+	}{
+		{
+			name:        "cycle through dependencies yet to be planned",
+			wantWarning: "unless one of the existing dependencies network -> db, db -> app is destroyed",
+		},
+		{
+			name: "cycle through kept dependencies",
+			earlier: [][2]tftypes.Value{
+				{value("network", "db"), value("network", "db")},
+				{value("db", "app", "account"), value("db", "app")},
+			},
+			wantErr: "app -> network -> db -> app",
+		},
+		{
+			name: "cycle broken by a destroyed dependency",
+			earlier: [][2]tftypes.Value{
+				{value("network", "db"), value("network", "db")},
+				{value("db", "app"), value("")},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// network already depends on db, which depends on app.
+			server := NewGraphQLServer(t, map[string]GraphQLHandler{
+				"StackDependenciesRead": func(r GraphQLRequest) any {
+					upstreams := map[string][]string{"network": {"db"}, "db": {"app"}}
+
+					var dependsOn []map[string]any
+					for _, upstreamID := range upstreams[r.Variable("stackId")] {
+						dependsOn = append(dependsOn, stackDependency(r.Variable("stackId")+"-"+upstreamID, upstreamID))
+					}
+					return map[string]any{"stack": map[string]any{"dependsOn": dependsOn}}
+				},
+			})
+
+			r := &stackDependenciesResource{client: server.Client()}
+
+			modifyPlan := func(state, plan tftypes.Value) diag.Diagnostics {
+				req := resource.ModifyPlanRequest{
+					State: tfsdk.State{Schema: schemaResp.Schema, Raw: state},
+					Plan:  tfsdk.Plan{Schema: schemaResp.Schema, Raw: plan},
+				}
+				resp := resource.ModifyPlanResponse{Plan: req.Plan}
+
+				r.ModifyPlan(context.Background(), req, &resp)
+
+				return resp.Diagnostics
+			}
+
+			for _, earlier := range tc.earlier {
+				if diags := modifyPlan(earlier[0], earlier[1]); diags.HasError() {
+					t.Fatalf("unexpected diagnostics planning %v: %v", earlier, diags)
+				}
+			}
+
+			diags := modifyPlan(value(""), value("app", "account", "network"))
+
+			switch {
+			case tc.wantErr != "":
+				if !diags.HasError() || !strings.Contains(diags[0].Detail(), tc.wantErr) {
+					t.Fatalf("expected error %q, got %v", tc.wantErr, diags)
+				}
+			case tc.wantWarning != "":
+				if diags.HasError() || diags.WarningsCount() != 1 || !strings.Contains(diags[0].Detail(), tc.wantWarning) {
+					t.Fatalf("expected warning %q, got %v", tc.wantWarning, diags)
+				}
+			case len(diags) > 0:
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+
+			// Only the dependency which isn't rejected counts towards later
+			// cycles.
+			planned := plannedStackDependenciesFor(r.client)
+			if !planned.certain(stackDependencyEdge{stackID: "app", dependsOnStackID: "account"}) {
+				t.Error("dependency on account was not added to those planned")
+			}
+			if got := planned.certain(stackDependencyEdge{stackID: "app", dependsOnStackID: "network"}); got != (tc.wantErr == "") {
+				t.Errorf("dependency on network planned: %t", got)
+			}
+		})
+	}
+}
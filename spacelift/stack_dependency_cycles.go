@@ -0,0 +1,252 @@
+package spacelift
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+)
+
+// stackDependencyEdge is a dependency of a stack on an upstream stack.
+type stackDependencyEdge struct {
+	stackID          string
+	dependsOnStackID string
+}
+
+// plannedStackDependencies collects the dependencies created, kept and
+// destroyed by the plan being made, so that a cycle closed by several new
+// dependencies together is caught too. Dependencies on stacks which don't
+// exist yet have unknown IDs at plan time, so they can't be taken into
+// account.
+//
+// Terraform plans resources in no particular order, so an existing dependency
+// which is neither kept nor destroyed may still be destroyed by a resource
+// planned later. Cycles going through such dependencies are only warned
+// about.
+type plannedStackDependencies struct {
+	mu      sync.Mutex
+	added   map[stackDependencyEdge]bool
+	kept    map[stackDependencyEdge]bool
+	removed map[stackDependencyEdge]bool
+}
+
+// stackDependencyPlans holds the planned dependencies of every client. A
+// provider is configured afresh for every plan, so they don't outlive it.
+var stackDependencyPlans = struct {
+	sync.Mutex
+	byClient map[*internal.Client]*plannedStackDependencies
+}{byClient: map[*internal.Client]*plannedStackDependencies{}}
+
+func plannedStackDependenciesFor(client *internal.Client) *plannedStackDependencies {
+	stackDependencyPlans.Lock()
+	defer stackDependencyPlans.Unlock()
+
+	planned, ok := stackDependencyPlans.byClient[client]
+	if !ok {
+		planned = &plannedStackDependencies{
+			added:   map[stackDependencyEdge]bool{},
+			kept:    map[stackDependencyEdge]bool{},
+			removed: map[stackDependencyEdge]bool{},
+		}
+		stackDependencyPlans.byClient[client] = planned
+	}
+
+	return planned
+}
+
+func (p *plannedStackDependencies) add(edge stackDependencyEdge) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.added[edge] = true
+	delete(p.removed, edge)
+}
+
+// keep records an existing dependency which the plan leaves in place.
+func (p *plannedStackDependencies) keep(edge stackDependencyEdge) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.kept[edge] = true
+}
+
+func (p *plannedStackDependencies) remove(edge stackDependencyEdge) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.removed[edge] = true
+	delete(p.added, edge)
+	delete(p.kept, edge)
+}
+
+// certain tells whether the dependency is there once the plan is applied,
+// whatever the resources which are yet to be planned do.
+func (p *plannedStackDependencies) certain(edge stackDependencyEdge) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.added[edge] || p.kept[edge]
+}
+
+// upstream returns the stacks the stack depends on once the plan is applied,
+// starting from the dependencies it has now.
+func (p *plannedStackDependencies) upstream(stackID string, existing []string) []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var upstream []string
+	for _, dependsOnStackID := range existing {
+		if !p.removed[stackDependencyEdge{stackID: stackID, dependsOnStackID: dependsOnStackID}] {
+			upstream = append(upstream, dependsOnStackID)
+		}
+	}
+
+	for edge := range p.added {
+		if edge.stackID == stackID && !slices.Contains(upstream, edge.dependsOnStackID) {
+			upstream = append(upstream, edge.dependsOnStackID)
+		}
+	}
+
+	slices.Sort(upstream)
+
+	return upstream
+}
+
+// findStackDependencyCycle looks for a path from the stack depended on back to
+// the stack depending on it, which the new dependency would close into a
+// cycle. It returns the cycle, starting and ending with the stack depending on
+// the other, or nil if there is none.
+func findStackDependencyCycle(ctx context.Context, upstream func(context.Context, string) ([]string, error), edge stackDependencyEdge) ([]string, error) {
+	if edge.stackID == edge.dependsOnStackID {
+		return []string{edge.stackID, edge.stackID}, nil
+	}
+
+	// Walk upstream breadth first, so that the shortest cycle is reported.
+	parents := map[string]string{edge.dependsOnStackID: ""}
+	queue := []string{edge.dependsOnStackID}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		dependsOn, err := upstream(ctx, current)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, next := range dependsOn {
+			if _, seen := parents[next]; seen {
+				continue
+			}
+			parents[next] = current
+
+			if next != edge.stackID {
+				queue = append(queue, next)
+				continue
+			}
+
+			var path []string
+			for stackID := next; stackID != ""; stackID = parents[stackID] {
+				path = append(path, stackID)
+			}
+			slices.Reverse(path)
+
+			return append([]string{edge.stackID}, path...), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// stackDependencyCycleCheck is the outcome of checking a new dependency for
+// cycles.
+type stackDependencyCycleCheck struct {
+	// detail describes the cycle the dependency would close, or is empty if
+	// there is none.
+	detail string
+
+	// certain is set if the cycle only goes through dependencies which are
+	// planned or kept by the plan. Otherwise the plan may yet break the
+	// cycle, and the dependency is added to those planned anyway.
+	certain bool
+}
+
+// checkPlannedStackDependency looks for a cycle the dependency would close,
+// and adds it to those planned unless the cycle is certain.
+func checkPlannedStackDependency(ctx context.Context, client *internal.Client, planned *plannedStackDependencies, edge stackDependencyEdge) (stackDependencyCycleCheck, error) {
+	existing := map[string][]string{}
+
+	upstream := func(ctx context.Context, stackID string) ([]string, error) {
+		upstreams, ok := existing[stackID]
+		if !ok {
+			current, err := getStackUpstreams(ctx, client, stackID)
+			if err != nil {
+				return nil, err
+			}
+			upstreams = slices.Sorted(maps.Keys(current))
+			existing[stackID] = upstreams
+		}
+
+		return planned.upstream(stackID, upstreams), nil
+	}
+
+	certainUpstream := func(ctx context.Context, stackID string) ([]string, error) {
+		upstreams, err := upstream(ctx, stackID)
+		if err != nil {
+			return nil, err
+		}
+
+		return slices.DeleteFunc(upstreams, func(dependsOnStackID string) bool {
+			return !planned.certain(stackDependencyEdge{stackID: stackID, dependsOnStackID: dependsOnStackID})
+		}), nil
+	}
+
+	cycle, err := findStackDependencyCycle(ctx, certainUpstream, edge)
+	if err != nil {
+		return stackDependencyCycleCheck{}, err
+	}
+
+	if cycle != nil {
+		return stackDependencyCycleCheck{detail: describeStackDependencyCycle(edge, cycle), certain: true}, nil
+	}
+
+	planned.add(edge)
+
+	if cycle, err = findStackDependencyCycle(ctx, upstream, edge); err != nil || cycle == nil {
+		return stackDependencyCycleCheck{}, err
+	}
+
+	var uncertain []string
+	for i := 1; i+1 < len(cycle); i++ {
+		if other := (stackDependencyEdge{stackID: cycle[i], dependsOnStackID: cycle[i+1]}); !planned.certain(other) {
+			uncertain = append(uncertain, formatStackDependencyCycle(cycle[i:i+2]))
+		}
+	}
+
+	return stackDependencyCycleCheck{
+		detail: fmt.Sprintf(
+			"%s, unless one of the existing dependencies %s is destroyed by a resource which is yet to be planned. If none is, applying the plan will fail.",
+			describeStackDependencyCycle(edge, cycle), strings.Join(uncertain, ", "),
+		),
+	}, nil
+}
+
+func describeStackDependencyCycle(edge stackDependencyEdge, cycle []string) string {
+	if edge.stackID == edge.dependsOnStackID {
+		return fmt.Sprintf("Stack %s can't depend on itself: %s", edge.stackID, formatStackDependencyCycle(cycle))
+	}
+
+	return fmt.Sprintf(
+		"Stack %s can't depend on stack %s, because %s already depends on %s, directly or indirectly: %s",
+		edge.stackID, edge.dependsOnStackID, edge.dependsOnStackID, edge.stackID, formatStackDependencyCycle(cycle),
+	)
+}
+
+// formatStackDependencyCycle renders a cycle as a -> b -> a.
+func formatStackDependencyCycle(cycle []string) string {
+	return strings.Join(cycle, " -> ")
+}
@@ -0,0 +1,192 @@
+package spacelift
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	. "github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/testhelpers"
+)
+
+func TestFindStackDependencyCycle(t *testing.T) {
+	dependencies := map[string][]string{
+		"app":     {"db", "network"},
+		"db":      {"network"},
+		"network": {"account"},
+		"cache":   {"app"},
+	}
+
+	upstream := func(_ context.Context, stackID string) ([]string, error) {
+		return dependencies[stackID], nil
+	}
+
+	for _, tc := range []struct {
+		stackID   string
+		dependsOn string
+		want      string
+	}{
+		{stackID: "cache", dependsOn: "db"},
+		{stackID: "account", dependsOn: "app", want: "account -> app -> network -> account"},
+		{stackID: "db", dependsOn: "cache", want: "db -> cache -> app -> db"},
+		{stackID: "app", dependsOn: "app", want: "app -> app"},
+	} {
+		cycle, err := findStackDependencyCycle(context.Background(), upstream, stackDependencyEdge{stackID: tc.stackID, dependsOnStackID: tc.dependsOn})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got := formatStackDependencyCycle(cycle); got != tc.want {
+			t.Errorf("%s -> %s: got cycle %q, want %q", tc.stackID, tc.dependsOn, got, tc.want)
+		}
+	}
+}
+
+func TestPlannedStackDependenciesUpstream(t *testing.T) {
+	planned := &plannedStackDependencies{
+		added:   map[stackDependencyEdge]bool{},
+		kept:    map[stackDependencyEdge]bool{},
+		removed: map[stackDependencyEdge]bool{},
+	}
+
+	planned.add(stackDependencyEdge{stackID: "app", dependsOnStackID: "cache"})
+	planned.remove(stackDependencyEdge{stackID: "app", dependsOnStackID: "db"})
+	planned.add(stackDependencyEdge{stackID: "db", dependsOnStackID: "network"})
+
+	if got := strings.Join(planned.upstream("app", []string{"db", "network"}), ","); got != "cache,network" {
+		t.Errorf("got upstream %s, want cache,network", got)
+	}
+}
+
+func TestStackDependencyModifyPlan(t *testing.T) {
+	var schemaResp resource.SchemaResponse
+	(&stackDependencyResource{}).Schema(context.Background(), resource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(context.Background())
+
+	value := func(edge []any) tftypes.Value {
+		if edge == nil {
+			return tftypes.NewValue(objectType, nil)
+		}
+
+		return tftypes.NewValue(objectType, map[string]tftypes.Value{
+			"id":                  tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			"stack_id":            tftypes.NewValue(tftypes.String, edge[0]),
+			"depends_on_stack_id": tftypes.NewValue(tftypes.String, edge[1]),
+		})
+	}
+
+	// step is a single resource being planned, going from the dependency in
+	// the state to the one in the plan. Either may be nil.
+	type step struct {
+		state, plan []any
+	}
+
+	for _, tc := range []struct {
+		name        string
+		earlier     []step
+		edge        step
+		wantErr     string
+		wantWarning string
+	}{
+		{name: "no cycle", edge: step{plan: []any{"app", "account"}}},
+		{name: "unknown stack", edge: step{plan: []any{"app", tftypes.UnknownValue}}},
+		{
+			name:    "self",
+			edge:    step{plan: []any{"app", "app"}},
+			wantErr: "Stack app can't depend on itself: app -> app",
+		},
+		{
+			name:        "cycle through dependencies yet to be planned",
+			edge:        step{plan: []any{"app", "network"}},
+			wantWarning: "app -> network -> db -> app, unless one of the existing dependencies network -> db, db -> app is destroyed",
+		},
+		{
+			name: "cycle through kept dependencies",
+			earlier: []step{
+				{state: []any{"network", "db"}, plan: []any{"network", "db"}},
+				{state: []any{"db", "app"}, plan: []any{"db", "app"}},
+			},
+			edge:    step{plan: []any{"app", "network"}},
+			wantErr: "app -> network -> db -> app",
+		},
+		{
+			name: "cycle through planned dependencies",
+			earlier: []step{
+				{plan: []any{"account", "app"}},
+			},
+			edge:    step{plan: []any{"app", "account"}},
+			wantErr: "app -> account -> app",
+		},
+		{
+			name: "reversed dependency",
+			earlier: []step{
+				{state: []any{"db", "app"}},
+			},
+			edge: step{plan: []any{"app", "db"}},
+		},
+		{
+			name:        "reversed dependency planned first",
+			edge:        step{plan: []any{"app", "db"}},
+			wantWarning: "unless one of the existing dependencies db -> app is destroyed",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// network already depends on db, which depends on app.
+			server := NewGraphQLServer(t, map[string]GraphQLHandler{
+				"StackDependenciesRead": func(r GraphQLRequest) any {
+					upstreams := map[string][]string{"network": {"db"}, "db": {"app"}}
+
+					var dependsOn []map[string]any
+					for _, upstreamID := range upstreams[r.Variable("stackId")] {
+						dependsOn = append(dependsOn, stackDependency(r.Variable("stackId")+"-"+upstreamID, upstreamID))
+					}
+					return map[string]any{"stack": map[string]any{"dependsOn": dependsOn}}
+				},
+			})
+
+			r := &stackDependencyResource{client: server.Client()}
+
+			modifyPlan := func(s step) resource.ModifyPlanResponse {
+				req := resource.ModifyPlanRequest{
+					State: tfsdk.State{Schema: schemaResp.Schema, Raw: value(s.state)},
+					Plan:  tfsdk.Plan{Schema: schemaResp.Schema, Raw: value(s.plan)},
+				}
+				resp := resource.ModifyPlanResponse{Plan: req.Plan}
+
+				r.ModifyPlan(context.Background(), req, &resp)
+
+				return resp
+			}
+
+			for _, earlier := range tc.earlier {
+				if resp := modifyPlan(earlier); resp.Diagnostics.HasError() {
+					t.Fatalf("unexpected diagnostics planning %v: %v", earlier, resp.Diagnostics)
+				}
+			}
+
+			diags := modifyPlan(tc.edge).Diagnostics
+
+			switch {
+			case tc.wantErr != "":
+				if !diags.HasError() || !strings.Contains(diags[0].Detail(), tc.wantErr) {
+					t.Fatalf("expected error %q, got %v", tc.wantErr, diags)
+				}
+
+				// A rejected dependency must not count towards later cycles.
+				planned := plannedStackDependenciesFor(r.client)
+				if planned.certain(stackDependencyEdge{stackID: tc.edge.plan[0].(string), dependsOnStackID: tc.edge.plan[1].(string)}) {
+					t.Error("rejected dependency was added to those planned")
+				}
+			case tc.wantWarning != "":
+				if diags.HasError() || diags.WarningsCount() != 1 || !strings.Contains(diags[0].Detail(), tc.wantWarning) {
+					t.Fatalf("expected warning %q, got %v", tc.wantWarning, diags)
+				}
+			case len(diags) > 0:
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+		})
+	}
+}
@@ -0,0 +1,272 @@
+package spacelift
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// stackGraphEdge is a dependency of a stack on an upstream stack.
+type stackGraphEdge struct {
+	ID         string
+	StackID    string
+	DependsOn  string
+	References []stackGraphReference
+}
+
+// stackGraphReference passes an output of the upstream stack to the
+// downstream one.
+type stackGraphReference struct {
+	OutputName    string
+	InputName     string
+	TriggerAlways bool
+}
+
+// stackGraph is a graph of stacks, with edges pointing from each stack to the
+// stacks it depends on.
+type stackGraph struct {
+	names     map[string]string
+	dependsOn map[string][]string
+	edges     []stackGraphEdge
+}
+
+func newStackGraph() *stackGraph {
+	return &stackGraph{
+		names:     map[string]string{},
+		dependsOn: map[string][]string{},
+	}
+}
+
+// addStack adds a stack to the graph. Adding it again only updates its name.
+func (g *stackGraph) addStack(stackID, name string) {
+	g.names[stackID] = name
+	if _, ok := g.dependsOn[stackID]; !ok {
+		g.dependsOn[stackID] = nil
+	}
+}
+
+// addEdge adds a dependency, and the stacks on both ends of it.
+func (g *stackGraph) addEdge(edge stackGraphEdge) {
+	for _, stackID := range []string{edge.StackID, edge.DependsOn} {
+		if _, ok := g.dependsOn[stackID]; !ok {
+			g.addStack(stackID, stackID)
+		}
+	}
+
+	g.dependsOn[edge.StackID] = append(g.dependsOn[edge.StackID], edge.DependsOn)
+	g.edges = append(g.edges, edge)
+}
+
+// stackIDs returns the IDs of all the stacks, sorted.
+func (g *stackGraph) stackIDs() []string {
+	return slices.Sorted(maps.Keys(g.dependsOn))
+}
+
+// sortedEdges returns the edges ordered by the stacks on their ends.
+func (g *stackGraph) sortedEdges() []stackGraphEdge {
+	edges := slices.Clone(g.edges)
+	slices.SortFunc(edges, func(a, b stackGraphEdge) int {
+		return strings.Compare(a.StackID+"\x00"+a.DependsOn, b.StackID+"\x00"+b.DependsOn)
+	})
+	return edges
+}
+
+// components returns the strongly connected components of the graph, using
+// Tarjan's algorithm. Components are listed so that every component comes
+// after the components it depends on, and the stacks within a component are
+// sorted.
+func (g *stackGraph) components() [][]string {
+	index := map[string]int{}
+	lowLink := map[string]int{}
+	onStack := map[string]bool{}
+	var stack []string
+	var components [][]string
+
+	var connect func(stackID string)
+	connect = func(stackID string) {
+		index[stackID] = len(index)
+		lowLink[stackID] = index[stackID]
+		stack = append(stack, stackID)
+		onStack[stackID] = true
+
+		for _, upstream := range slices.Sorted(slices.Values(g.dependsOn[stackID])) {
+			if _, visited := index[upstream]; !visited {
+				connect(upstream)
+				lowLink[stackID] = min(lowLink[stackID], lowLink[upstream])
+			} else if onStack[upstream] {
+				lowLink[stackID] = min(lowLink[stackID], index[upstream])
+			}
+		}
+
+		if lowLink[stackID] != index[stackID] {
+			return
+		}
+
+		var component []string
+		for {
+			member := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[member] = false
+			component = append(component, member)
+
+			if member == stackID {
+				break
+			}
+		}
+
+		slices.Sort(component)
+		components = append(components, component)
+	}
+
+	for _, stackID := range g.stackIDs() {
+		if _, visited := index[stackID]; !visited {
+			connect(stackID)
+		}
+	}
+
+	// Edges point upstream, so Tarjan's algorithm completes upstream
+	// components first.
+	return components
+}
+
+// cycles returns the components which form a cycle: those with more than one
+// stack, or a single stack depending on itself.
+func (g *stackGraph) cycles() [][]string {
+	var cycles [][]string
+	for _, component := range g.components() {
+		if len(component) > 1 || slices.Contains(g.dependsOn[component[0]], component[0]) {
+			cycles = append(cycles, component)
+		}
+	}
+	return cycles
+}
+
+// topologicalOrder returns the stacks ordered so that every stack comes after
+// the stacks it depends on. Stacks which can go in any order are sorted by
+// ID, and stacks in a cycle are listed together.
+func (g *stackGraph) topologicalOrder() []string {
+	components := g.components()
+
+	componentOf := map[string]int{}
+	for i, component := range components {
+		for _, stackID := range component {
+			componentOf[stackID] = i
+		}
+	}
+
+	// Count the upstream components of every component, and index the
+	// downstream ones.
+	pending := make([]map[int]bool, len(components))
+	downstream := make([]map[int]bool, len(components))
+	for i := range components {
+		pending[i] = map[int]bool{}
+		downstream[i] = map[int]bool{}
+	}
+
+	for stackID, upstreams := range g.dependsOn {
+		for _, upstream := range upstreams {
+			from, to := componentOf[upstream], componentOf[stackID]
+			if from != to {
+				pending[to][from] = true
+				downstream[from][to] = true
+			}
+		}
+	}
+
+	var ready []int
+	for i := range components {
+		if len(pending[i]) == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	var order []string
+	for len(ready) > 0 {
+		slices.SortFunc(ready, func(a, b int) int { return strings.Compare(components[a][0], components[b][0]) })
+
+		next := ready[0]
+		ready = ready[1:]
+		order = append(order, components[next]...)
+
+		for to := range downstream[next] {
+			delete(pending[to], next)
+			if len(pending[to]) == 0 {
+				ready = append(ready, to)
+			}
+		}
+	}
+
+	return order
+}
+
+// dot renders the graph in the Graphviz DOT language, with edges pointing from
+// upstream stacks to the stacks depending on them.
+func (g *stackGraph) dot() string {
+	var sb strings.Builder
+
+	sb.WriteString("digraph stacks {\n")
+	sb.WriteString("  rankdir=LR;\n")
+
+	for _, stackID := range g.stackIDs() {
+		fmt.Fprintf(&sb, "  %s [label=%s];\n", dotQuote(stackID), dotQuote(g.names[stackID]))
+	}
+
+	for _, edge := range g.sortedEdges() {
+		fmt.Fprintf(&sb, "  %s -> %s", dotQuote(edge.DependsOn), dotQuote(edge.StackID))
+		if label := edge.label(); label != "" {
+			fmt.Fprintf(&sb, " [label=%s]", dotQuote(label))
+		}
+		sb.WriteString(";\n")
+	}
+
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+// mermaid renders the graph as a Mermaid flowchart, with edges pointing from
+// upstream stacks to the stacks depending on them.
+func (g *stackGraph) mermaid() string {
+	var sb strings.Builder
+
+	sb.WriteString("flowchart LR\n")
+
+	// Stack IDs are not necessarily valid Mermaid node IDs, so number them.
+	nodeIDs := map[string]string{}
+	for i, stackID := range g.stackIDs() {
+		nodeIDs[stackID] = fmt.Sprintf("stack%d", i)
+		fmt.Fprintf(&sb, "  %s[%s]\n", nodeIDs[stackID], mermaidQuote(g.names[stackID]))
+	}
+
+	for _, edge := range g.sortedEdges() {
+		arrow := "-->"
+		if label := edge.label(); label != "" {
+			arrow = fmt.Sprintf("-->|%s|", mermaidQuote(label))
+		}
+		fmt.Fprintf(&sb, "  %s %s %s\n", nodeIDs[edge.DependsOn], arrow, nodeIDs[edge.StackID])
+	}
+
+	return sb.String()
+}
+
+// label describes the references of the edge, one per line.
+func (edge stackGraphEdge) label() string {
+	var lines []string
+	for _, reference := range edge.References {
+		lines = append(lines, fmt.Sprintf("%s → %s", reference.OutputName, reference.InputName))
+	}
+	slices.Sort(lines)
+	return strings.Join(lines, "\n")
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + strings.ReplaceAll(s, "\n", `\n`) + `"`
+}
+
+func mermaidQuote(s string) string {
+	s = strings.ReplaceAll(s, `"`, "#quot;")
+	return `"` + strings.ReplaceAll(s, "\n", "<br/>") + `"`
+}
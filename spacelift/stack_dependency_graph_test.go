@@ -0,0 +1,105 @@
+package spacelift
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func testStackGraph(edges ...string) *stackGraph {
+	graph := newStackGraph()
+	for _, edge := range edges {
+		stackID, dependsOn, _ := strings.Cut(edge, "->")
+		graph.addEdge(stackGraphEdge{StackID: stackID, DependsOn: dependsOn})
+	}
+	return graph
+}
+
+func TestStackGraphTopologicalOrder(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		graph      *stackGraph
+		wantOrder  string
+		wantCycles string
+	}{
+		{
+			name:      "chain",
+			graph:     testStackGraph("app->db", "db->network"),
+			wantOrder: "network,db,app",
+		},
+		{
+			name:      "diamond",
+			graph:     testStackGraph("app->db", "app->cache", "db->network", "cache->network"),
+			wantOrder: "network,cache,db,app",
+		},
+		{
+			name: "disconnected stacks",
+			graph: func() *stackGraph {
+				graph := testStackGraph("b->a")
+				graph.addStack("c", "C")
+				return graph
+			}(),
+			wantOrder: "a,b,c",
+		},
+		{
+			name:       "cycle",
+			graph:      testStackGraph("app->db", "db->cache", "cache->db", "db->network"),
+			wantOrder:  "network,cache,db,app",
+			wantCycles: "[cache db]",
+		},
+		{
+			name:       "self dependency",
+			graph:      testStackGraph("app->app", "app->db"),
+			wantOrder:  "db,app",
+			wantCycles: "[app]",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := strings.Join(tc.graph.topologicalOrder(), ","); got != tc.wantOrder {
+				t.Errorf("got order %s, want %s", got, tc.wantOrder)
+			}
+
+			var cycles []string
+			for _, cycle := range tc.graph.cycles() {
+				cycles = append(cycles, fmt.Sprint(cycle))
+			}
+			if got := strings.Join(cycles, ","); got != tc.wantCycles {
+				t.Errorf("got cycles %s, want %s", got, tc.wantCycles)
+			}
+		})
+	}
+}
+
+func TestStackGraphRendering(t *testing.T) {
+	graph := newStackGraph()
+	graph.addStack("network", `Core "network"`)
+	graph.addStack("app", "App")
+	graph.addEdge(stackGraphEdge{
+		StackID:   "app",
+		DependsOn: "network",
+		References: []stackGraphReference{
+			{OutputName: "vpc_id", InputName: "TF_VAR_vpc_id"},
+			{OutputName: "subnet_ids", InputName: "TF_VAR_subnet_ids"},
+		},
+	})
+
+	wantDOT := `digraph stacks {
+  rankdir=LR;
+  "app" [label="App"];
+  "network" [label="Core \"network\""];
+  "network" -> "app" [label="subnet_ids → TF_VAR_subnet_ids\nvpc_id → TF_VAR_vpc_id"];
+}
+`
+	if got := graph.dot(); got != wantDOT {
+		t.Errorf("unexpected DOT:\n%s", got)
+	}
+
+	wantMermaid := `flowchart LR
+  stack0["App"]
+  stack1["Core #quot;network#quot;"]
+  stack1 -->|"subnet_ids → TF_VAR_subnet_ids<br/>vpc_id → TF_VAR_vpc_id"| stack0
+`
+	if got := graph.mermaid(); got != wantMermaid {
+		t.Errorf("unexpected Mermaid:\n%s", got)
+	}
+}
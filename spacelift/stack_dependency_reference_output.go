@@ -0,0 +1,134 @@
+package spacelift
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/pkg/errors"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs"
+)
+
+const (
+	stackDependencyReferenceOnMissingOutputFail   = "FAIL"
+	stackDependencyReferenceOnMissingOutputWarn   = "WARN"
+	stackDependencyReferenceOnMissingOutputIgnore = "IGNORE"
+)
+
+// stackDependencyReferenceOutput is what is known about the output of the
+// upstream stack a reference passes on.
+type stackDependencyReferenceOutput struct {
+	// upstreamStackID is empty if the dependency does not exist.
+	upstreamStackID string
+	outputName      string
+
+	// output is nil if the upstream stack has no such output.
+	output *structs.StackOutput
+}
+
+// lookupStackDependencyReferenceOutput finds the output of the stack depended
+// on, using the same query as spacelift_stack_outputs.
+func lookupStackDependencyReferenceOutput(ctx context.Context, client *internal.Client, stackID, dependencyID, outputName string) (*stackDependencyReferenceOutput, error) {
+	var query struct {
+		Stack *struct {
+			Dependency *struct {
+				DependsOnStack struct {
+					ID string `graphql:"id"`
+				} `graphql:"dependsOnStack"`
+			} `graphql:"dependency(id: $dependencyId)"`
+		} `graphql:"stack(id: $stackId)"`
+	}
+
+	variables := map[string]any{
+		"stackId":      toID(stackID),
+		"dependencyId": toID(dependencyID),
+	}
+
+	if err := client.Query(ctx, "StackDependencyUpstreamRead", &query, variables); err != nil {
+		return nil, errors.Wrapf(err, "could not query for stack dependency %s", dependencyID)
+	}
+
+	result := &stackDependencyReferenceOutput{outputName: outputName}
+
+	if query.Stack == nil || query.Stack.Dependency == nil {
+		return result, nil
+	}
+
+	result.upstreamStackID = query.Stack.Dependency.DependsOnStack.ID
+
+	outputs, _, err := getStackOutputs(ctx, client, result.upstreamStackID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, output := range outputs {
+		if output.ID == outputName {
+			result.output = &output
+			break
+		}
+	}
+
+	return result, nil
+}
+
+func (o *stackDependencyReferenceOutput) missing() bool {
+	return o.upstreamStackID != "" && o.output == nil
+}
+
+func (o *stackDependencyReferenceOutput) sensitive() bool {
+	return o.output != nil && o.output.Sensitive
+}
+
+func (o *stackDependencyReferenceOutput) missingMessage() string {
+	return fmt.Sprintf("stack %s has no output named %q", o.upstreamStackID, o.outputName)
+}
+
+func (o *stackDependencyReferenceOutput) sensitiveMessage() string {
+	return fmt.Sprintf("output %q of stack %s is sensitive", o.outputName, o.upstreamStackID)
+}
+
+// check fails if the output is missing and it has to exist, and logs what is
+// worth a warning otherwise. Warnings can't be reported at plan time, so
+// diagnostics returns them after the reference is applied.
+func (o *stackDependencyReferenceOutput) check(ctx context.Context, onMissingOutput string) error {
+	if o.missing() {
+		switch onMissingOutput {
+		case stackDependencyReferenceOnMissingOutputFail:
+			return errors.Errorf("%s; set on_missing_output to %s if it is yet to be added", o.missingMessage(), stackDependencyReferenceOnMissingOutputWarn)
+		case stackDependencyReferenceOnMissingOutputWarn:
+			tflog.Warn(ctx, o.missingMessage())
+		}
+	}
+
+	if o.sensitive() {
+		tflog.Warn(ctx, o.sensitiveMessage())
+	}
+
+	return nil
+}
+
+// diagnostics returns the warnings about the output.
+func (o *stackDependencyReferenceOutput) diagnostics(onMissingOutput string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if o.missing() && onMissingOutput == stackDependencyReferenceOnMissingOutputWarn {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  o.missingMessage(),
+			Detail:   "The reference is only resolved once the output exists, so runs of the downstream stack won't get the input until then.",
+		})
+	}
+
+	if o.sensitive() {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  o.sensitiveMessage(),
+			Detail:   "Its value is passed to the downstream stack, so make sure the input is treated as a secret there.",
+		})
+	}
+
+	return diags
+}
@@ -0,0 +1,205 @@
+package spacelift
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/pkg/errors"
+
+	. "github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/testhelpers"
+)
+
+// stackOutput is an output of a stack as returned by the API.
+func stackOutput(id string, sensitive bool) map[string]any {
+	return map[string]any{"id": id, "description": "", "sensitive": sensitive}
+}
+
+// stackOutputsHandlers answer lookups of the upstream stack of dependency
+// dep-id of stack app, and of the outputs of stack infra.
+func stackOutputsHandlers(t *testing.T, outputs ...map[string]any) map[string]GraphQLHandler {
+	return map[string]GraphQLHandler{
+		"StackDependencyUpstreamRead": func(r GraphQLRequest) any {
+			if r.Variable("stackId") != "app" || r.Variable("dependencyId") != "dep-id" {
+				return map[string]any{"stack": nil}
+			}
+			return map[string]any{"stack": map[string]any{"dependency": map[string]any{"dependsOnStack": map[string]any{"id": "infra"}}}}
+		},
+		"StackOutputsRead": func(r GraphQLRequest) any {
+			if id := r.Variable("id"); id != "infra" {
+				t.Errorf("looked up outputs of stack %q", id)
+			}
+			return map[string]any{"stack": map[string]any{"outputs": outputs}}
+		},
+	}
+}
+
+func TestStackDependencyReferenceInputNameValidation(t *testing.T) {
+	for _, tc := range []struct {
+		inputName string
+		wantErr   string
+	}{
+		{inputName: "APP_DB_URL"},
+		{inputName: "_private"},
+		{inputName: "TF_VAR_db_url"},
+		{inputName: "TF_VAR_db-url"},
+		{inputName: "TF_VAR_", wantErr: `"TF_VAR_" does not name a valid Terraform variable`},
+		{inputName: "TF_VAR_1st", wantErr: "does not name a valid Terraform variable"},
+		{inputName: "DB-URL", wantErr: `"DB-URL" is not a valid environment variable name`},
+		{inputName: "1ST", wantErr: "is not a valid environment variable name"},
+		{inputName: "DB URL", wantErr: "is not a valid environment variable name"},
+	} {
+		diags := resourceStackDependencyReference().Validate(terraform.NewResourceConfigRaw(map[string]any{
+			"stack_dependency_id": "app/dep-id",
+			"output_name":         "db_url",
+			"input_name":          tc.inputName,
+		}))
+
+		var messages []string
+		for _, d := range diags {
+			messages = append(messages, d.Summary+": "+d.Detail)
+		}
+
+		switch {
+		case tc.wantErr == "" && diags.HasError():
+			t.Errorf("%s: unexpected errors %v", tc.inputName, messages)
+		case tc.wantErr != "" && !strings.Contains(strings.Join(messages, "\n"), tc.wantErr):
+			t.Errorf("%s: expected error %q, got %v", tc.inputName, tc.wantErr, messages)
+		}
+	}
+}
+
+func TestStackDependencyReferenceOutputCheck(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		dependencyID    string
+		outputName      string
+		onMissingOutput string
+		wantSensitive   string
+		wantErr         string
+	}{
+		{name: "existing output", dependencyID: "app/dep-id", outputName: "db_url", wantSensitive: "false"},
+		{name: "sensitive output", dependencyID: "app/dep-id", outputName: "db_password", wantSensitive: "true"},
+		{name: "missing output", dependencyID: "app/dep-id", outputName: "db_uri", wantSensitive: "false"},
+		{name: "missing output ignored", dependencyID: "app/dep-id", outputName: "db_uri", onMissingOutput: "IGNORE", wantSensitive: "false"},
+		{name: "missing output failing", dependencyID: "app/dep-id", outputName: "db_uri", onMissingOutput: "FAIL", wantErr: `stack infra has no output named "db_uri"`},
+		{name: "missing dependency", dependencyID: "app/other-id", outputName: "db_uri", onMissingOutput: "FAIL", wantSensitive: "false"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := NewGraphQLServer(t, stackOutputsHandlers(t, stackOutput("db_url", false), stackOutput("db_password", true)))
+
+			config := map[string]any{
+				"stack_dependency_id": tc.dependencyID,
+				"output_name":         tc.outputName,
+				"input_name":          "TF_VAR_db",
+			}
+			if tc.onMissingOutput != "" {
+				config["on_missing_output"] = tc.onMissingOutput
+			}
+
+			diff, err := resourceStackDependencyReference().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), server.Client())
+
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := diff.Attributes["output_sensitive"].New; got != tc.wantSensitive {
+				t.Errorf("got output_sensitive %q, want %q", got, tc.wantSensitive)
+			}
+		})
+	}
+}
+
+func TestStackDependencyReferenceOutputDiagnostics(t *testing.T) {
+	client := NewGraphQLServer(t, stackOutputsHandlers(t, stackOutput("db_password", true))).Client()
+
+	for _, tc := range []struct {
+		outputName      string
+		onMissingOutput string
+		want            []string
+	}{
+		{outputName: "db_password", onMissingOutput: "WARN", want: []string{`output "db_password" of stack infra is sensitive`}},
+		{outputName: "db_url", onMissingOutput: "WARN", want: []string{`stack infra has no output named "db_url"`}},
+		{outputName: "db_url", onMissingOutput: "IGNORE"},
+	} {
+		output, err := lookupStackDependencyReferenceOutput(context.Background(), client, "app", "dep-id", tc.outputName)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var got []string
+		for _, d := range output.diagnostics(tc.onMissingOutput) {
+			got = append(got, d.Summary)
+		}
+
+		if strings.Join(got, "\n") != strings.Join(tc.want, "\n") {
+			t.Errorf("%s with %s: got warnings %v, want %v", tc.outputName, tc.onMissingOutput, got, tc.want)
+		}
+	}
+}
+
+func TestStackDependencyReferenceOutputCheckWithoutOutputs(t *testing.T) {
+	handlers := stackOutputsHandlers(t)
+	handlers["StackOutputsRead"] = func(GraphQLRequest) any {
+		return errors.New("forbidden")
+	}
+
+	server := NewGraphQLServer(t, handlers)
+
+	diff, err := resourceStackDependencyReference().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(map[string]any{
+		"stack_dependency_id": "app/dep-id",
+		"output_name":         "db_url",
+		"input_name":          "TF_VAR_db",
+		"on_missing_output":   "FAIL",
+	}), server.Client())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !diff.Attributes["output_sensitive"].NewComputed {
+		t.Errorf("output_sensitive is %q, want it to be computed once applied", diff.Attributes["output_sensitive"].New)
+	}
+}
+
+func TestStackDependencyReferenceRead(t *testing.T) {
+	// Outputs are only checked when the reference changes, so reading it
+	// doesn't look them up.
+	server := NewGraphQLServer(t, map[string]GraphQLHandler{
+		"StackDependenciesReferenceRead": func(GraphQLRequest) any {
+			return map[string]any{"stack": map[string]any{"dependency": map[string]any{
+				"dependsOnStack": map[string]any{"id": "infra"},
+				"reference": map[string]any{
+					"id":            "ref-id",
+					"outputName":    "db_password",
+					"inputName":     "TF_VAR_db_password",
+					"type":          "ENVIRONMENT_VARIABLE",
+					"triggerAlways": false,
+				},
+			}}}
+		},
+	})
+
+	d := resourceStackDependencyReference().TestResourceData()
+	d.SetId("app/dep-id/ref-id")
+	d.Set("output_sensitive", true)
+
+	if diags := resourceStackDependencyReferenceRead(context.Background(), d, server.Client()); len(diags) > 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	if !d.Get("output_sensitive").(bool) {
+		t.Error("output_sensitive lost its value")
+	}
+
+	if got := d.Get("input_name"); got != "TF_VAR_db_password" {
+		t.Errorf("got input_name %q, want TF_VAR_db_password", got)
+	}
+}
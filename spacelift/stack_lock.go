@@ -0,0 +1,89 @@
+package spacelift
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/shurcooL/graphql"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal/structs"
+)
+
+// getStackLock returns the lock held on the stack, or nil if the stack does
+// not exist.
+func getStackLock(ctx context.Context, client *internal.Client, stackID string) (*structs.StackLock, error) {
+	var query struct {
+		Stack *structs.StackLock `graphql:"stack(id: $id)"`
+	}
+
+	variables := map[string]any{"id": graphql.ID(stackID)}
+
+	if err := client.Query(ctx, "StackLockRead", &query, variables); err != nil {
+		return nil, errors.Wrap(err, "could not query for stack lock")
+	}
+
+	return query.Stack, nil
+}
+
+// getViewerID returns the ID of the API user, which is who holds the locks
+// the provider takes.
+func getViewerID(ctx context.Context, client *internal.Client) (string, error) {
+	var query struct {
+		Viewer *struct {
+			ID string `graphql:"id"`
+		} `graphql:"viewer"`
+	}
+
+	if err := client.Query(ctx, "ViewerRead", &query, map[string]any{}); err != nil {
+		return "", errors.Wrap(err, "could not query for the API user")
+	}
+
+	if query.Viewer == nil {
+		return "", errors.New("could not identify the API user")
+	}
+
+	return query.Viewer.ID, nil
+}
+
+// lockStack locks the stack for exclusive use by the API user, optionally
+// leaving a note explaining why.
+func lockStack(ctx context.Context, client *internal.Client, stackID, note string) error {
+	var mutation struct {
+		StackLock struct {
+			ID string `graphql:"id"`
+		} `graphql:"stackLock(id: $id, note: $note)"`
+	}
+
+	variables := map[string]any{
+		"id":   graphql.ID(stackID),
+		"note": (*graphql.String)(nil),
+	}
+
+	if note != "" {
+		variables["note"] = graphql.NewString(graphql.String(note))
+	}
+
+	if err := client.Mutate(ctx, "StackLock", &mutation, variables); err != nil {
+		return errors.Wrapf(internal.FromSpaceliftError(err), "could not lock stack %s", stackID)
+	}
+
+	return nil
+}
+
+// unlockStack releases the lock the API user holds on the stack.
+func unlockStack(ctx context.Context, client *internal.Client, stackID string) error {
+	var mutation struct {
+		StackUnlock struct {
+			ID string `graphql:"id"`
+		} `graphql:"stackUnlock(id: $id)"`
+	}
+
+	variables := map[string]any{"id": graphql.ID(stackID)}
+
+	if err := client.Mutate(ctx, "StackUnlock", &mutation, variables); err != nil {
+		return errors.Wrapf(internal.FromSpaceliftError(err), "could not unlock stack %s", stackID)
+	}
+
+	return nil
+}
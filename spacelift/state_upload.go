@@ -0,0 +1,167 @@
+package spacelift
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/spacelift-io/terraform-provider-spacelift/spacelift/internal"
+)
+
+var (
+	// stateUploadAttempts is the number of times an interrupted state upload
+	// is attempted before giving up.
+	stateUploadAttempts = 5
+
+	// stateUploadBackoff is the delay before the first retry of a state
+	// upload, doubled after each subsequent failure.
+	stateUploadBackoff = time.Second
+)
+
+// stateChecksums holds the digests of a state file: MD5 is what the object
+// storage verifies the upload against, SHA-256 is what we keep in the state.
+type stateChecksums struct {
+	md5    []byte
+	sha256 string
+}
+
+// uploadStateFile streams the state file at path to Spacelift and returns the
+// ID of the uploaded object along with the SHA-256 checksum of its content.
+func uploadStateFile(ctx context.Context, client *internal.Client, path string) (objectID, checksum string, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", "", errors.Wrap(err, "could not open state file")
+	}
+	defer file.Close()
+
+	return uploadState(ctx, client, file)
+}
+
+// uploadStateContent uploads state held in memory to Spacelift and returns
+// the ID of the uploaded object along with the SHA-256 checksum of its content.
+func uploadStateContent(ctx context.Context, client *internal.Client, content string) (objectID, checksum string, err error) {
+	return uploadState(ctx, client, strings.NewReader(content))
+}
+
+func uploadState(ctx context.Context, client *internal.Client, body io.ReadSeeker) (string, string, error) {
+	size, sums, err := checksumState(body)
+	if err != nil {
+		return "", "", err
+	}
+
+	var mutation struct {
+		StateUploadURL struct {
+			ObjectID string `graphql:"objectId"`
+			URL      string `graphql:"url"`
+		} `graphql:"stateUploadUrl"`
+	}
+
+	if err := client.Mutate(ctx, "StateUploadUrl", &mutation, nil); err != nil {
+		return "", "", errors.Wrap(err, "could not generate state upload URL")
+	}
+
+	if err := putState(ctx, mutation.StateUploadURL.URL, body, size, sums.md5); err != nil {
+		return "", "", err
+	}
+
+	return mutation.StateUploadURL.ObjectID, sums.sha256, nil
+}
+
+// checksumState reads body once to compute its size and digests, then
+// rewinds it so it can be uploaded.
+func checksumState(body io.ReadSeeker) (int64, stateChecksums, error) {
+	md5Hash, sha256Hash := md5.New(), sha256.New()
+
+	size, err := io.Copy(io.MultiWriter(md5Hash, sha256Hash), body)
+	if err != nil {
+		return 0, stateChecksums{}, errors.Wrap(err, "could not read the state")
+	}
+
+	if _, err := body.Seek(0, io.SeekStart); err != nil {
+		return 0, stateChecksums{}, errors.Wrap(err, "could not rewind the state")
+	}
+
+	return size, stateChecksums{
+		md5:    md5Hash.Sum(nil),
+		sha256: hex.EncodeToString(sha256Hash.Sum(nil)),
+	}, nil
+}
+
+// putState uploads body to the pre-signed URL, asking the object storage to
+// verify it against the MD5 checksum. Network errors and server-side failures
+// are retried with exponential backoff, rewinding body before each attempt.
+func putState(ctx context.Context, url string, body io.ReadSeeker, size int64, checksum []byte) error {
+	backoff := stateUploadBackoff
+
+	var lastErr error
+	for attempt := 1; attempt <= stateUploadAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return errors.Wrap(ctx.Err(), "state upload interrupted")
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		retryable, err := putStateOnce(ctx, url, body, size, checksum)
+		if err == nil {
+			return nil
+		}
+
+		if !retryable {
+			return err
+		}
+
+		lastErr = err
+	}
+
+	return errors.Wrapf(lastErr, "state upload failed after %d attempts", stateUploadAttempts)
+}
+
+func putStateOnce(ctx context.Context, url string, body io.ReadSeeker, size int64, checksum []byte) (retryable bool, err error) {
+	if _, err := body.Seek(0, io.SeekStart); err != nil {
+		return false, errors.Wrap(err, "could not rewind the state")
+	}
+
+	// Wrapping the body hides its Seek method from the HTTP client, which
+	// would otherwise try to buffer or sniff it.
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, url, io.NopCloser(body))
+	if err != nil {
+		return false, errors.Wrap(err, "could not create state upload request")
+	}
+	request.ContentLength = size
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Content-MD5", base64.StdEncoding.EncodeToString(checksum))
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return ctx.Err() == nil, errors.Wrap(err, "could not upload the state to remote URL")
+	}
+	defer response.Body.Close()
+
+	// Drain the body so the connection can be reused by the next attempt.
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	switch {
+	case response.StatusCode/100 == 2:
+		return false, nil
+	case response.StatusCode == http.StatusBadRequest:
+		// Object storage rejects a body which does not match its Content-MD5
+		// with a 400, which retrying the same content would not fix.
+		return false, errors.Errorf("state upload rejected with HTTP status code %d, the uploaded content may not match its checksum", response.StatusCode)
+	case response.StatusCode == http.StatusRequestTimeout, response.StatusCode == http.StatusTooManyRequests, response.StatusCode >= 500:
+		return true, errors.Errorf("unexpected HTTP status code when uploading the state: %d", response.StatusCode)
+	default:
+		return false, errors.Errorf("unexpected HTTP status code when uploading the state: %d", response.StatusCode)
+	}
+}
@@ -0,0 +1,107 @@
+package spacelift
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPutState(t *testing.T) {
+	stateUploadBackoff = time.Millisecond
+
+	const content = `{"version":4,"serial":1,"lineage":"test"}`
+
+	for _, tc := range []struct {
+		name         string
+		statuses     []int
+		wantAttempts int
+		wantErr      string
+	}{
+		{
+			name:         "success",
+			statuses:     []int{http.StatusOK},
+			wantAttempts: 1,
+		},
+		{
+			name:         "retried after server errors",
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK},
+			wantAttempts: 3,
+		},
+		{
+			name:         "checksum mismatch is not retried",
+			statuses:     []int{http.StatusBadRequest},
+			wantAttempts: 1,
+			wantErr:      "may not match its checksum",
+		},
+		{
+			name:         "gives up after all attempts",
+			statuses:     []int{http.StatusBadGateway},
+			wantAttempts: stateUploadAttempts,
+			wantErr:      "failed after 5 attempts",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			body := strings.NewReader(content)
+
+			size, sums, err := checksumState(body)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var attempts int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+
+				if got, _ := io.ReadAll(r.Body); string(got) != content {
+					t.Errorf("attempt %d uploaded %q, want %q", attempts, got, content)
+				}
+
+				if got, want := r.Header.Get("Content-MD5"), base64.StdEncoding.EncodeToString(sums.md5); got != want {
+					t.Errorf("Content-MD5 = %q, want %q", got, want)
+				}
+
+				w.WriteHeader(tc.statuses[min(attempts, len(tc.statuses))-1])
+			}))
+			defer server.Close()
+
+			err = putState(context.Background(), server.URL, body, size, sums.md5)
+
+			if attempts != tc.wantAttempts {
+				t.Errorf("got %d attempts, want %d", attempts, tc.wantAttempts)
+			}
+
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+				t.Errorf("got error %v, want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestChecksumState(t *testing.T) {
+	body := strings.NewReader("{}")
+
+	size, sums, err := checksumState(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if size != 2 {
+		t.Errorf("size = %d, want 2", size)
+	}
+
+	if want := "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"; sums.sha256 != want {
+		t.Errorf("sha256 = %s, want %s", sums.sha256, want)
+	}
+
+	if rest, _ := io.ReadAll(body); string(rest) != "{}" {
+		t.Errorf("body was not rewound, remaining content %q", rest)
+	}
+}